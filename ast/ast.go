@@ -7,25 +7,47 @@ import (
 	"github.com/VadimZvf/golang/ast_node_block"
 	"github.com/VadimZvf/golang/ast_node_boolean"
 	"github.com/VadimZvf/golang/ast_node_call_expression"
+	"github.com/VadimZvf/golang/ast_node_defer"
+	"github.com/VadimZvf/golang/ast_node_enum"
+	"github.com/VadimZvf/golang/ast_node_for_of"
 	"github.com/VadimZvf/golang/ast_node_function"
+	"github.com/VadimZvf/golang/ast_node_index"
+	"github.com/VadimZvf/golang/ast_node_loop_control"
+	"github.com/VadimZvf/golang/ast_node_match"
 	"github.com/VadimZvf/golang/ast_node_number"
 	"github.com/VadimZvf/golang/ast_node_parenthesized_expression"
 	"github.com/VadimZvf/golang/ast_node_read_property"
 	"github.com/VadimZvf/golang/ast_node_reference"
 	"github.com/VadimZvf/golang/ast_node_return"
+	"github.com/VadimZvf/golang/ast_node_spawn"
 	"github.com/VadimZvf/golang/ast_node_string"
+	"github.com/VadimZvf/golang/ast_node_unary_expression"
 	"github.com/VadimZvf/golang/ast_node_variable_declaration"
+	"github.com/VadimZvf/golang/ast_node_while"
+	"github.com/VadimZvf/golang/ast_node_yield"
 	"github.com/VadimZvf/golang/ast_token_stream"
 	"github.com/VadimZvf/golang/parser_error"
 	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_async"
+	"github.com/VadimZvf/golang/token_await"
 	"github.com/VadimZvf/golang/token_boolean"
+	"github.com/VadimZvf/golang/token_break"
+	"github.com/VadimZvf/golang/token_continue"
+	"github.com/VadimZvf/golang/token_defer"
+	"github.com/VadimZvf/golang/token_enum"
+	"github.com/VadimZvf/golang/token_for"
 	"github.com/VadimZvf/golang/token_function_declaration"
 	"github.com/VadimZvf/golang/token_keyword"
+	"github.com/VadimZvf/golang/token_match"
 	"github.com/VadimZvf/golang/token_number"
 	"github.com/VadimZvf/golang/token_read_property"
 	"github.com/VadimZvf/golang/token_return"
+	"github.com/VadimZvf/golang/token_spawn"
 	"github.com/VadimZvf/golang/token_string"
+	"github.com/VadimZvf/golang/token_typeof"
 	"github.com/VadimZvf/golang/token_variable_declaration"
+	"github.com/VadimZvf/golang/token_while"
+	"github.com/VadimZvf/golang/token_yield"
 )
 
 func CreateAST(tokens []token.Token) (*ast_node.ASTNode, error) {
@@ -83,12 +105,39 @@ func (ctx context) Process(stream ast_node.ITokenStream, currentCtx ast_node.IAS
 	case token_keyword.KEY_WORD:
 		return ast_node_reference.ReferenceProcessor(stream, ctx, leftNode)
 
-	case token_function_declaration.FUNCTION_DECLARATION:
+	case token_function_declaration.FUNCTION_DECLARATION, token_function_declaration.DECORATOR, token_async.ASYNC_DECLARATION:
 		return ast_node_function.FunctionProcessor(stream, ctx, leftNode)
 
 	case token_return.RETURN_DECLARATION:
 		return ast_node_return.ReturnProcessor(stream, ctx, leftNode)
 
+	case token_typeof.TYPEOF, token_await.AWAIT, token.TILDE:
+		return ast_node_unary_expression.UnaryExpressionProcessor(stream, ctx, leftNode)
+
+	case token_match.MATCH_DECLARATION:
+		return ast_node_match.MatchProcessor(stream, ctx, leftNode)
+
+	case token_while.WHILE_DECLARATION:
+		return ast_node_while.WhileProcessor(stream, ctx, leftNode)
+
+	case token_for.FOR_DECLARATION:
+		return ast_node_for_of.ForOfProcessor(stream, ctx, leftNode)
+
+	case token_break.BREAK_DECLARATION, token_continue.CONTINUE_DECLARATION:
+		return ast_node_loop_control.LoopControlProcessor(stream, ctx, leftNode)
+
+	case token_yield.YIELD_DECLARATION:
+		return ast_node_yield.YieldProcessor(stream, ctx, leftNode)
+
+	case token_spawn.SPAWN_DECLARATION:
+		return ast_node_spawn.SpawnProcessor(stream, ctx, leftNode)
+
+	case token_enum.ENUM_DECLARATION:
+		return ast_node_enum.EnumProcessor(stream, ctx, leftNode)
+
+	case token_defer.DEFER_DECLARATION:
+		return ast_node_defer.DeferProcessor(stream, ctx, leftNode)
+
 	case token.OPEN_BLOCK:
 		return ast_node_block.BlockProcessor(stream, ctx, leftNode)
 
@@ -99,12 +148,18 @@ func (ctx context) Process(stream ast_node.ITokenStream, currentCtx ast_node.IAS
 
 		return ast_node_call_expression.CallExpressionProcessor(stream, ctx, leftNode)
 
-	case token.ADD, token.SUBTRACT, token.SLASH, token.ASTERISK:
+	case token.ADD, token.SUBTRACT, token.SLASH, token.ASTERISK, token.PERCENT,
+		token.EQUAL, token.NOT_EQUAL, token.GREATER, token.LESS, token.GREATER_OR_EQUAL, token.LESS_OR_EQUAL,
+		token.VERTICAL_BAR, token.CARET, token.AMPERSAND, token.SHIFT_LEFT, token.SHIFT_RIGHT, token.UNSIGNED_SHIFT_RIGHT,
+		token.PIPE:
 		return ast_node_binary_expression.BinaryExpressionProcessor(stream, ctx, leftNode)
 
 	case token_read_property.READ_PROPERTY:
 		return ast_node_read_property.ReadPropertyProcessor(stream, ctx, leftNode)
 
+	case token.OPEN_INDEX:
+		return ast_node_index.IndexProcessor(stream, ctx, leftNode)
+
 	case token.ASSIGNMENT:
 		return ast_node_assignment.AssignmentProcessor(stream, ctx, leftNode)
 
@@ -2,19 +2,38 @@ package ast_node
 
 import (
 	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_await"
 	"github.com/VadimZvf/golang/token_boolean"
+	"github.com/VadimZvf/golang/token_break"
+	"github.com/VadimZvf/golang/token_continue"
+	"github.com/VadimZvf/golang/token_defer"
+	"github.com/VadimZvf/golang/token_enum"
+	"github.com/VadimZvf/golang/token_for"
 	"github.com/VadimZvf/golang/token_function_declaration"
 	"github.com/VadimZvf/golang/token_keyword"
+	"github.com/VadimZvf/golang/token_match"
 	"github.com/VadimZvf/golang/token_number"
 	"github.com/VadimZvf/golang/token_read_property"
 	"github.com/VadimZvf/golang/token_return"
+	"github.com/VadimZvf/golang/token_spawn"
 	"github.com/VadimZvf/golang/token_string"
+	"github.com/VadimZvf/golang/token_typeof"
 	"github.com/VadimZvf/golang/token_variable_declaration"
+	"github.com/VadimZvf/golang/token_while"
+	"github.com/VadimZvf/golang/token_yield"
 )
 
+// Place of variable in runtime scopes. Depth is count of scopes between usage and declaration
+type VariableSlot struct {
+	Depth int
+	Index int
+}
+
 type ASTNodeParam struct {
 	Name  string
 	Value string
+	// Slot of variable, which is declared or used by name. Set by resolver
+	Slot *VariableSlot
 	// Debug data
 	StartPosition int
 	EndPosition   int
@@ -26,6 +45,9 @@ type ASTNode struct {
 	Params    []ASTNodeParam
 	Arguments []*ASTNode
 	Body      []*ASTNode
+	// Count of variables in scope, which is created by node, like function call or loop iteration.
+	// Set by resolver
+	ScopeSize int
 	// Debug data
 	StartPosition int
 	EndPosition   int
@@ -45,15 +67,39 @@ const AST_NODE_CODE_BOOLEAN = "BOOLEAN"
 const AST_NODE_CODE_REFERENCE = "REFERENCE"
 const AST_NODE_CODE_FUNCTION = "FUNCTION"
 const AST_NODE_CODE_RETURN = "RETURN"
+const AST_NODE_CODE_UNARY_EXPRESSION = "UNARY_EXPRESSION"
+const AST_NODE_CODE_MATCH = "MATCH"
+const AST_NODE_CODE_MATCH_ARM = "MATCH_ARM"
+const AST_NODE_CODE_MATCH_GUARD = "MATCH_GUARD"
+const AST_NODE_CODE_MATCH_WILDCARD = "MATCH_WILDCARD"
+const AST_NODE_CODE_MATCH_BINDING = "MATCH_BINDING"
+const AST_NODE_CODE_WHILE = "WHILE"
+const AST_NODE_CODE_FOR_OF = "FOR_OF"
+const AST_NODE_CODE_BREAK = "BREAK"
+const AST_NODE_CODE_CONTINUE = "CONTINUE"
+const AST_NODE_CODE_YIELD = "YIELD"
+const AST_NODE_CODE_SPAWN = "SPAWN"
+const AST_NODE_CODE_INDEX = "INDEX"
+const AST_NODE_CODE_ENUM = "ENUM"
+const AST_NODE_CODE_ENUM_MEMBER = "ENUM_MEMBER"
+const AST_NODE_CODE_NAMED_ARGUMENT = "NAMED_ARGUMENT"
+const AST_NODE_CODE_DEFER = "DEFER"
 
 const AST_PARAM_VARIABLE_NAME = "VARIABLE_NAME"
 const AST_PARAM_FUNCTION_NAME = "FUNCTION_NAME"
 const AST_PARAM_FUNCTION_ARGUMENT_NAME = "FUNCTION_ARGUMENT_NAME"
+const AST_PARAM_FUNCTION_GENERATOR = "FUNCTION_GENERATOR"
+const AST_PARAM_FUNCTION_ASYNC = "FUNCTION_ASYNC"
+const AST_PARAM_FUNCTION_DECORATOR = "FUNCTION_DECORATOR"
+const AST_PARAM_FUNCTION_ARGUMENT_TYPE = "FUNCTION_ARGUMENT_TYPE"
+const AST_PARAM_FUNCTION_RETURN_TYPE = "FUNCTION_RETURN_TYPE"
+const AST_PARAM_VARIABLE_TYPE = "VARIABLE_TYPE"
 const AST_PARAM_NUMBER_VALUE = "NUMBER_VALUE"
 const AST_PARAM_STRING_VALUE = "STRING_VALUE"
 const AST_PARAM_BOOLEAN_VALUE = "BOOLEAN_VALUE"
 const AST_PARAM_BINARY_EXPRESSION_TYPE = "BINARY_EXPRESSION_TYPE"
 const AST_PARAM_PROPERTY_NAME = "PROPERTY_NAME"
+const AST_PARAM_UNARY_EXPRESSION_TYPE = "UNARY_EXPRESSION_TYPE"
 
 func GetVariableNameParam(node *ASTNode) *ASTNodeParam {
 	return GetParam(node, AST_PARAM_VARIABLE_NAME)
@@ -79,10 +125,134 @@ func GetBinaryExpressionTypeParam(node *ASTNode) *ASTNodeParam {
 	return GetParam(node, AST_PARAM_BINARY_EXPRESSION_TYPE)
 }
 
-func GetParam(node *ASTNode, paramCode string) *ASTNodeParam {
+func GetUnaryExpressionTypeParam(node *ASTNode) *ASTNodeParam {
+	return GetParam(node, AST_PARAM_UNARY_EXPRESSION_TYPE)
+}
+
+func GetVariableTypeParam(node *ASTNode) *ASTNodeParam {
+	return GetParam(node, AST_PARAM_VARIABLE_TYPE)
+}
+
+func GetFunctionReturnTypeParam(node *ASTNode) *ASTNodeParam {
+	return GetParam(node, AST_PARAM_FUNCTION_RETURN_TYPE)
+}
+
+// Argument of function declaration, type is nil for argument without annotation
+type FunctionArgument struct {
+	Name ASTNodeParam
+	Type *ASTNodeParam
+}
+
+func GetFunctionArguments(node *ASTNode) []FunctionArgument {
+	var arguments = []FunctionArgument{}
+
 	for _, param := range node.Params {
-		if param.Name == paramCode {
-			return &param
+		if param.Name == AST_PARAM_FUNCTION_ARGUMENT_NAME {
+			arguments = append(arguments, FunctionArgument{Name: param})
+		}
+
+		// Annotation follows name of its argument
+		if param.Name == AST_PARAM_FUNCTION_ARGUMENT_TYPE && len(arguments) > 0 {
+			var argumentType = param
+			arguments[len(arguments)-1].Type = &argumentType
+		}
+	}
+
+	return arguments
+}
+
+func IsNamedArgument(node *ASTNode) bool {
+	return node.Code == AST_NODE_CODE_NAMED_ARGUMENT
+}
+
+// Returns node of call argument, which is bound to function argument with given index and name.
+// Positional arguments go before named ones. Nil, when argument is not passed
+func FindCallArgument(callNode *ASTNode, index int, name string) *ASTNode {
+	if index < len(callNode.Arguments) && !IsNamedArgument(callNode.Arguments[index]) {
+		return callNode.Arguments[index]
+	}
+
+	for _, argumentNode := range callNode.Arguments {
+		if !IsNamedArgument(argumentNode) {
+			continue
+		}
+
+		var nameParam = GetVariableNameParam(argumentNode)
+
+		if nameParam != nil && nameParam.Value == name {
+			return argumentNode
+		}
+	}
+
+	return nil
+}
+
+func IsPipe(node *ASTNode) bool {
+	if node.Code != AST_NODE_CODE_BINARY_EXPRESSION {
+		return false
+	}
+
+	var expressionType = GetBinaryExpressionTypeParam(node)
+
+	return expressionType != nil && expressionType.Value == "|>"
+}
+
+// Turns pipe "x |> f(a)" into call "f(x, a)", and "x |> f" into "f(x)".
+// Call has position of right side, so errors point to stage of pipe
+func CreatePipeCall(node *ASTNode) *ASTNode {
+	var value = node.Body[0]
+	var stage = node.Body[1]
+
+	if stage.Code == AST_NODE_CODE_CALL_EXPRESSION {
+		return &ASTNode{
+			Code:          AST_NODE_CODE_CALL_EXPRESSION,
+			Body:          stage.Body,
+			Arguments:     append([]*ASTNode{value}, stage.Arguments...),
+			StartPosition: stage.StartPosition,
+			EndPosition:   stage.EndPosition,
+		}
+	}
+
+	return &ASTNode{
+		Code:          AST_NODE_CODE_CALL_EXPRESSION,
+		Body:          []*ASTNode{stage},
+		Arguments:     []*ASTNode{value},
+		StartPosition: stage.StartPosition,
+		EndPosition:   stage.EndPosition,
+	}
+}
+
+func IsGeneratorFunction(node *ASTNode) bool {
+	return GetParam(node, AST_PARAM_FUNCTION_GENERATOR) != nil
+}
+
+func IsAsyncFunction(node *ASTNode) bool {
+	return GetParam(node, AST_PARAM_FUNCTION_ASYNC) != nil
+}
+
+// Decorators in order of declaration, the last one is applied first
+func GetFunctionDecorators(node *ASTNode) []ASTNodeParam {
+	var decorators = []ASTNodeParam{}
+
+	for _, param := range node.Params {
+		if param.Name == AST_PARAM_FUNCTION_DECORATOR {
+			decorators = append(decorators, param)
+		}
+	}
+
+	return decorators
+}
+
+// Function declarations are hoisted, except decorated ones, which are defined in place
+func IsHoisted(node *ASTNode) bool {
+	return node.Code == AST_NODE_CODE_FUNCTION && len(GetFunctionDecorators(node)) == 0
+}
+
+// Returns param of node itself, not a copy
+func GetParam(node *ASTNode, paramCode string) *ASTNodeParam {
+	for index := range node.Params {
+		if node.Params[index].Name == paramCode {
+			return &node.Params[index]
 		}
 	}
 
@@ -93,14 +263,27 @@ func CreateNode(currentToken token.Token) ASTNode {
 	switch currentToken.Code {
 	case token_variable_declaration.VARIABLE_DECLARAION:
 		var variableName = token_variable_declaration.GetVariableNameParam(currentToken)
+		var params = []ASTNodeParam{{
+			Name:          AST_PARAM_VARIABLE_NAME,
+			Value:         variableName.Value,
+			StartPosition: variableName.StartPosition,
+			EndPosition:   variableName.EndPosition,
+		}}
+
+		var variableType, isTyped = token_variable_declaration.GetVariableTypeParam(currentToken)
+
+		if isTyped {
+			params = append(params, ASTNodeParam{
+				Name:          AST_PARAM_VARIABLE_TYPE,
+				Value:         variableType.Value,
+				StartPosition: variableType.StartPosition,
+				EndPosition:   variableType.EndPosition,
+			})
+		}
+
 		return ASTNode{
-			Code: AST_NODE_CODE_VARIABLE_DECLARATION,
-			Params: []ASTNodeParam{{
-				Name:          AST_PARAM_VARIABLE_NAME,
-				Value:         variableName.Value,
-				StartPosition: variableName.StartPosition,
-				EndPosition:   variableName.EndPosition,
-			}},
+			Code:   AST_NODE_CODE_VARIABLE_DECLARATION,
+			Params: params,
 			// Debug data
 			StartPosition: currentToken.StartPosition,
 			EndPosition:   currentToken.EndPosition,
@@ -114,7 +297,10 @@ func CreateNode(currentToken token.Token) ASTNode {
 			EndPosition:   currentToken.EndPosition,
 		}
 
-	case token.ADD, token.SUBTRACT, token.SLASH, token.ASTERISK:
+	case token.ADD, token.SUBTRACT, token.SLASH, token.ASTERISK, token.PERCENT,
+		token.EQUAL, token.NOT_EQUAL, token.GREATER, token.LESS, token.GREATER_OR_EQUAL, token.LESS_OR_EQUAL,
+		token.VERTICAL_BAR, token.CARET, token.AMPERSAND, token.SHIFT_LEFT, token.SHIFT_RIGHT, token.UNSIGNED_SHIFT_RIGHT,
+		token.PIPE:
 		return ASTNode{
 			Code: AST_NODE_CODE_BINARY_EXPRESSION,
 			Params: []ASTNodeParam{{
@@ -219,6 +405,33 @@ func CreateNode(currentToken token.Token) ASTNode {
 					EndPosition:   funcParam.EndPosition,
 				})
 			}
+
+			if funcParam.Name == token_function_declaration.FUNCTION_ARGUMENT_TYPE_PARAM {
+				params = append(params, ASTNodeParam{
+					Name:          AST_PARAM_FUNCTION_ARGUMENT_TYPE,
+					Value:         funcParam.Value,
+					StartPosition: funcParam.StartPosition,
+					EndPosition:   funcParam.EndPosition,
+				})
+			}
+
+			if funcParam.Name == token_function_declaration.FUNCTION_RETURN_TYPE_PARAM {
+				params = append(params, ASTNodeParam{
+					Name:          AST_PARAM_FUNCTION_RETURN_TYPE,
+					Value:         funcParam.Value,
+					StartPosition: funcParam.StartPosition,
+					EndPosition:   funcParam.EndPosition,
+				})
+			}
+
+			if funcParam.Name == token_function_declaration.FUNCTION_GENERATOR_PARAM {
+				params = append(params, ASTNodeParam{
+					Name:          AST_PARAM_FUNCTION_GENERATOR,
+					Value:         funcParam.Value,
+					StartPosition: funcParam.StartPosition,
+					EndPosition:   funcParam.EndPosition,
+				})
+			}
 		}
 
 		return ASTNode{
@@ -229,6 +442,92 @@ func CreateNode(currentToken token.Token) ASTNode {
 			EndPosition:   currentToken.EndPosition,
 		}
 
+	case token_typeof.TYPEOF, token_await.AWAIT, token.TILDE:
+		return ASTNode{
+			Code: AST_NODE_CODE_UNARY_EXPRESSION,
+			Params: []ASTNodeParam{{
+				Name:          AST_PARAM_UNARY_EXPRESSION_TYPE,
+				Value:         currentToken.Value,
+				StartPosition: currentToken.StartPosition,
+				EndPosition:   currentToken.EndPosition,
+			}},
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_match.MATCH_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_MATCH,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_while.WHILE_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_WHILE,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_for.FOR_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_FOR_OF,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_break.BREAK_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_BREAK,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_continue.CONTINUE_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_CONTINUE,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_yield.YIELD_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_YIELD,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_spawn.SPAWN_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_SPAWN,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_defer.DEFER_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_DEFER,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+	case token_enum.ENUM_DECLARATION:
+		return ASTNode{
+			Code: AST_NODE_CODE_ENUM,
+			// Debug data
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
 	case token.OPEN_EXPRESSION:
 		return ASTNode{
 			Code: AST_NODE_CODE_PARENTHESIZED_EXPRESSION,
@@ -262,24 +561,66 @@ type IASTNodeProcessingContext interface {
 
 type ASTNodeProcessor = func(stream ITokenStream, context IASTNodeProcessingContext, leftNode *ASTNode) (resultNodes []*ASTNode, err error)
 
-var arithmeticTokens = []string{
-	token.ADD, token.SUBTRACT, token.SLASH, token.ASTERISK,
+var binaryExpressionTokens = []string{
+	token.ADD, token.SUBTRACT, token.SLASH, token.ASTERISK, token.PERCENT,
+	token.EQUAL, token.NOT_EQUAL, token.GREATER, token.LESS, token.GREATER_OR_EQUAL, token.LESS_OR_EQUAL,
+	token.VERTICAL_BAR, token.CARET, token.AMPERSAND, token.SHIFT_LEFT, token.SHIFT_RIGHT, token.UNSIGNED_SHIFT_RIGHT,
+	token.PIPE,
 }
 
-func IsNextArithmeticToken(stream ITokenStream) bool {
+// Higher value binds stronger. Operators with same precedence are left associative
+var binaryExpressionPrecedence = map[string]int{
+	"|>":  1,
+	"|":   2,
+	"^":   3,
+	"&":   4,
+	"==":  5,
+	"!=":  5,
+	">":   6,
+	"<":   6,
+	">=":  6,
+	"<=":  6,
+	"<<":  7,
+	">>":  7,
+	">>>": 7,
+	"+":   8,
+	"-":   8,
+	"*":   9,
+	"/":   9,
+	"%":   9,
+}
+
+func GetBinaryExpressionPrecedence(expressionType string) int {
+	return binaryExpressionPrecedence[expressionType]
+}
+
+func IsNextBinaryExpressionToken(stream ITokenStream) bool {
 	var nextToken, isEnd = stream.LookNext()
 
 	if isEnd {
 		return false
 	}
 
-	if contains(arithmeticTokens, nextToken.Code) {
+	if contains(binaryExpressionTokens, nextToken.Code) {
 		return true
 	}
 
 	return false
 }
 
+// Binary operator, property read or index, which can follow literal value
+func IsNextLiteralContinuationToken(stream ITokenStream) bool {
+	var nextToken, isEnd = stream.LookNext()
+
+	if isEnd {
+		return false
+	}
+
+	return contains(binaryExpressionTokens, nextToken.Code) ||
+		nextToken.Code == token_read_property.READ_PROPERTY ||
+		nextToken.Code == token.OPEN_INDEX
+}
+
 func IsNextExpressionToken(stream ITokenStream) bool {
 	var nextToken, isEnd = stream.LookNext()
 
@@ -287,7 +628,7 @@ func IsNextExpressionToken(stream ITokenStream) bool {
 		return false
 	}
 
-	if contains(arithmeticTokens, nextToken.Code) {
+	if contains(binaryExpressionTokens, nextToken.Code) {
 		return true
 	}
 
@@ -299,6 +640,10 @@ func IsNextExpressionToken(stream ITokenStream) bool {
 		return true
 	}
 
+	if nextToken.Code == token.OPEN_INDEX {
+		return true
+	}
+
 	if nextToken.Code == token.ASSIGNMENT {
 		return true
 	}
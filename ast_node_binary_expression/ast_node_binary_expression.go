@@ -46,10 +46,33 @@ func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingCo
 		}
 	}
 
-	ast_node.AppendNodes(&binaryNode, []*ast_node.ASTNode{
+	return []*ast_node.ASTNode{combineByPrecedence(leftNode, &binaryNode, rightNodes[0])}, nil
+}
+
+// Right node is parsed greedy, so it contains whole rest of expression.
+// Rotate the tree, while right node operator binds weaker or the same,
+// to respect operators precedence and left associativity
+func combineByPrecedence(leftNode *ast_node.ASTNode, binaryNode *ast_node.ASTNode, rightNode *ast_node.ASTNode) *ast_node.ASTNode {
+	if rightNode.Code == ast_node.AST_NODE_CODE_BINARY_EXPRESSION && getPrecedence(rightNode) <= getPrecedence(binaryNode) {
+		rightNode.Body[0] = combineByPrecedence(leftNode, binaryNode, rightNode.Body[0])
+
+		return rightNode
+	}
+
+	ast_node.AppendNodes(binaryNode, []*ast_node.ASTNode{
 		leftNode,
-		rightNodes[0],
+		rightNode,
 	})
 
-	return []*ast_node.ASTNode{&binaryNode}, nil
+	return binaryNode
+}
+
+func getPrecedence(node *ast_node.ASTNode) int {
+	var expressionType = ast_node.GetBinaryExpressionTypeParam(node)
+
+	if expressionType == nil {
+		return 0
+	}
+
+	return ast_node.GetBinaryExpressionPrecedence(expressionType.Value)
 }
@@ -28,5 +28,11 @@ func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingCo
 
 	var booleanNode = ast_node.CreateNode(currentToken)
 
-	return []*ast_node.ASTNode{&booleanNode}, nil
+	if !ast_node.IsNextBinaryExpressionToken(stream) {
+		return []*ast_node.ASTNode{&booleanNode}, nil
+	}
+
+	stream.MoveNext()
+
+	return context.Process(stream, context, &booleanNode)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/VadimZvf/golang/ast_node"
 	"github.com/VadimZvf/golang/parser_error"
 	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_keyword"
 )
 
 var CallExpressionProcessor ast_node.ASTNodeProcessor = process
@@ -72,25 +73,35 @@ func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingCo
 func processCallExpressionArguments(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext) ([]*ast_node.ASTNode, error) {
 	var currentToken, isEnd = stream.Look()
 	var arguments = []*ast_node.ASTNode{}
+	var hasNamedArgument = false
 
 	for !isEnd && currentToken.Code != token.CLOSE_EXPRESSION {
-		var argument, argumentParsingError = context.Process(stream, context, nil)
+		if isNamedArgument(stream) {
+			var namedArgument, namedArgumentParsingError = processNamedArgument(stream, context)
 
-		if argumentParsingError != nil {
-			return arguments, parser_error.MergeParserErrors(parser_error.ParserError{
-				Message: "Something wrong at call argument processing",
-			}, argumentParsingError)
-		}
+			if namedArgumentParsingError != nil {
+				return arguments, namedArgumentParsingError
+			}
 
-		if len(argument) != 1 {
-			return arguments, parser_error.ParserError{
-				Message:       "Parsing error. Argument declaration should have only one value node. But received: " + fmt.Sprint(len(argument)),
-				StartPosition: currentToken.StartPosition,
-				EndPosition:   currentToken.EndPosition,
+			hasNamedArgument = true
+			arguments = append(arguments, namedArgument)
+		} else {
+			if hasNamedArgument {
+				return arguments, parser_error.ParserError{
+					Message:       "Positional argument should be before named arguments",
+					StartPosition: currentToken.StartPosition,
+					EndPosition:   currentToken.EndPosition,
+				}
 			}
-		}
 
-		arguments = append(arguments, argument[0])
+			var argument, argumentParsingError = processPositionalArgument(stream, context)
+
+			if argumentParsingError != nil {
+				return arguments, argumentParsingError
+			}
+
+			arguments = append(arguments, argument)
+		}
 
 		stream.MoveNext()
 		currentToken, isEnd = stream.Look()
@@ -111,3 +122,77 @@ func processCallExpressionArguments(stream ast_node.ITokenStream, context ast_no
 
 	return arguments, nil
 }
+
+// Named argument starts with name and colon, like "admin: true"
+func isNamedArgument(stream ast_node.ITokenStream) bool {
+	var currentToken, _ = stream.Look()
+	var nextToken, isEnd = stream.LookNext()
+
+	return currentToken.Code == token_keyword.KEY_WORD && !isEnd && nextToken.Code == token.COLON
+}
+
+func processNamedArgument(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext) (*ast_node.ASTNode, error) {
+	var nameToken, _ = stream.Look()
+
+	var argumentNode = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_NAMED_ARGUMENT,
+		Params: []ast_node.ASTNodeParam{{
+			Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+			Value:         nameToken.Value,
+			StartPosition: nameToken.StartPosition,
+			EndPosition:   nameToken.EndPosition,
+		}},
+		// Debug data
+		StartPosition: nameToken.StartPosition,
+		EndPosition:   nameToken.EndPosition,
+	}
+
+	// Skip name and colon
+	stream.MoveNext()
+	stream.MoveNext()
+
+	var valueToken, isEndAtValue = stream.Look()
+
+	if isEndAtValue || valueToken.Code == token.COMMA || valueToken.Code == token.CLOSE_EXPRESSION {
+		return &argumentNode, parser_error.ParserError{
+			Message:       "Named argument should have value",
+			StartPosition: nameToken.StartPosition,
+			EndPosition:   valueToken.EndPosition,
+		}
+	}
+
+	var value, valueParsingError = processPositionalArgument(stream, context)
+
+	if valueParsingError != nil {
+		return &argumentNode, valueParsingError
+	}
+
+	ast_node.AppendNode(&argumentNode, value)
+
+	// Value processing stops at its last token
+	var lastToken, _ = stream.Look()
+	argumentNode.EndPosition = lastToken.EndPosition
+
+	return &argumentNode, nil
+}
+
+func processPositionalArgument(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext) (*ast_node.ASTNode, error) {
+	var currentToken, _ = stream.Look()
+	var argument, argumentParsingError = context.Process(stream, context, nil)
+
+	if argumentParsingError != nil {
+		return nil, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Something wrong at call argument processing",
+		}, argumentParsingError)
+	}
+
+	if len(argument) != 1 {
+		return nil, parser_error.ParserError{
+			Message:       "Parsing error. Argument declaration should have only one value node. But received: " + fmt.Sprint(len(argument)),
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	return argument[0], nil
+}
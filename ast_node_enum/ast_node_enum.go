@@ -0,0 +1,166 @@
+package ast_node_enum
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_keyword"
+	"github.com/VadimZvf/golang/token_number"
+	"github.com/VadimZvf/golang/token_string"
+)
+
+var EnumProcessor ast_node.ASTNodeProcessor = process
+
+// Reads "enum Color { Red, Green, Blue = 10 }". Members without value
+// get value of previous member plus one, first member gets zero
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode != nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Left node not supported for enum node",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at enum processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var enumNode = ast_node.CreateNode(currentToken)
+	stream.MoveNext()
+
+	var nameToken, isEndAtName = stream.Look()
+
+	if isEndAtName || nameToken.Code != token_keyword.KEY_WORD {
+		return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+			Message:       "Enum should have name",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   nameToken.EndPosition,
+		}
+	}
+
+	enumNode.Params = []ast_node.ASTNodeParam{{
+		Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+		Value:         nameToken.Value,
+		StartPosition: nameToken.StartPosition,
+		EndPosition:   nameToken.EndPosition,
+	}}
+
+	stream.MoveNext()
+
+	var openBlockToken, isEndAtOpenBlock = stream.Look()
+
+	if isEndAtOpenBlock || openBlockToken.Code != token.OPEN_BLOCK {
+		return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+			Message:       "Enum should have \"{\" after name",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   openBlockToken.EndPosition,
+		}
+	}
+
+	stream.MoveNext()
+
+	var memberNames = map[string]bool{}
+	var isPreviousString = false
+
+	for {
+		var memberToken, isEndAtMember = stream.Look()
+
+		if isEndAtMember {
+			return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+				Message:       "Unexpected file end. Enum should be closed by \"}\"",
+				StartPosition: currentToken.StartPosition,
+				EndPosition:   memberToken.EndPosition,
+			}
+		}
+
+		if memberToken.Code == token.CLOSE_BLOCK {
+			enumNode.EndPosition = memberToken.EndPosition
+			return []*ast_node.ASTNode{&enumNode}, nil
+		}
+
+		if memberToken.Code != token_keyword.KEY_WORD {
+			return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+				Message:       "Enum member should have name",
+				StartPosition: memberToken.StartPosition,
+				EndPosition:   memberToken.EndPosition,
+			}
+		}
+
+		if memberNames[memberToken.Value] {
+			return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+				Message:       "Duplicate enum member: " + memberToken.Value,
+				StartPosition: memberToken.StartPosition,
+				EndPosition:   memberToken.EndPosition,
+			}
+		}
+
+		memberNames[memberToken.Value] = true
+
+		var memberNode = ast_node.ASTNode{
+			Code: ast_node.AST_NODE_CODE_ENUM_MEMBER,
+			Params: []ast_node.ASTNodeParam{{
+				Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+				Value:         memberToken.Value,
+				StartPosition: memberToken.StartPosition,
+				EndPosition:   memberToken.EndPosition,
+			}},
+			// Debug data
+			StartPosition: memberToken.StartPosition,
+			EndPosition:   memberToken.EndPosition,
+		}
+
+		stream.MoveNext()
+
+		var nextToken, _ = stream.Look()
+
+		if nextToken.Code == token.ASSIGNMENT {
+			stream.MoveNext()
+
+			var valueToken, isEndAtValue = stream.Look()
+
+			if isEndAtValue || (valueToken.Code != token_number.NUMBER && valueToken.Code != token_string.STRING) {
+				return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+					Message:       "Enum member value should be number or string",
+					StartPosition: memberToken.StartPosition,
+					EndPosition:   valueToken.EndPosition,
+				}
+			}
+
+			var valueNode = ast_node.CreateNode(valueToken)
+			ast_node.AppendNode(&memberNode, &valueNode)
+			memberNode.EndPosition = valueToken.EndPosition
+			isPreviousString = valueToken.Code == token_string.STRING
+
+			stream.MoveNext()
+			nextToken, _ = stream.Look()
+		} else if isPreviousString {
+			// Next value cannot be counted after string
+			return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+				Message:       "Enum member after string value should have value",
+				StartPosition: memberToken.StartPosition,
+				EndPosition:   memberToken.EndPosition,
+			}
+		}
+
+		ast_node.AppendNode(&enumNode, &memberNode)
+
+		switch nextToken.Code {
+		case token.COMMA:
+			stream.MoveNext()
+		case token.CLOSE_BLOCK:
+		default:
+			return []*ast_node.ASTNode{&enumNode}, parser_error.ParserError{
+				Message:       "Enum members should be separated by \",\"",
+				StartPosition: memberToken.StartPosition,
+				EndPosition:   nextToken.EndPosition,
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package ast_node_for_of
+
+import (
+	"fmt"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_keyword"
+	"github.com/VadimZvf/golang/token_variable_declaration"
+)
+
+var ForOfProcessor ast_node.ASTNodeProcessor = process
+
+const ofKeyword = "of"
+
+// Reads "for (var item of iterable) { ... }", "var" before item is optional
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode != nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Left node not supported for for node",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at for processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var forNode = ast_node.CreateNode(currentToken)
+	stream.MoveNext()
+
+	var openExpressionToken, isEndAtOpenExpression = stream.Look()
+
+	if isEndAtOpenExpression || openExpressionToken.Code != token.OPEN_EXPRESSION {
+		return []*ast_node.ASTNode{&forNode}, parser_error.ParserError{
+			Message:       "For loop should have \"(\" after \"for\"",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   openExpressionToken.EndPosition,
+		}
+	}
+
+	stream.MoveNext()
+
+	var itemToken, isEndAtItem = stream.Look()
+	var itemName token.TokenParam
+
+	switch {
+	case isEndAtItem:
+	case itemToken.Code == token_variable_declaration.VARIABLE_DECLARAION:
+		itemName = token_variable_declaration.GetVariableNameParam(itemToken)
+	case itemToken.Code == token_keyword.KEY_WORD:
+		itemName = token.TokenParam{
+			Value:         itemToken.Value,
+			StartPosition: itemToken.StartPosition,
+			EndPosition:   itemToken.EndPosition,
+		}
+	}
+
+	if itemName.Value == "" {
+		return []*ast_node.ASTNode{&forNode}, parser_error.ParserError{
+			Message:       "For loop should have variable name for items",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   itemToken.EndPosition,
+		}
+	}
+
+	forNode.Params = []ast_node.ASTNodeParam{{
+		Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+		Value:         itemName.Value,
+		StartPosition: itemName.StartPosition,
+		EndPosition:   itemName.EndPosition,
+	}}
+
+	stream.MoveNext()
+
+	var ofToken, isEndAtOf = stream.Look()
+
+	if isEndAtOf || ofToken.Code != token_keyword.KEY_WORD || ofToken.Value != ofKeyword {
+		return []*ast_node.ASTNode{&forNode}, parser_error.ParserError{
+			Message:       "For loop should have \"of\" after variable name",
+			StartPosition: itemToken.StartPosition,
+			EndPosition:   ofToken.EndPosition,
+		}
+	}
+
+	stream.MoveNext()
+
+	var iterableToken, _ = stream.Look()
+	var iterableNodes, iterableNodeError = context.Process(stream, context, nil)
+
+	if iterableNodeError != nil {
+		return []*ast_node.ASTNode{&forNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse iterable value of for loop",
+		}, iterableNodeError)
+	}
+
+	if len(iterableNodes) != 1 {
+		return []*ast_node.ASTNode{&forNode}, parser_error.ParserError{
+			Message:       "Parsing error. For loop should have only one iterable node. But received: " + fmt.Sprint(len(iterableNodes)),
+			StartPosition: iterableToken.StartPosition,
+			EndPosition:   iterableToken.EndPosition,
+		}
+	}
+
+	forNode.Arguments = iterableNodes
+	stream.MoveNext()
+
+	var closeExpressionToken, isEndAtCloseExpression = stream.Look()
+
+	if isEndAtCloseExpression || closeExpressionToken.Code != token.CLOSE_EXPRESSION {
+		return []*ast_node.ASTNode{&forNode}, parser_error.ParserError{
+			Message:       "For loop should have \")\" after iterable value",
+			StartPosition: openExpressionToken.StartPosition,
+			EndPosition:   closeExpressionToken.EndPosition,
+		}
+	}
+
+	stream.MoveNext()
+
+	var bodyToken, isEndAtBody = stream.Look()
+
+	if isEndAtBody || bodyToken.Code != token.OPEN_BLOCK {
+		return []*ast_node.ASTNode{&forNode}, parser_error.ParserError{
+			Message:       "For loop should have body",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   bodyToken.EndPosition,
+		}
+	}
+
+	var bodyNodes, bodyNodeParsingError = context.Process(stream, context, nil)
+
+	if bodyNodeParsingError != nil {
+		return []*ast_node.ASTNode{&forNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parsing in for loop body",
+		}, bodyNodeParsingError)
+	}
+
+	ast_node.AppendNodes(&forNode, bodyNodes)
+
+	var closeBlockToken, _ = stream.Look()
+	forNode.EndPosition = closeBlockToken.EndPosition
+
+	return []*ast_node.ASTNode{&forNode}, nil
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/VadimZvf/golang/ast_node"
 	"github.com/VadimZvf/golang/parser_error"
 	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_async"
+	"github.com/VadimZvf/golang/token_function_declaration"
 )
 
 var FunctionProcessor ast_node.ASTNodeProcessor = process
@@ -27,7 +29,77 @@ func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingCo
 		}
 	}
 
+	// Decorators, like "@memo @trace function fib(n) {}"
+	var decorators = []ast_node.ASTNodeParam{}
+	var startPosition = currentToken.StartPosition
+
+	for !isEnd && currentToken.Code == token_function_declaration.DECORATOR {
+		var decoratorToken = currentToken
+		var decoratorName = currentToken.Params[0]
+
+		decorators = append(decorators, ast_node.ASTNodeParam{
+			Name:          ast_node.AST_PARAM_FUNCTION_DECORATOR,
+			Value:         decoratorName.Value,
+			StartPosition: decoratorName.StartPosition,
+			EndPosition:   decoratorName.EndPosition,
+		})
+
+		stream.MoveNext()
+		currentToken, isEnd = stream.Look()
+
+		if isEnd || (currentToken.Code != token_function_declaration.DECORATOR &&
+			currentToken.Code != token_async.ASYNC_DECLARATION &&
+			currentToken.Code != token_function_declaration.FUNCTION_DECLARATION) {
+			return []*ast_node.ASTNode{}, parser_error.ParserError{
+				Message:       "Decorator should be followed by function declaration",
+				StartPosition: decoratorToken.StartPosition,
+				EndPosition:   decoratorToken.EndPosition,
+			}
+		}
+	}
+
+	// Async function, like "async function load() {}"
+	var asyncToken = currentToken
+	var isAsync = currentToken.Code == token_async.ASYNC_DECLARATION
+
+	if isAsync {
+		stream.MoveNext()
+		currentToken, isEnd = stream.Look()
+
+		if isEnd || currentToken.Code != token_function_declaration.FUNCTION_DECLARATION {
+			return []*ast_node.ASTNode{}, parser_error.ParserError{
+				Message:       "Expected function declaration after \"async\"",
+				StartPosition: asyncToken.StartPosition,
+				EndPosition:   asyncToken.EndPosition,
+			}
+		}
+	}
+
 	var functionNode = ast_node.CreateNode(currentToken)
+
+	if isAsync {
+		if ast_node.IsGeneratorFunction(&functionNode) {
+			return []*ast_node.ASTNode{}, parser_error.ParserError{
+				Message:       "Async generator functions are not supported",
+				StartPosition: asyncToken.StartPosition,
+				EndPosition:   currentToken.EndPosition,
+			}
+		}
+
+		functionNode.Params = append(functionNode.Params, ast_node.ASTNodeParam{
+			Name:          ast_node.AST_PARAM_FUNCTION_ASYNC,
+			Value:         "async",
+			StartPosition: asyncToken.StartPosition,
+			EndPosition:   asyncToken.EndPosition,
+		})
+		functionNode.StartPosition = asyncToken.StartPosition
+	}
+
+	if len(decorators) > 0 {
+		functionNode.Params = append(functionNode.Params, decorators...)
+		functionNode.StartPosition = startPosition
+	}
+
 	stream.MoveNext()
 
 	var nextToken, isEndNext = stream.Look()
@@ -0,0 +1,89 @@
+package ast_node_index
+
+import (
+	"fmt"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token"
+)
+
+var IndexProcessor ast_node.ASTNodeProcessor = process
+
+// Reads index access, like "text[1]". Left node is indexed value
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode == nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Expected value before index",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{leftNode}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at index expression processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var indexNode = ast_node.ASTNode{
+		Code:          ast_node.AST_NODE_CODE_INDEX,
+		StartPosition: currentToken.StartPosition,
+		EndPosition:   currentToken.EndPosition,
+		Body:          []*ast_node.ASTNode{leftNode},
+	}
+
+	stream.MoveNext()
+	var indexToken, isEndAtIndex = stream.Look()
+
+	if isEndAtIndex || indexToken.Code == token.CLOSE_INDEX {
+		return []*ast_node.ASTNode{&indexNode}, parser_error.ParserError{
+			Message:       "Index expression should have index value",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   indexToken.EndPosition,
+		}
+	}
+
+	var indexValueNodes, indexValueError = context.Process(stream, context, nil)
+
+	if indexValueError != nil {
+		return []*ast_node.ASTNode{&indexNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse index value",
+		}, indexValueError)
+	}
+
+	if len(indexValueNodes) != 1 {
+		return []*ast_node.ASTNode{&indexNode}, parser_error.ParserError{
+			Message:       "Parsing error. Index expression should have only one value node. But received: " + fmt.Sprint(len(indexValueNodes)),
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	indexNode.Arguments = indexValueNodes
+
+	stream.MoveNext()
+	var closeToken, isEndAtClose = stream.Look()
+
+	if isEndAtClose || closeToken.Code != token.CLOSE_INDEX {
+		return []*ast_node.ASTNode{&indexNode}, parser_error.ParserError{
+			Message:       "Unknow token. Expected end of index expression. But received: " + closeToken.Code,
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   closeToken.EndPosition,
+		}
+	}
+
+	indexNode.EndPosition = closeToken.EndPosition
+
+	if !ast_node.IsNextExpressionToken(stream) {
+		return []*ast_node.ASTNode{&indexNode}, nil
+	}
+
+	stream.MoveNext()
+
+	return context.Process(stream, context, &indexNode)
+}
@@ -0,0 +1,297 @@
+package ast_node_match
+
+import (
+	"fmt"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_boolean"
+	"github.com/VadimZvf/golang/token_if"
+	"github.com/VadimZvf/golang/token_keyword"
+	"github.com/VadimZvf/golang/token_number"
+	"github.com/VadimZvf/golang/token_string"
+)
+
+var MatchProcessor ast_node.ASTNodeProcessor = process
+
+const wildcardName = "_"
+
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode != nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Left node not supported for match node",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at match processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var matchNode = ast_node.CreateNode(currentToken)
+	stream.MoveNext()
+
+	var valueToken, isEndAtValue = stream.Look()
+
+	if isEndAtValue || valueToken.Code != token.OPEN_EXPRESSION {
+		return []*ast_node.ASTNode{&matchNode}, parser_error.ParserError{
+			Message:       "Match should have value in parentheses",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var valueNodes, valueNodeError = context.Process(stream, context, nil)
+
+	if valueNodeError != nil {
+		return []*ast_node.ASTNode{&matchNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse value node of match expression",
+		}, valueNodeError)
+	}
+
+	if len(valueNodes) != 1 {
+		return []*ast_node.ASTNode{&matchNode}, parser_error.ParserError{
+			Message:       "Parsing error. Match expression should have only one value node. But received: " + fmt.Sprint(len(valueNodes)),
+			StartPosition: valueToken.StartPosition,
+			EndPosition:   valueToken.EndPosition,
+		}
+	}
+
+	matchNode.Arguments = valueNodes
+	stream.MoveNext()
+
+	var openBlockToken, isEndAtOpenBlock = stream.Look()
+
+	if isEndAtOpenBlock || openBlockToken.Code != token.OPEN_BLOCK {
+		return []*ast_node.ASTNode{&matchNode}, parser_error.ParserError{
+			Message:       "Match should have arms block",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   openBlockToken.EndPosition,
+		}
+	}
+
+	stream.MoveNext()
+
+	var nextToken, isEndNext = stream.Look()
+
+	for !isEndNext && nextToken.Code != token.CLOSE_BLOCK {
+		var armNode, armParsingError = processArm(stream, context)
+
+		if armParsingError != nil {
+			return []*ast_node.ASTNode{&matchNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+				Message: "Failed parsing match arm",
+			}, armParsingError)
+		}
+
+		ast_node.AppendNode(&matchNode, armNode)
+		nextToken, isEndNext = stream.Look()
+	}
+
+	if isEndNext {
+		return []*ast_node.ASTNode{&matchNode}, parser_error.ParserError{
+			Message:       "Unexpected file end. Match should be closed",
+			StartPosition: openBlockToken.StartPosition,
+			EndPosition:   openBlockToken.EndPosition,
+		}
+	}
+
+	matchNode.EndPosition = nextToken.EndPosition
+
+	if !ast_node.IsNextExpressionToken(stream) {
+		return []*ast_node.ASTNode{&matchNode}, nil
+	}
+
+	stream.MoveNext()
+
+	return context.Process(stream, context, &matchNode)
+}
+
+// Reads "pattern | pattern if guard => value" and leaves stream after arm divider
+func processArm(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext) (*ast_node.ASTNode, error) {
+	var armToken, _ = stream.Look()
+	var armNode = ast_node.ASTNode{
+		Code:          ast_node.AST_NODE_CODE_MATCH_ARM,
+		StartPosition: armToken.StartPosition,
+		EndPosition:   armToken.EndPosition,
+	}
+
+	var patterns, patternsParsingError = processPatterns(stream)
+
+	armNode.Arguments = patterns
+
+	if patternsParsingError != nil {
+		return &armNode, patternsParsingError
+	}
+
+	var currentToken, isEnd = stream.Look()
+
+	if !isEnd && currentToken.Code == token_if.IF_DECLARATION {
+		var guardNode = ast_node.ASTNode{
+			Code:          ast_node.AST_NODE_CODE_MATCH_GUARD,
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+
+		stream.MoveNext()
+
+		var guardNodes, guardNodeError = context.Process(stream, context, nil)
+
+		if guardNodeError != nil {
+			return &armNode, parser_error.MergeParserErrors(parser_error.ParserError{
+				Message: "Failed parse match arm guard",
+			}, guardNodeError)
+		}
+
+		if len(guardNodes) != 1 {
+			return &armNode, parser_error.ParserError{
+				Message:       "Parsing error. Match arm guard should have only one value node. But received: " + fmt.Sprint(len(guardNodes)),
+				StartPosition: currentToken.StartPosition,
+				EndPosition:   currentToken.EndPosition,
+			}
+		}
+
+		ast_node.AppendNodes(&guardNode, guardNodes)
+		ast_node.AppendNode(&armNode, &guardNode)
+
+		stream.MoveNext()
+		currentToken, isEnd = stream.Look()
+	}
+
+	if isEnd || currentToken.Code != token.ARROW {
+		return &armNode, parser_error.ParserError{
+			Message:       "Match arm should have \"=>\" after pattern",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	armNode.EndPosition = currentToken.EndPosition
+	stream.MoveNext()
+
+	var valueNodes, valueNodeError = context.Process(stream, context, nil)
+
+	if valueNodeError != nil {
+		return &armNode, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse match arm value",
+		}, valueNodeError)
+	}
+
+	if len(valueNodes) != 1 {
+		return &armNode, parser_error.ParserError{
+			Message:       "Parsing error. Match arm should have only one value node. But received: " + fmt.Sprint(len(valueNodes)),
+			StartPosition: armNode.StartPosition,
+			EndPosition:   armNode.EndPosition,
+		}
+	}
+
+	ast_node.AppendNodes(&armNode, valueNodes)
+
+	stream.MoveNext()
+	currentToken, isEnd = stream.Look()
+
+	if !isEnd && currentToken.Code == token.COMMA {
+		stream.MoveNext()
+		return &armNode, nil
+	}
+
+	if isEnd || currentToken.Code != token.CLOSE_BLOCK {
+		return &armNode, parser_error.ParserError{
+			Message:       "Parsing error. Match arms should be divided by comma. But received: " + currentToken.Code,
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	return &armNode, nil
+}
+
+func processPatterns(stream ast_node.ITokenStream) ([]*ast_node.ASTNode, error) {
+	var patterns = []*ast_node.ASTNode{}
+	var hasBinding = false
+
+	for {
+		var patternToken, isEnd = stream.Look()
+
+		if isEnd {
+			return patterns, parser_error.ParserError{
+				Message:       "Unexpected file end. Match arm should have pattern",
+				StartPosition: patternToken.StartPosition,
+				EndPosition:   patternToken.EndPosition,
+			}
+		}
+
+		var patternNode, patternError = createPattern(patternToken)
+
+		if patternError != nil {
+			return patterns, patternError
+		}
+
+		if patternNode.Code == ast_node.AST_NODE_CODE_MATCH_BINDING {
+			hasBinding = true
+		}
+
+		patterns = append(patterns, patternNode)
+		stream.MoveNext()
+
+		var nextToken, isEndNext = stream.Look()
+
+		if isEndNext || nextToken.Code != token.VERTICAL_BAR {
+			break
+		}
+
+		stream.MoveNext()
+	}
+
+	if hasBinding && len(patterns) > 1 {
+		return patterns, parser_error.ParserError{
+			Message:       "Binding pattern cannot be combined with other patterns",
+			StartPosition: patterns[0].StartPosition,
+			EndPosition:   patterns[len(patterns)-1].EndPosition,
+		}
+	}
+
+	return patterns, nil
+}
+
+func createPattern(patternToken token.Token) (*ast_node.ASTNode, error) {
+	switch patternToken.Code {
+	case token_number.NUMBER, token_string.STRING, token_boolean.BOOLEAN:
+		var literalNode = ast_node.CreateNode(patternToken)
+		return &literalNode, nil
+
+	case token_keyword.KEY_WORD:
+		if patternToken.Value == wildcardName {
+			return &ast_node.ASTNode{
+				Code:          ast_node.AST_NODE_CODE_MATCH_WILDCARD,
+				StartPosition: patternToken.StartPosition,
+				EndPosition:   patternToken.EndPosition,
+			}, nil
+		}
+
+		return &ast_node.ASTNode{
+			Code: ast_node.AST_NODE_CODE_MATCH_BINDING,
+			Params: []ast_node.ASTNodeParam{{
+				Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+				Value:         patternToken.Value,
+				StartPosition: patternToken.StartPosition,
+				EndPosition:   patternToken.EndPosition,
+			}},
+			StartPosition: patternToken.StartPosition,
+			EndPosition:   patternToken.EndPosition,
+		}, nil
+	}
+
+	return nil, parser_error.ParserError{
+		Message:       "Unexpected match pattern. Expected literal, \"_\" or variable name. But received: " + patternToken.Code,
+		StartPosition: patternToken.StartPosition,
+		EndPosition:   patternToken.EndPosition,
+	}
+}
@@ -28,7 +28,7 @@ func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingCo
 
 	var numberNode = ast_node.CreateNode(currentToken)
 
-	if !ast_node.IsNextArithmeticToken(stream) {
+	if !ast_node.IsNextLiteralContinuationToken(stream) {
 		return []*ast_node.ASTNode{&numberNode}, nil
 	}
 
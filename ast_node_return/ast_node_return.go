@@ -5,6 +5,7 @@ import (
 
 	"github.com/VadimZvf/golang/ast_node"
 	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token"
 )
 
 var ReturnProcessor ast_node.ASTNodeProcessor = process
@@ -29,6 +30,14 @@ func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingCo
 	}
 
 	var returnNode = ast_node.CreateNode(currentToken)
+
+	// Return without value, like "return;"
+	var nextToken, isEndNext = stream.LookNext()
+
+	if isEndNext || nextToken.Code == token.END_LINE || nextToken.Code == token.CLOSE_BLOCK {
+		return []*ast_node.ASTNode{&returnNode}, nil
+	}
+
 	stream.MoveNext()
 
 	var valueNodes, valueNodeError = context.Process(stream, context, nil)
@@ -0,0 +1,72 @@
+package ast_node_spawn
+
+import (
+	"fmt"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+)
+
+var SpawnProcessor ast_node.ASTNodeProcessor = process
+
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode != nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Left node not supported for spawn node",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at spawn processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var spawnNode = ast_node.CreateNode(currentToken)
+
+	var _, isEndNext = stream.LookNext()
+
+	if isEndNext {
+		return []*ast_node.ASTNode{&spawnNode}, parser_error.ParserError{
+			Message:       "Spawn should be followed by function call",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	stream.MoveNext()
+
+	var callNodes, callNodeError = context.Process(stream, context, nil)
+
+	if callNodeError != nil {
+		return []*ast_node.ASTNode{&spawnNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse call of spawn expression",
+		}, callNodeError)
+	}
+
+	if len(callNodes) != 1 {
+		return []*ast_node.ASTNode{&spawnNode}, parser_error.ParserError{
+			Message:       "Parsing error. Spawn expression should have only one call node. But received: " + fmt.Sprint(len(callNodes)),
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if callNodes[0].Code != ast_node.AST_NODE_CODE_CALL_EXPRESSION {
+		return []*ast_node.ASTNode{&spawnNode}, parser_error.ParserError{
+			Message:       "Spawn should be followed by function call",
+			StartPosition: callNodes[0].StartPosition,
+			EndPosition:   callNodes[0].EndPosition,
+		}
+	}
+
+	ast_node.AppendNodes(&spawnNode, callNodes)
+
+	return []*ast_node.ASTNode{&spawnNode}, nil
+}
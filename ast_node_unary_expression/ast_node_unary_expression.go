@@ -0,0 +1,65 @@
+package ast_node_unary_expression
+
+import (
+	"fmt"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+)
+
+var UnaryExpressionProcessor ast_node.ASTNodeProcessor = process
+
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode != nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Left node not supported for unary expression",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at unary expression processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var unaryNode = ast_node.CreateNode(currentToken)
+	stream.MoveNext()
+
+	var operandNodes, operandNodeError = context.Process(stream, context, nil)
+
+	if operandNodeError != nil {
+		return []*ast_node.ASTNode{&unaryNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse operand of unary expression",
+		}, operandNodeError)
+	}
+
+	if len(operandNodes) != 1 {
+		return []*ast_node.ASTNode{&unaryNode}, parser_error.ParserError{
+			Message:       "Parsing error. Unary expression should have only one operand node. But received: " + fmt.Sprint(len(operandNodes)),
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	return []*ast_node.ASTNode{applyToLeftmostOperand(&unaryNode, operandNodes[0])}, nil
+}
+
+// Operand is parsed greedy, so "typeof a == b" is received as "a == b".
+// Unary operator binds stronger than any binary one, so apply it to the leftmost operand only
+func applyToLeftmostOperand(unaryNode *ast_node.ASTNode, operandNode *ast_node.ASTNode) *ast_node.ASTNode {
+	if operandNode.Code == ast_node.AST_NODE_CODE_BINARY_EXPRESSION {
+		operandNode.Body[0] = applyToLeftmostOperand(unaryNode, operandNode.Body[0])
+
+		return operandNode
+	}
+
+	ast_node.AppendNode(unaryNode, operandNode)
+
+	return unaryNode
+}
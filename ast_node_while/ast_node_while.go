@@ -0,0 +1,88 @@
+package ast_node_while
+
+import (
+	"fmt"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token"
+)
+
+var WhileProcessor ast_node.ASTNodeProcessor = process
+
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode != nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Left node not supported for while node",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at while processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var whileNode = ast_node.CreateNode(currentToken)
+	stream.MoveNext()
+
+	var conditionToken, isEndAtCondition = stream.Look()
+
+	if isEndAtCondition || conditionToken.Code != token.OPEN_EXPRESSION {
+		return []*ast_node.ASTNode{&whileNode}, parser_error.ParserError{
+			Message:       "While should have condition in parentheses",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var conditionNodes, conditionNodeError = context.Process(stream, context, nil)
+
+	if conditionNodeError != nil {
+		return []*ast_node.ASTNode{&whileNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse condition of while loop",
+		}, conditionNodeError)
+	}
+
+	if len(conditionNodes) != 1 {
+		return []*ast_node.ASTNode{&whileNode}, parser_error.ParserError{
+			Message:       "Parsing error. While loop should have only one condition node. But received: " + fmt.Sprint(len(conditionNodes)),
+			StartPosition: conditionToken.StartPosition,
+			EndPosition:   conditionToken.EndPosition,
+		}
+	}
+
+	whileNode.Arguments = conditionNodes
+	stream.MoveNext()
+
+	var bodyToken, isEndAtBody = stream.Look()
+
+	if isEndAtBody || bodyToken.Code != token.OPEN_BLOCK {
+		return []*ast_node.ASTNode{&whileNode}, parser_error.ParserError{
+			Message:       "While loop should have body",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   bodyToken.EndPosition,
+		}
+	}
+
+	var bodyNodes, bodyNodeParsingError = context.Process(stream, context, nil)
+
+	if bodyNodeParsingError != nil {
+		return []*ast_node.ASTNode{&whileNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parsing in while loop body",
+		}, bodyNodeParsingError)
+	}
+
+	ast_node.AppendNodes(&whileNode, bodyNodes)
+
+	var closeBlockToken, _ = stream.Look()
+	whileNode.EndPosition = closeBlockToken.EndPosition
+
+	return []*ast_node.ASTNode{&whileNode}, nil
+}
@@ -0,0 +1,62 @@
+package ast_node_yield
+
+import (
+	"fmt"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token"
+)
+
+var YieldProcessor ast_node.ASTNodeProcessor = process
+
+func process(stream ast_node.ITokenStream, context ast_node.IASTNodeProcessingContext, leftNode *ast_node.ASTNode) ([]*ast_node.ASTNode, error) {
+	var currentToken, isEnd = stream.Look()
+
+	if leftNode != nil {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Left node not supported for yield node",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	if isEnd {
+		return []*ast_node.ASTNode{}, parser_error.ParserError{
+			Message:       "Unexpected file end. Something wrong internal at yield processing",
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	var yieldNode = ast_node.CreateNode(currentToken)
+
+	// Yield without value, like "yield;"
+	var nextToken, isEndNext = stream.LookNext()
+
+	if isEndNext || nextToken.Code == token.END_LINE || nextToken.Code == token.CLOSE_BLOCK || nextToken.Code == token.CLOSE_EXPRESSION {
+		return []*ast_node.ASTNode{&yieldNode}, nil
+	}
+
+	stream.MoveNext()
+
+	var valueNodes, valueNodeError = context.Process(stream, context, nil)
+
+	if valueNodeError != nil {
+		return []*ast_node.ASTNode{&yieldNode}, parser_error.MergeParserErrors(parser_error.ParserError{
+			Message: "Failed parse value node of yield expression",
+		}, valueNodeError)
+	}
+
+	if len(valueNodes) != 1 {
+		return []*ast_node.ASTNode{&yieldNode}, parser_error.ParserError{
+			Message:       "Parsing error. Yield expression should have only one value node. But received: " + fmt.Sprint(len(valueNodes)),
+			StartPosition: currentToken.StartPosition,
+			EndPosition:   currentToken.EndPosition,
+		}
+	}
+
+	ast_node.AppendNodes(&yieldNode, valueNodes)
+
+	return []*ast_node.ASTNode{&yieldNode}, nil
+}
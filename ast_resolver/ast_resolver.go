@@ -0,0 +1,353 @@
+package ast_resolver
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+)
+
+// Names of build in functions. They take the first slots of root scope in this order
+var BUILD_IN_NAMES = []string{
+	"print",
+	"isNumber",
+	"isString",
+	"isInteger",
+	"isBoolean",
+	"isFunction",
+	"isNative",
+	"isMap",
+	"isSet",
+	"isUnknown",
+	"toInteger",
+	"toNumber",
+	"fnName",
+	"fnArity",
+	"setTimeout",
+	"setInterval",
+	"clearTimeout",
+	"clearInterval",
+	"sleep",
+	"now",
+	"Map",
+	"Set",
+	"chan",
+	"send",
+	"receive",
+	"close",
+	"select",
+	"memo",
+	"trace",
+}
+
+// Variables of one runtime scope, like function call or loop iteration
+type scope struct {
+	parent *scope
+	slots  map[string]int
+	// Names, which cannot be assigned, like names of enums
+	constants map[string]bool
+	// Node, which creates scope, gets count of its variables
+	node *ast_node.ASTNode
+}
+
+type resolver struct {
+	scope *scope
+	err   error
+}
+
+// Index of build in function in root scope, -1 for unknown name
+func GetBuildInIndex(name string) int {
+	for index, buildInName := range BUILD_IN_NAMES {
+		if buildInName == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// Assigns slots to all declared and used variables, so runtime finds variable by depth
+// and index of slot instead of lookup by name. Variable is visible in whole scope, where
+// it is declared, so closures can use variables declared after them.
+// Returns error for the first variable, which is not declared in any scope
+func Resolve(root *ast_node.ASTNode) error {
+	var r = resolver{}
+
+	r.pushScope(root)
+
+	for _, name := range BUILD_IN_NAMES {
+		r.scope.declare(name)
+	}
+
+	r.resolveScope(root.Body)
+
+	return r.err
+}
+
+func (r *resolver) pushScope(node *ast_node.ASTNode) {
+	r.scope = &scope{
+		parent:    r.scope,
+		slots:     map[string]int{},
+		constants: map[string]bool{},
+		node:      node,
+	}
+	node.ScopeSize = 0
+}
+
+func (r *resolver) popScope() {
+	r.scope = r.scope.parent
+}
+
+func (r *resolver) report(message string, start int, end int) {
+	if r.err == nil {
+		r.err = parser_error.CreateError(message, start, end)
+	}
+}
+
+// Declared again variable gets the same slot, so runtime reports repeated declaration
+func (s *scope) declare(name string) int {
+	var index, isDeclared = s.slots[name]
+
+	if !isDeclared {
+		index = len(s.slots)
+		s.slots[name] = index
+		s.node.ScopeSize = len(s.slots)
+	}
+
+	return index
+}
+
+func (s *scope) lookup(name string) (*ast_node.VariableSlot, bool) {
+	var depth = 0
+
+	for current := s; current != nil; current = current.parent {
+		if index, isDeclared := current.slots[name]; isDeclared {
+			return &ast_node.VariableSlot{Depth: depth, Index: index}, true
+		}
+
+		depth++
+	}
+
+	return nil, false
+}
+
+// True, when the closest declaration of name is constant
+func (s *scope) isConstant(name string) bool {
+	for current := s; current != nil; current = current.parent {
+		if _, isDeclared := current.slots[name]; isDeclared {
+			return current.constants[name]
+		}
+	}
+
+	return false
+}
+
+// Declares name from param of node in current scope
+func (r *resolver) declareParam(param *ast_node.ASTNodeParam) {
+	param.Slot = &ast_node.VariableSlot{Index: r.scope.declare(param.Value)}
+}
+
+// Finds slot for name from param of node, message is used, when name is not declared
+func (r *resolver) resolveParam(param *ast_node.ASTNodeParam, message string) {
+	var slot, isDeclared = r.scope.lookup(param.Value)
+
+	if !isDeclared {
+		r.report(message+param.Value, param.StartPosition, param.EndPosition)
+		return
+	}
+
+	param.Slot = slot
+}
+
+// Declarations of scope are collected first, then usages are resolved
+func (r *resolver) resolveScope(nodes []*ast_node.ASTNode) {
+	for _, node := range nodes {
+		r.collect(node)
+	}
+
+	for _, node := range nodes {
+		r.resolve(node)
+	}
+}
+
+// Declares variables of node, which belong to current scope. Nested scopes are skipped
+func (r *resolver) collect(node *ast_node.ASTNode) {
+	switch node.Code {
+	case ast_node.AST_NODE_CODE_VARIABLE_DECLARATION:
+		r.declareNamedParams(node, ast_node.AST_PARAM_VARIABLE_NAME)
+
+	case ast_node.AST_NODE_CODE_ENUM:
+		r.declareNamedParams(node, ast_node.AST_PARAM_VARIABLE_NAME)
+
+		for _, param := range node.Params {
+			if param.Name == ast_node.AST_PARAM_VARIABLE_NAME {
+				r.scope.constants[param.Value] = true
+			}
+		}
+
+	case ast_node.AST_NODE_CODE_FUNCTION:
+		r.declareNamedParams(node, ast_node.AST_PARAM_FUNCTION_NAME)
+		return
+
+	case ast_node.AST_NODE_CODE_WHILE, ast_node.AST_NODE_CODE_FOR_OF:
+		r.collectNodes(node.Arguments)
+		return
+
+	case ast_node.AST_NODE_CODE_MATCH:
+		r.collectNodes(node.Arguments)
+
+		for _, armNode := range node.Body {
+			if getBinding(armNode) == nil {
+				r.collect(armNode)
+				continue
+			}
+
+			// Guard and value of arm with binding are in scope of arm
+			r.collectNodes(getArmPatterns(armNode))
+		}
+
+		return
+	}
+
+	r.collectNodes(node.Body)
+	r.collectNodes(node.Arguments)
+}
+
+func (r *resolver) collectNodes(nodes []*ast_node.ASTNode) {
+	for _, node := range nodes {
+		r.collect(node)
+	}
+}
+
+func (r *resolver) declareNamedParams(node *ast_node.ASTNode, paramName string) {
+	for index := range node.Params {
+		if node.Params[index].Name == paramName {
+			r.declareParam(&node.Params[index])
+		}
+	}
+}
+
+func (r *resolver) resolve(node *ast_node.ASTNode) {
+	switch node.Code {
+	case ast_node.AST_NODE_CODE_REFERENCE:
+		for index := range node.Params {
+			if node.Params[index].Name == ast_node.AST_PARAM_VARIABLE_NAME {
+				r.resolveParam(&node.Params[index], "Variable not declared: ")
+			}
+		}
+
+		return
+
+	case ast_node.AST_NODE_CODE_ASSIGNMENT:
+		r.checkConstantAssignment(node)
+
+	case ast_node.AST_NODE_CODE_FUNCTION:
+		r.resolveFunction(node)
+		return
+
+	case ast_node.AST_NODE_CODE_WHILE:
+		r.resolveNodes(node.Arguments)
+
+		// Every iteration has own scope
+		r.pushScope(node)
+		r.resolveScope(node.Body)
+		r.popScope()
+
+		return
+
+	case ast_node.AST_NODE_CODE_FOR_OF:
+		r.resolveNodes(node.Arguments)
+
+		r.pushScope(node)
+		r.declareNamedParams(node, ast_node.AST_PARAM_VARIABLE_NAME)
+		r.resolveScope(node.Body)
+		r.popScope()
+
+		return
+
+	case ast_node.AST_NODE_CODE_MATCH:
+		r.resolveNodes(node.Arguments)
+
+		for _, armNode := range node.Body {
+			r.resolveMatchArm(armNode)
+		}
+
+		return
+	}
+
+	r.resolveNodes(node.Body)
+	r.resolveNodes(node.Arguments)
+}
+
+func (r *resolver) resolveNodes(nodes []*ast_node.ASTNode) {
+	for _, node := range nodes {
+		r.resolve(node)
+	}
+}
+
+// Enum is fixed at declaration, so its name cannot get other value
+func (r *resolver) checkConstantAssignment(node *ast_node.ASTNode) {
+	if len(node.Body) == 0 || node.Body[0].Code != ast_node.AST_NODE_CODE_REFERENCE {
+		return
+	}
+
+	for _, param := range node.Body[0].Params {
+		if param.Name == ast_node.AST_PARAM_VARIABLE_NAME && r.scope.isConstant(param.Value) {
+			r.report("Cannot assign to enum "+param.Value+", enums are immutable", param.StartPosition, param.EndPosition)
+		}
+	}
+}
+
+// Decorators are found in scope of declaration. Call of function has own scope
+// with arguments in the first slots
+func (r *resolver) resolveFunction(node *ast_node.ASTNode) {
+	for index := range node.Params {
+		if node.Params[index].Name == ast_node.AST_PARAM_FUNCTION_DECORATOR {
+			r.resolveParam(&node.Params[index], "Decorator is not defined: ")
+		}
+	}
+
+	r.pushScope(node)
+	r.declareNamedParams(node, ast_node.AST_PARAM_FUNCTION_ARGUMENT_NAME)
+	r.resolveScope(node.Body)
+	r.popScope()
+}
+
+// Arm with binding has own scope for binding, its guard and value.
+// Other patterns are evaluated in scope of match
+func (r *resolver) resolveMatchArm(armNode *ast_node.ASTNode) {
+	var binding = getBinding(armNode)
+
+	if binding == nil {
+		r.resolve(armNode)
+		return
+	}
+
+	r.resolveNodes(getArmPatterns(armNode))
+
+	r.pushScope(armNode)
+	r.declareNamedParams(binding, ast_node.AST_PARAM_VARIABLE_NAME)
+	r.resolveScope(armNode.Body)
+	r.popScope()
+}
+
+func getBinding(armNode *ast_node.ASTNode) *ast_node.ASTNode {
+	for _, patternNode := range armNode.Arguments {
+		if patternNode.Code == ast_node.AST_NODE_CODE_MATCH_BINDING {
+			return patternNode
+		}
+	}
+
+	return nil
+}
+
+// Patterns, which are compared with value
+func getArmPatterns(armNode *ast_node.ASTNode) []*ast_node.ASTNode {
+	var patterns = []*ast_node.ASTNode{}
+
+	for _, patternNode := range armNode.Arguments {
+		if patternNode.Code != ast_node.AST_NODE_CODE_MATCH_BINDING && patternNode.Code != ast_node.AST_NODE_CODE_MATCH_WILDCARD {
+			patterns = append(patterns, patternNode)
+		}
+	}
+
+	return patterns
+}
@@ -0,0 +1,184 @@
+package ast_resolver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/VadimZvf/golang/ast"
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func resolveCode(t *testing.T, code string) (*ast_node.ASTNode, error) {
+	var buffer = tokenizer_buffer.CreateBuffer(source_mock.GetSourceMock(code))
+	var tknzr = tokenizer.GetTokenizer(&buffer)
+	var tokens, tokensErr = tknzr.GetTokens()
+
+	if tokensErr != nil {
+		t.Fatalf("Code should be tokenized, but failed with error: \"%s\"", tokensErr.Error())
+	}
+
+	var root, astErr = ast.CreateAST(tokens)
+
+	if astErr != nil {
+		t.Fatalf("Code should be parsed, but failed with error: \"%s\"", astErr.Error())
+	}
+
+	return root, Resolve(root)
+}
+
+// Slots of all names in order of tree, like "a 0:1"
+func describeSlots(node *ast_node.ASTNode) []string {
+	var slots = []string{}
+
+	for _, param := range node.Params {
+		if param.Slot != nil {
+			slots = append(slots, fmt.Sprintf("%s %d:%d", param.Value, param.Slot.Depth, param.Slot.Index))
+		}
+	}
+
+	for _, children := range [][]*ast_node.ASTNode{node.Arguments, node.Body} {
+		for _, child := range children {
+			slots = append(slots, describeSlots(child)...)
+		}
+	}
+
+	return slots
+}
+
+func expectSlots(t *testing.T, root *ast_node.ASTNode, expected []string) {
+	var received = describeSlots(root)
+
+	if strings.Join(received, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Should resolve slots:\n%s\nbut received:\n%s", strings.Join(expected, ", "), strings.Join(received, ", "))
+	}
+}
+
+func TestFunctionScope(t *testing.T) {
+	var root, err = resolveCode(t, `
+	var a = 1
+	function add(x) {
+		var y = x
+		return a + y
+	}
+	print(add(2))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should be resolved, but failed with error: \"%s\"", err.Error())
+	}
+
+	var a = len(BUILD_IN_NAMES)
+
+	expectSlots(t, root, []string{
+		fmt.Sprintf("a 0:%d", a),
+		fmt.Sprintf("a 0:%d", a),
+		fmt.Sprintf("add 0:%d", a+1),
+		"x 0:0",
+		"y 0:1",
+		"y 0:1",
+		"x 0:0",
+		fmt.Sprintf("a 1:%d", a),
+		"y 0:1",
+		fmt.Sprintf("add 0:%d", a+1),
+		"print 0:0",
+	})
+
+	if root.ScopeSize != a+2 || root.Body[2].ScopeSize != 2 {
+		t.Errorf("Should count variables of scopes, but received: %d and %d", root.ScopeSize, root.Body[2].ScopeSize)
+	}
+}
+
+func TestLoopAndMatchScopes(t *testing.T) {
+	var root, err = resolveCode(t, `
+	var i = 0
+	while (i < 2) {
+		var next = i + 1
+		i = match (next) {
+			n if n > 1 => n,
+			_ => next
+		}
+	}
+	`)
+
+	if err != nil {
+		t.Errorf("Code should be resolved, but failed with error: \"%s\"", err.Error())
+	}
+
+	var i = len(BUILD_IN_NAMES)
+
+	expectSlots(t, root, []string{
+		fmt.Sprintf("i 0:%d", i),
+		fmt.Sprintf("i 0:%d", i),
+		fmt.Sprintf("i 0:%d", i),
+		"next 0:0",
+		"next 0:0",
+		fmt.Sprintf("i 1:%d", i),
+		fmt.Sprintf("i 1:%d", i),
+		"next 0:0",
+		"n 0:0",
+		"n 0:0",
+		"n 0:0",
+		"next 0:0",
+	})
+}
+
+func TestRepeatedDeclarationSharesSlot(t *testing.T) {
+	var root, err = resolveCode(t, `
+	var a
+	var a
+	`)
+
+	if err != nil {
+		t.Errorf("Code should be resolved, but failed with error: \"%s\"", err.Error())
+	}
+
+	var a = len(BUILD_IN_NAMES)
+
+	expectSlots(t, root, []string{fmt.Sprintf("a 0:%d", a), fmt.Sprintf("a 0:%d", a)})
+}
+
+func TestUndeclaredVariable(t *testing.T) {
+	var _, err = resolveCode(t, `
+	function f() {
+		return missing + other
+	}
+	`)
+
+	var parserErr, isParserErr = err.(parser_error.ParserError)
+
+	if !isParserErr || parserErr.Message != "Variable not declared: missing" || parserErr.StartPosition != 26 || parserErr.EndPosition != 32 {
+		t.Errorf("Should report the first undeclared variable, but received: %#v", err)
+	}
+}
+
+func TestUndeclaredDecorator(t *testing.T) {
+	var _, err = resolveCode(t, `@nope function f() {}`)
+
+	var parserErr, isParserErr = err.(parser_error.ParserError)
+
+	if !isParserErr || parserErr.Message != "Decorator is not defined: nope" || parserErr.StartPosition != 1 || parserErr.EndPosition != 4 {
+		t.Errorf("Should report undeclared decorator, but received: %#v", err)
+	}
+}
+
+func TestEnumNameIsConstant(t *testing.T) {
+	var _, err = resolveCode(t, `
+	enum E { A }
+	function f() {
+		var E = 1
+		E = 2
+	}
+	E = 5
+	`)
+
+	var parserErr, isParserErr = err.(parser_error.ParserError)
+
+	if !isParserErr || parserErr.Message != "Cannot assign to enum E, enums are immutable" || parserErr.StartPosition != 55 {
+		t.Errorf("Should report assignment to enum, but received: %#v", err)
+	}
+}
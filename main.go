@@ -1,15 +1,12 @@
 package main
 
 import (
-	"syscall/js"
-
+	"github.com/VadimZvf/golang/ast_node"
 	"github.com/VadimZvf/golang/parser"
 	"github.com/VadimZvf/golang/parser_error_printer"
 	"github.com/VadimZvf/golang/runtime"
-	"github.com/VadimZvf/golang/runtime_bridge_web"
 	"github.com/VadimZvf/golang/runtime_error_printer"
-	"github.com/VadimZvf/golang/source_string"
-	"github.com/VadimZvf/golang/stdout_web"
+	"github.com/VadimZvf/golang/type_checker"
 )
 
 type iStdout interface {
@@ -17,53 +14,55 @@ type iStdout interface {
 	PrintError(line string)
 }
 
-func main() {
-	// if len(os.Args) > 0 {
-	// 	filePath := os.Args[1]
+// Runs code by configured runtime. Type errors are printed, but code still runs,
+// only checked mode of runtime stops it on wrong values. Check reports errors without running
+func Run(source parser.ISource, stdout iStdout, rt *runtime.Runtime) interface{} {
+	var parser = parser.CreateParser(source, stdout)
 
-	// 	TlengRunFile(filePath)
-	// }
+	var astRoot, astError = parser.Parse(false)
 
-	js.Global().Set("TlengRun", js.FuncOf(TlengWebRun))
+	if astError != nil {
+		parser_error_printer.PrintError(parser.GetSourceCode(), stdout, astError)
+		return nil
+	}
 
-	<-make(chan bool)
-}
+	printTypeErrors(parser.GetSourceCode(), stdout, astRoot)
+
+	var runtimeErr = rt.Run(astRoot)
 
-func TlengWebRun(this js.Value, args []js.Value) interface{} {
-	codeText := args[0].String() // get the parameters
-	var src = source_string.GetSource(codeText)
-	var bridge = runtime_bridge_web.CreateBridge()
-	var stdout = stdout_web.CreateStdoutWeb()
-	Run(src, &stdout, &bridge)
+	if runtimeErr != nil {
+		runtime_error_printer.PrintError(parser.GetSourceCode(), stdout, runtimeErr)
+	}
 
 	return nil
 }
 
-// func TlengRunFile(filePath string) interface{} {
-// 	var src = source_file.GetSource(filePath)
-// 	var bridge = runtime_bridge_cli.CreateBridge()
-// 	var stdout = stdout.CreateStdout()
-// 	Run(src, &stdout, &bridge)
-
-// 	return nil
-// }
-
-func Run(source parser.ISource, stdout iStdout, bridge runtime.IBridge) interface{} {
+// Reports type errors of code without running it. Returns true, when code has errors
+func Check(source parser.ISource, stdout iStdout) bool {
 	var parser = parser.CreateParser(source, stdout)
 
 	var astRoot, astError = parser.Parse(false)
 
 	if astError != nil {
 		parser_error_printer.PrintError(parser.GetSourceCode(), stdout, astError)
-		return nil
+		return true
 	}
 
-	var rt = runtime.CreateRuntime(bridge)
-	var runtimeErr = rt.Run(astRoot)
+	if printTypeErrors(parser.GetSourceCode(), stdout, astRoot) {
+		return true
+	}
 
-	if runtimeErr != nil {
-		runtime_error_printer.PrintError(parser.GetSourceCode(), stdout, runtimeErr)
+	stdout.Print("No type errors found\n")
+
+	return false
+}
+
+func printTypeErrors(code string, stdout iStdout, astRoot *ast_node.ASTNode) bool {
+	var diagnostics = type_checker.Check(astRoot)
+
+	for _, diagnostic := range diagnostics {
+		parser_error_printer.PrintError(code, stdout, diagnostic)
 	}
 
-	return nil
+	return len(diagnostics) > 0
 }
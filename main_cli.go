@@ -0,0 +1,65 @@
+//go:build !js
+// +build !js
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/VadimZvf/golang/runtime"
+	"github.com/VadimZvf/golang/runtime_bridge_cli"
+	"github.com/VadimZvf/golang/runtime_clock"
+	"github.com/VadimZvf/golang/source_file"
+	"github.com/VadimZvf/golang/stdout"
+)
+
+// Usage: "tleng file.tl" runs file, "tleng --checked file.tl" runs it in checked mode,
+// "tleng check file.tl" reports its type errors
+func main() {
+	if len(os.Args) == 3 && os.Args[1] == "check" {
+		if TlengCheckFile(os.Args[2]) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if len(os.Args) == 3 && os.Args[1] == "--checked" {
+		TlengRunFile(os.Args[2], true)
+		return
+	}
+
+	if len(os.Args) == 2 {
+		TlengRunFile(os.Args[1], false)
+		return
+	}
+
+	fmt.Println("Usage: tleng [--checked] <file> or tleng check <file>")
+	os.Exit(2)
+}
+
+func TlengCheckFile(filePath string) bool {
+	var src = source_file.GetSource(filePath)
+	defer src.Close()
+	var stdout = stdout.CreateStdout()
+
+	return Check(src, &stdout)
+}
+
+// Checked mode stops program, when value doesn't match type annotation
+func TlengRunFile(filePath string, isChecked bool) interface{} {
+	var src = source_file.GetSource(filePath)
+	defer src.Close()
+	var bridge = runtime_bridge_cli.CreateBridge()
+	var stdout = stdout.CreateStdout()
+
+	var clock = runtime_clock.CreateRealClock()
+	var rt = runtime.CreateRuntime(&bridge)
+	rt.SetClock(&clock)
+	rt.SetCheckedMode(isChecked)
+
+	Run(src, &stdout, &rt)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"context"
+	"syscall/js"
+	"time"
+
+	"github.com/VadimZvf/golang/runtime"
+	"github.com/VadimZvf/golang/runtime_bridge_web"
+	"github.com/VadimZvf/golang/runtime_clock"
+	"github.com/VadimZvf/golang/source_string"
+	"github.com/VadimZvf/golang/stdout_web"
+)
+
+// Playground stops programs, which run too long or take too much memory
+const playgroundTimeout = 10 * time.Second
+const playgroundMaxMemory = 256 << 20
+
+func main() {
+	js.Global().Set("TlengRun", js.FuncOf(TlengWebRun))
+	js.Global().Set("TlengCheck", js.FuncOf(TlengWebCheck))
+
+	<-make(chan bool)
+}
+
+// Second parameter enables checked mode, it is optional
+func TlengWebRun(this js.Value, args []js.Value) interface{} {
+	codeText := args[0].String() // get the parameters
+	var isChecked = len(args) > 1 && args[1].Truthy()
+	var src = source_string.GetSource(codeText)
+	var bridge = runtime_bridge_web.CreateBridge()
+	var stdout = stdout_web.CreateStdoutWeb()
+
+	// Playground runs timers in virtual time, so page is never blocked by waiting
+	var clock = runtime_clock.CreateVirtualClock()
+	var rt = runtime.CreateRuntime(&bridge)
+	rt.SetClock(&clock)
+	rt.SetCheckedMode(isChecked)
+
+	var ctx, cancel = context.WithTimeout(context.Background(), playgroundTimeout)
+	defer cancel()
+	rt.SetOptions(runtime.Options{Context: ctx, MaxMemory: playgroundMaxMemory})
+
+	Run(src, &stdout, &rt)
+
+	return nil
+}
+
+func TlengWebCheck(this js.Value, args []js.Value) interface{} {
+	codeText := args[0].String() // get the parameters
+	var src = source_string.GetSource(codeText)
+	var stdout = stdout_web.CreateStdoutWeb()
+	Check(src, &stdout)
+
+	return nil
+}
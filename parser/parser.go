@@ -2,17 +2,20 @@ package parser
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/VadimZvf/golang/ast"
 	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/ast_resolver"
 	"github.com/VadimZvf/golang/token_function_declaration"
 	"github.com/VadimZvf/golang/token_variable_declaration"
 	"github.com/VadimZvf/golang/tokenizer"
 	"github.com/VadimZvf/golang/tokenizer_buffer"
 )
 
+// Code of program in UTF-8, leading byte order mark is skipped
 type ISource interface {
-	NextSymbol() (symbol rune, isEnd bool)
+	io.Reader
 }
 
 type iStdout interface {
@@ -72,6 +75,11 @@ func (parser *Parser) Parse(isDebug bool) (*ast_node.ASTNode, error) {
 
 	var ast, astError = ast.CreateAST(tokens)
 
+	// Variables get slots only in complete tree
+	if astError == nil {
+		astError = ast_resolver.Resolve(ast)
+	}
+
 	if isDebug && ast != nil {
 		parser.stdout.Print("___________________AST_______________________\n")
 		printASTNode(parser.stdout, ast, 0, false)
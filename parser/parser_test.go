@@ -2,9 +2,11 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
 	"github.com/VadimZvf/golang/source_mock"
 )
 
@@ -194,6 +196,7 @@ func TestStringVariable(t *testing.T) {
 func TestReferenceNumberAssignment(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
 	bar = 777;
+	var bar;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -234,6 +237,19 @@ func TestReferenceNumberAssignment(t *testing.T) {
 				StartPosition: 6,
 				EndPosition:   6,
 			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "bar",
+						StartPosition: 18,
+						EndPosition:   20,
+					},
+				},
+				StartPosition: 14,
+				EndPosition:   20,
+			},
 		},
 	}
 
@@ -253,6 +269,7 @@ func TestReferenceNumberAssignment(t *testing.T) {
 func TestNumberSumm(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
 	bar = 3 + 9;
+	var bar;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -321,6 +338,19 @@ func TestNumberSumm(t *testing.T) {
 				StartPosition: 6,
 				EndPosition:   6,
 			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "bar",
+						StartPosition: 20,
+						EndPosition:   22,
+					},
+				},
+				StartPosition: 16,
+				EndPosition:   22,
+			},
 		},
 	}
 
@@ -340,6 +370,9 @@ func TestNumberSumm(t *testing.T) {
 func TestReferenceSumm(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
 	bar = foo + baz;
+	var bar;
+	var foo;
+	var baz;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -408,6 +441,45 @@ func TestReferenceSumm(t *testing.T) {
 				StartPosition: 6,
 				EndPosition:   6,
 			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "bar",
+						StartPosition: 24,
+						EndPosition:   26,
+					},
+				},
+				StartPosition: 20,
+				EndPosition:   26,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "foo",
+						StartPosition: 34,
+						EndPosition:   36,
+					},
+				},
+				StartPosition: 30,
+				EndPosition:   36,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "baz",
+						StartPosition: 44,
+						EndPosition:   46,
+					},
+				},
+				StartPosition: 40,
+				EndPosition:   46,
+			},
 		},
 	}
 
@@ -427,6 +499,8 @@ func TestReferenceSumm(t *testing.T) {
 func TestReferenceWithNumberSumm(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
 	bar = foo + 55;
+	var bar;
+	var foo;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -495,6 +569,32 @@ func TestReferenceWithNumberSumm(t *testing.T) {
 				StartPosition: 6,
 				EndPosition:   6,
 			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "bar",
+						StartPosition: 23,
+						EndPosition:   25,
+					},
+				},
+				StartPosition: 19,
+				EndPosition:   25,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "foo",
+						StartPosition: 33,
+						EndPosition:   35,
+					},
+				},
+				StartPosition: 29,
+				EndPosition:   35,
+			},
 		},
 	}
 
@@ -514,6 +614,8 @@ func TestReferenceWithNumberSumm(t *testing.T) {
 func TestParenthesizedExpression(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
 	bar = (foo + 55);
+	var bar;
+	var foo;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -589,6 +691,32 @@ func TestParenthesizedExpression(t *testing.T) {
 				StartPosition: 6,
 				EndPosition:   6,
 			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "bar",
+						StartPosition: 25,
+						EndPosition:   27,
+					},
+				},
+				StartPosition: 21,
+				EndPosition:   27,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "foo",
+						StartPosition: 35,
+						EndPosition:   37,
+					},
+				},
+				StartPosition: 31,
+				EndPosition:   37,
+			},
 		},
 	}
 
@@ -608,6 +736,8 @@ func TestParenthesizedExpression(t *testing.T) {
 func TestTwoParenthesizedExpression(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
 	baz = (foo + 55) / (9 - 3);
+	var baz;
+	var foo;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -746,6 +876,32 @@ func TestTwoParenthesizedExpression(t *testing.T) {
 				StartPosition: 6,
 				EndPosition:   6,
 			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "baz",
+						StartPosition: 35,
+						EndPosition:   37,
+					},
+				},
+				StartPosition: 31,
+				EndPosition:   37,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "foo",
+						StartPosition: 45,
+						EndPosition:   47,
+					},
+				},
+				StartPosition: 41,
+				EndPosition:   47,
+			},
 		},
 	}
 
@@ -768,6 +924,7 @@ func TestFunctionDeclaration(t *testing.T) {
 		var a = 1;
 		b = 2;
 	};
+	var b;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -876,6 +1033,19 @@ func TestFunctionDeclaration(t *testing.T) {
 				StartPosition: 2,
 				EndPosition:   42,
 			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "b",
+						StartPosition: 50,
+						EndPosition:   50,
+					},
+				},
+				StartPosition: 46,
+				EndPosition:   50,
+			},
 		},
 	}
 
@@ -990,9 +1160,11 @@ func TestFunctionDeclarationWithReturn(t *testing.T) {
 	}
 }
 
-func TestReadProperty(t *testing.T) {
+func TestFunctionDeclarationWithEmptyReturn(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
-	a.b + 23
+	function baz() {
+		return;
+	};
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -1001,60 +1173,31 @@ func TestReadProperty(t *testing.T) {
 		Code: ast_node.AST_NODE_CODE_ROOT,
 		Body: []*ast_node.ASTNode{
 			{
-				Code: ast_node.AST_NODE_CODE_BINARY_EXPRESSION,
+				Code: ast_node.AST_NODE_CODE_FUNCTION,
 				Params: []ast_node.ASTNodeParam{
 					{
-						Name:          ast_node.AST_PARAM_BINARY_EXPRESSION_TYPE,
-						Value:         "+",
-						StartPosition: 6,
-						EndPosition:   6,
+						Name:          ast_node.AST_PARAM_FUNCTION_NAME,
+						Value:         "baz",
+						StartPosition: 11,
+						EndPosition:   13,
 					},
 				},
 				Body: []*ast_node.ASTNode{
 					{
-						Code: ast_node.AST_NODE_CODE_READ_PROP,
-						Params: []ast_node.ASTNodeParam{
-							{
-								Name:          ast_node.AST_PARAM_PROPERTY_NAME,
-								Value:         "b",
-								StartPosition: 4,
-								EndPosition:   4,
-							},
-						},
+						Code: ast_node.AST_NODE_CODE_BLOCK,
 						Body: []*ast_node.ASTNode{
 							{
-								Code: ast_node.AST_NODE_CODE_REFERENCE,
-								Params: []ast_node.ASTNodeParam{
-									{
-										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
-										Value:         "a",
-										StartPosition: 2,
-										EndPosition:   2,
-									},
-								},
-								StartPosition: 2,
-								EndPosition:   2,
-							},
-						},
-						StartPosition: 3,
-						EndPosition:   3,
-					},
-					{
-						Code: ast_node.AST_NODE_CODE_NUMBER,
-						Params: []ast_node.ASTNodeParam{
-							{
-								Name:          ast_node.AST_PARAM_NUMBER_VALUE,
-								Value:         "23",
-								StartPosition: 8,
-								EndPosition:   9,
+								Code:          ast_node.AST_NODE_CODE_RETURN,
+								StartPosition: 21,
+								EndPosition:   26,
 							},
 						},
-						StartPosition: 8,
-						EndPosition:   9,
+						StartPosition: 17,
+						EndPosition:   30,
 					},
 				},
-				StartPosition: 6,
-				EndPosition:   6,
+				StartPosition: 2,
+				EndPosition:   30,
 			},
 		},
 	}
@@ -1072,9 +1215,10 @@ func TestReadProperty(t *testing.T) {
 	}
 }
 
-func TestReadTwoProperties(t *testing.T) {
+func TestForOfLoop(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
-	a.foo.bar = 12
+	for (item of items) { break }
+	var items;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -1083,67 +1227,59 @@ func TestReadTwoProperties(t *testing.T) {
 		Code: ast_node.AST_NODE_CODE_ROOT,
 		Body: []*ast_node.ASTNode{
 			{
-				Code: ast_node.AST_NODE_CODE_ASSIGNMENT,
-				Body: []*ast_node.ASTNode{
+				Code: ast_node.AST_NODE_CODE_FOR_OF,
+				Params: []ast_node.ASTNodeParam{
 					{
-						Code: ast_node.AST_NODE_CODE_READ_PROP,
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "item",
+						StartPosition: 7,
+						EndPosition:   10,
+					},
+				},
+				Arguments: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_REFERENCE,
 						Params: []ast_node.ASTNodeParam{
 							{
-								Name:          ast_node.AST_PARAM_PROPERTY_NAME,
-								Value:         "bar",
-								StartPosition: 8,
-								EndPosition:   10,
+								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+								Value:         "items",
+								StartPosition: 15,
+								EndPosition:   19,
 							},
 						},
+						StartPosition: 15,
+						EndPosition:   19,
+					},
+				},
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_BLOCK,
 						Body: []*ast_node.ASTNode{
 							{
-								Code: ast_node.AST_NODE_CODE_READ_PROP,
-								Params: []ast_node.ASTNodeParam{
-									{
-										Name:          ast_node.AST_PARAM_PROPERTY_NAME,
-										Value:         "foo",
-										StartPosition: 4,
-										EndPosition:   6,
-									},
-								},
-								Body: []*ast_node.ASTNode{
-									{
-										Code: ast_node.AST_NODE_CODE_REFERENCE,
-										Params: []ast_node.ASTNodeParam{
-											{
-												Name:          ast_node.AST_PARAM_VARIABLE_NAME,
-												Value:         "a",
-												StartPosition: 2,
-												EndPosition:   2,
-											},
-										},
-										StartPosition: 2,
-										EndPosition:   2,
-									},
-								},
-								StartPosition: 3,
-								EndPosition:   3,
+								Code:          ast_node.AST_NODE_CODE_BREAK,
+								StartPosition: 24,
+								EndPosition:   28,
 							},
 						},
-						StartPosition: 7,
-						EndPosition:   7,
+						StartPosition: 22,
+						EndPosition:   30,
 					},
+				},
+				StartPosition: 2,
+				EndPosition:   30,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
 					{
-						Code: ast_node.AST_NODE_CODE_NUMBER,
-						Params: []ast_node.ASTNodeParam{
-							{
-								Name:          ast_node.AST_PARAM_NUMBER_VALUE,
-								Value:         "12",
-								StartPosition: 14,
-								EndPosition:   15,
-							},
-						},
-						StartPosition: 14,
-						EndPosition:   15,
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "items",
+						StartPosition: 37,
+						EndPosition:   41,
 					},
 				},
-				StartPosition: 12,
-				EndPosition:   12,
+				StartPosition: 33,
+				EndPosition:   41,
 			},
 		},
 	}
@@ -1161,9 +1297,9 @@ func TestReadTwoProperties(t *testing.T) {
 	}
 }
 
-func TestCallFunction(t *testing.T) {
+func TestAsyncFunctionDeclaration(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
-	a()
+	async function baz() {}
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -1172,24 +1308,787 @@ func TestCallFunction(t *testing.T) {
 		Code: ast_node.AST_NODE_CODE_ROOT,
 		Body: []*ast_node.ASTNode{
 			{
-				Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
-				Body: []*ast_node.ASTNode{
+				Code: ast_node.AST_NODE_CODE_FUNCTION,
+				Params: []ast_node.ASTNodeParam{
 					{
-						Code: ast_node.AST_NODE_CODE_REFERENCE,
-						Params: []ast_node.ASTNodeParam{
-							{
-								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
-								Value:         "a",
-								StartPosition: 2,
-								EndPosition:   2,
-							},
-						},
-						StartPosition: 2,
-						EndPosition:   2,
+						Name:          ast_node.AST_PARAM_FUNCTION_NAME,
+						Value:         "baz",
+						StartPosition: 17,
+						EndPosition:   19,
+					},
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_ASYNC,
+						Value:         "async",
+						StartPosition: 2,
+						EndPosition:   6,
+					},
+				},
+				Body: []*ast_node.ASTNode{
+					{
+						Code:          ast_node.AST_NODE_CODE_BLOCK,
+						StartPosition: 23,
+						EndPosition:   24,
+					},
+				},
+				StartPosition: 2,
+				EndPosition:   24,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestSpawn(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	spawn worker(ch)
+	var worker;
+	var ch;
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_SPAWN,
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
+						Body: []*ast_node.ASTNode{
+							{
+								Code: ast_node.AST_NODE_CODE_REFERENCE,
+								Params: []ast_node.ASTNodeParam{
+									{
+										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+										Value:         "worker",
+										StartPosition: 8,
+										EndPosition:   13,
+									},
+								},
+								StartPosition: 8,
+								EndPosition:   13,
+							},
+						},
+						Arguments: []*ast_node.ASTNode{
+							{
+								Code: ast_node.AST_NODE_CODE_REFERENCE,
+								Params: []ast_node.ASTNodeParam{
+									{
+										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+										Value:         "ch",
+										StartPosition: 15,
+										EndPosition:   16,
+									},
+								},
+								StartPosition: 15,
+								EndPosition:   16,
+							},
+						},
+						StartPosition: 14,
+						EndPosition:   17,
+					},
+				},
+				StartPosition: 2,
+				EndPosition:   6,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "worker",
+						StartPosition: 24,
+						EndPosition:   29,
+					},
+				},
+				StartPosition: 20,
+				EndPosition:   29,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "ch",
+						StartPosition: 37,
+						EndPosition:   38,
+					},
+				},
+				StartPosition: 33,
+				EndPosition:   38,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestIndex(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	text[1]
+	var text;
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_INDEX,
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_REFERENCE,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+								Value:         "text",
+								StartPosition: 2,
+								EndPosition:   5,
+							},
+						},
+						StartPosition: 2,
+						EndPosition:   5,
+					},
+				},
+				Arguments: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_NUMBER,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_NUMBER_VALUE,
+								Value:         "1",
+								StartPosition: 7,
+								EndPosition:   7,
+							},
+						},
+						StartPosition: 7,
+						EndPosition:   7,
+					},
+				},
+				StartPosition: 6,
+				EndPosition:   8,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "text",
+						StartPosition: 15,
+						EndPosition:   18,
+					},
+				},
+				StartPosition: 11,
+				EndPosition:   18,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestReadProperty(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	a.b + 23
+	var a;
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_BINARY_EXPRESSION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_BINARY_EXPRESSION_TYPE,
+						Value:         "+",
+						StartPosition: 6,
+						EndPosition:   6,
+					},
+				},
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_READ_PROP,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_PROPERTY_NAME,
+								Value:         "b",
+								StartPosition: 4,
+								EndPosition:   4,
+							},
+						},
+						Body: []*ast_node.ASTNode{
+							{
+								Code: ast_node.AST_NODE_CODE_REFERENCE,
+								Params: []ast_node.ASTNodeParam{
+									{
+										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+										Value:         "a",
+										StartPosition: 2,
+										EndPosition:   2,
+									},
+								},
+								StartPosition: 2,
+								EndPosition:   2,
+							},
+						},
+						StartPosition: 3,
+						EndPosition:   3,
+					},
+					{
+						Code: ast_node.AST_NODE_CODE_NUMBER,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_NUMBER_VALUE,
+								Value:         "23",
+								StartPosition: 8,
+								EndPosition:   9,
+							},
+						},
+						StartPosition: 8,
+						EndPosition:   9,
+					},
+				},
+				StartPosition: 6,
+				EndPosition:   6,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "a",
+						StartPosition: 16,
+						EndPosition:   16,
+					},
+				},
+				StartPosition: 12,
+				EndPosition:   16,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestReadTwoProperties(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	a.foo.bar = 12
+	var a;
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_ASSIGNMENT,
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_READ_PROP,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_PROPERTY_NAME,
+								Value:         "bar",
+								StartPosition: 8,
+								EndPosition:   10,
+							},
+						},
+						Body: []*ast_node.ASTNode{
+							{
+								Code: ast_node.AST_NODE_CODE_READ_PROP,
+								Params: []ast_node.ASTNodeParam{
+									{
+										Name:          ast_node.AST_PARAM_PROPERTY_NAME,
+										Value:         "foo",
+										StartPosition: 4,
+										EndPosition:   6,
+									},
+								},
+								Body: []*ast_node.ASTNode{
+									{
+										Code: ast_node.AST_NODE_CODE_REFERENCE,
+										Params: []ast_node.ASTNodeParam{
+											{
+												Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+												Value:         "a",
+												StartPosition: 2,
+												EndPosition:   2,
+											},
+										},
+										StartPosition: 2,
+										EndPosition:   2,
+									},
+								},
+								StartPosition: 3,
+								EndPosition:   3,
+							},
+						},
+						StartPosition: 7,
+						EndPosition:   7,
+					},
+					{
+						Code: ast_node.AST_NODE_CODE_NUMBER,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_NUMBER_VALUE,
+								Value:         "12",
+								StartPosition: 14,
+								EndPosition:   15,
+							},
+						},
+						StartPosition: 14,
+						EndPosition:   15,
+					},
+				},
+				StartPosition: 12,
+				EndPosition:   12,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "a",
+						StartPosition: 22,
+						EndPosition:   22,
+					},
+				},
+				StartPosition: 18,
+				EndPosition:   22,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestCallFunction(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	a()
+	var a;
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_REFERENCE,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+								Value:         "a",
+								StartPosition: 2,
+								EndPosition:   2,
+							},
+						},
+						StartPosition: 2,
+						EndPosition:   2,
+					},
+				},
+				StartPosition: 3,
+				EndPosition:   4,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "a",
+						StartPosition: 11,
+						EndPosition:   11,
+					},
+				},
+				StartPosition: 7,
+				EndPosition:   11,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestCallFunctionWithProperty(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	bar.baz()
+	var bar;
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_READ_PROP,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_PROPERTY_NAME,
+								Value:         "baz",
+								StartPosition: 6,
+								EndPosition:   8,
+							},
+						},
+						Body: []*ast_node.ASTNode{
+							{
+								Code: ast_node.AST_NODE_CODE_REFERENCE,
+								Params: []ast_node.ASTNodeParam{
+									{
+										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+										Value:         "bar",
+										StartPosition: 2,
+										EndPosition:   4,
+									},
+								},
+								StartPosition: 2,
+								EndPosition:   4,
+							},
+						},
+						StartPosition: 5,
+						EndPosition:   5,
+					},
+				},
+				StartPosition: 9,
+				EndPosition:   10,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "bar",
+						StartPosition: 17,
+						EndPosition:   19,
+					},
+				},
+				StartPosition: 13,
+				EndPosition:   19,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestCallFunctionWithArguments(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	baz(bar(), "foo")
+	var baz;
+	var bar;
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
+				Body: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_REFERENCE,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+								Value:         "baz",
+								StartPosition: 2,
+								EndPosition:   4,
+							},
+						},
+						StartPosition: 2,
+						EndPosition:   4,
+					},
+				},
+				Arguments: []*ast_node.ASTNode{
+					{
+						Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
+						Body: []*ast_node.ASTNode{
+							{
+								Code: ast_node.AST_NODE_CODE_REFERENCE,
+								Params: []ast_node.ASTNodeParam{
+									{
+										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+										Value:         "bar",
+										StartPosition: 6,
+										EndPosition:   8,
+									},
+								},
+								StartPosition: 6,
+								EndPosition:   8,
+							},
+						},
+						StartPosition: 9,
+						EndPosition:   10,
+					},
+					{
+						Code: ast_node.AST_NODE_CODE_STRING,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_STRING_VALUE,
+								Value:         "foo",
+								StartPosition: 13,
+								EndPosition:   17,
+							},
+						},
+						StartPosition: 13,
+						EndPosition:   17,
+					},
+				},
+				StartPosition: 5,
+				EndPosition:   18,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "baz",
+						StartPosition: 25,
+						EndPosition:   27,
+					},
+				},
+				StartPosition: 21,
+				EndPosition:   27,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "bar",
+						StartPosition: 35,
+						EndPosition:   37,
+					},
+				},
+				StartPosition: 31,
+				EndPosition:   37,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func compareAst(first *ast_node.ASTNode, second *ast_node.ASTNode) string {
+	return compareNodes(first, second)
+}
+
+func compareNodes(first *ast_node.ASTNode, second *ast_node.ASTNode) string {
+	if first.Code != second.Code {
+		return "Different codes in nodes. First: " + first.Code + " Second: " + second.Code
+	}
+
+	if first.StartPosition != second.StartPosition {
+		return "Different start position in nodes. First code: " + first.Code + " Second code: " + second.Code + "\n" + "First start position: " + fmt.Sprint(first.StartPosition) + " Second: " + fmt.Sprint(second.StartPosition)
+	}
+
+	if first.EndPosition != second.EndPosition {
+		return "Different end position in nodes. First code: " + first.Code + " Second code: " + second.Code + "\n" + "First end position: " + fmt.Sprint(first.EndPosition) + " Second: " + fmt.Sprint(second.EndPosition)
+	}
+
+	var diffInParams = compareNodesParams(first, second)
+
+	if len(diffInParams) > 0 {
+		return "Diff in nodes. First: " + first.Code + " Second: " + second.Code + "\n" + diffInParams
+	}
+
+	if len(first.Body) != len(second.Body) {
+		return "Different body size. First code: " + first.Code + " Second code: " + second.Code + "\n" + "First body size: " + fmt.Sprint(len(first.Body)) + " Second: " + fmt.Sprint(len(second.Body))
+	}
+
+	for i := 0; i < len(first.Body); i++ {
+		var firstChild = first.Body[i]
+		var secondChild = second.Body[i]
+
+		var childDiff = compareNodes(firstChild, secondChild)
+
+		if len(childDiff) > 0 {
+			return "Diff in child nodes. First parent node: " + first.Code + " Second parent node: " + second.Code + "\n" + childDiff
+		}
+	}
+
+	return ""
+}
+
+func compareNodesParams(first *ast_node.ASTNode, second *ast_node.ASTNode) string {
+	if len(first.Params) != len(second.Params) {
+		return "Different params count. First: " + fmt.Sprint(len(first.Params)) + " Second: " + fmt.Sprint(len(second.Params))
+	}
+
+	for i := 0; i < len(first.Params); i++ {
+		var firstParam = first.Params[i]
+		var secondParam = second.Params[i]
+
+		if firstParam.Name != secondParam.Name {
+			return "Different param name. First: " + firstParam.Name + " Second: " + secondParam.Name
+		}
+
+		if firstParam.Value != secondParam.Value {
+			return "Different param value. First: " + firstParam.Value + " Second: " + secondParam.Value
+		}
+
+		if firstParam.StartPosition != secondParam.StartPosition {
+			return "Different param start position. First: " + fmt.Sprint(firstParam.StartPosition) + " Second: " + fmt.Sprint(secondParam.StartPosition)
+		}
+
+		if firstParam.EndPosition != secondParam.EndPosition {
+			return "Different param end position. First: " + fmt.Sprint(firstParam.EndPosition) + " Second: " + fmt.Sprint(secondParam.EndPosition)
+		}
+	}
+
+	return ""
+}
+
+type mockStdout struct{}
+
+func (std *mockStdout) Print(line string)      {}
+func (std *mockStdout) PrintError(line string) {}
+
+func createMockStdout() *mockStdout {
+	return &mockStdout{}
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	function f(a: number): string {}
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_FUNCTION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_NAME,
+						Value:         "f",
+						StartPosition: 11,
+						EndPosition:   11,
+					},
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_ARGUMENT_NAME,
+						Value:         "a",
+						StartPosition: 13,
+						EndPosition:   13,
+					},
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_ARGUMENT_TYPE,
+						Value:         "number",
+						StartPosition: 16,
+						EndPosition:   21,
+					},
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_RETURN_TYPE,
+						Value:         "string",
+						StartPosition: 25,
+						EndPosition:   30,
 					},
 				},
-				StartPosition: 3,
-				EndPosition:   4,
+				Body: []*ast_node.ASTNode{
+					{
+						Code:          ast_node.AST_NODE_CODE_BLOCK,
+						StartPosition: 32,
+						EndPosition:   33,
+					},
+				},
+				StartPosition: 2,
+				EndPosition:   33,
 			},
 		},
 	}
@@ -1207,9 +2106,9 @@ func TestCallFunction(t *testing.T) {
 	}
 }
 
-func TestCallFunctionWithProperty(t *testing.T) {
+func TestVariableTypeAnnotation(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
-	bar.baz()
+	var a: number;
 `)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
@@ -1218,39 +2117,105 @@ func TestCallFunctionWithProperty(t *testing.T) {
 		Code: ast_node.AST_NODE_CODE_ROOT,
 		Body: []*ast_node.ASTNode{
 			{
-				Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "a",
+						StartPosition: 6,
+						EndPosition:   6,
+					},
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_TYPE,
+						Value:         "number",
+						StartPosition: 9,
+						EndPosition:   14,
+					},
+				},
+				StartPosition: 2,
+				EndPosition:   14,
+			},
+		},
+	}
+
+	var diff = compareAst(ast, &expectedAst)
+
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
+	}
+
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
+
+func TestEnum(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	enum Color { Red, Blue = 10 }
+`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
+
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_ENUM,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "Color",
+						StartPosition: 7,
+						EndPosition:   11,
+					},
+				},
 				Body: []*ast_node.ASTNode{
 					{
-						Code: ast_node.AST_NODE_CODE_READ_PROP,
+						Code: ast_node.AST_NODE_CODE_ENUM_MEMBER,
 						Params: []ast_node.ASTNodeParam{
 							{
-								Name:          ast_node.AST_PARAM_PROPERTY_NAME,
-								Value:         "baz",
-								StartPosition: 6,
-								EndPosition:   8,
+								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+								Value:         "Red",
+								StartPosition: 15,
+								EndPosition:   17,
+							},
+						},
+						StartPosition: 15,
+						EndPosition:   17,
+					},
+					{
+						Code: ast_node.AST_NODE_CODE_ENUM_MEMBER,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+								Value:         "Blue",
+								StartPosition: 20,
+								EndPosition:   23,
 							},
 						},
 						Body: []*ast_node.ASTNode{
 							{
-								Code: ast_node.AST_NODE_CODE_REFERENCE,
+								Code: ast_node.AST_NODE_CODE_NUMBER,
 								Params: []ast_node.ASTNodeParam{
 									{
-										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
-										Value:         "bar",
-										StartPosition: 2,
-										EndPosition:   4,
+										Name:          ast_node.AST_PARAM_NUMBER_VALUE,
+										Value:         "10",
+										StartPosition: 27,
+										EndPosition:   28,
 									},
 								},
-								StartPosition: 2,
-								EndPosition:   4,
+								StartPosition: 27,
+								EndPosition:   28,
 							},
 						},
-						StartPosition: 5,
-						EndPosition:   5,
+						StartPosition: 20,
+						EndPosition:   28,
 					},
 				},
-				StartPosition: 9,
-				EndPosition:   10,
+				StartPosition: 2,
+				EndPosition:   30,
 			},
 		},
 	}
@@ -1268,10 +2233,26 @@ func TestCallFunctionWithProperty(t *testing.T) {
 	}
 }
 
-func TestCallFunctionWithArguments(t *testing.T) {
-	var src = source_mock.GetSourceMock(`
-	baz(bar(), "foo")
-`)
+func TestEnumDuplicateMember(t *testing.T) {
+	var src = source_mock.GetSourceMock(`enum Color { Red, Green, Red }`)
+	var parser = CreateParser(src, createMockStdout())
+	var _, err = parser.Parse(false)
+
+	if err == nil {
+		t.Errorf("Should fail with duplicate member")
+		return
+	}
+
+	var parserErr, isParserErr = err.(parser_error.ParserError)
+
+	if !isParserErr || parserErr.Message != "Duplicate enum member: Red" || parserErr.StartPosition != 25 || parserErr.EndPosition != 27 {
+		t.Errorf("Should report duplicate member, but received: %v", err)
+	}
+}
+
+func TestNamedArguments(t *testing.T) {
+	var src = source_mock.GetSourceMock(`f(1, admin: true)
+var f;`)
 	var parser = CreateParser(src, createMockStdout())
 	var ast, err = parser.Parse(false)
 
@@ -1286,52 +2267,73 @@ func TestCallFunctionWithArguments(t *testing.T) {
 						Params: []ast_node.ASTNodeParam{
 							{
 								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
-								Value:         "baz",
-								StartPosition: 2,
-								EndPosition:   4,
+								Value:         "f",
+								StartPosition: 0,
+								EndPosition:   0,
 							},
 						},
-						StartPosition: 2,
-						EndPosition:   4,
+						StartPosition: 0,
+						EndPosition:   0,
 					},
 				},
 				Arguments: []*ast_node.ASTNode{
 					{
-						Code: ast_node.AST_NODE_CODE_CALL_EXPRESSION,
+						Code: ast_node.AST_NODE_CODE_NUMBER,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_NUMBER_VALUE,
+								Value:         "1",
+								StartPosition: 2,
+								EndPosition:   2,
+							},
+						},
+						StartPosition: 2,
+						EndPosition:   2,
+					},
+					{
+						Code: ast_node.AST_NODE_CODE_NAMED_ARGUMENT,
+						Params: []ast_node.ASTNodeParam{
+							{
+								Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+								Value:         "admin",
+								StartPosition: 5,
+								EndPosition:   9,
+							},
+						},
 						Body: []*ast_node.ASTNode{
 							{
-								Code: ast_node.AST_NODE_CODE_REFERENCE,
+								Code: ast_node.AST_NODE_CODE_BOOLEAN,
 								Params: []ast_node.ASTNodeParam{
 									{
-										Name:          ast_node.AST_PARAM_VARIABLE_NAME,
-										Value:         "bar",
-										StartPosition: 6,
-										EndPosition:   8,
+										Name:          ast_node.AST_PARAM_BOOLEAN_VALUE,
+										Value:         "true",
+										StartPosition: 12,
+										EndPosition:   15,
 									},
 								},
-								StartPosition: 6,
-								EndPosition:   8,
+								StartPosition: 12,
+								EndPosition:   15,
 							},
 						},
-						StartPosition: 9,
-						EndPosition:   10,
+						StartPosition: 5,
+						EndPosition:   15,
 					},
+				},
+				StartPosition: 1,
+				EndPosition:   16,
+			},
+			{
+				Code: ast_node.AST_NODE_CODE_VARIABLE_DECLARATION,
+				Params: []ast_node.ASTNodeParam{
 					{
-						Code: ast_node.AST_NODE_CODE_STRING,
-						Params: []ast_node.ASTNodeParam{
-							{
-								Name:          ast_node.AST_PARAM_STRING_VALUE,
-								Value:         "foo",
-								StartPosition: 13,
-								EndPosition:   17,
-							},
-						},
-						StartPosition: 13,
-						EndPosition:   17,
+						Name:          ast_node.AST_PARAM_VARIABLE_NAME,
+						Value:         "f",
+						StartPosition: 22,
+						EndPosition:   22,
 					},
 				},
-				StartPosition: 5,
-				EndPosition:   18,
+				StartPosition: 18,
+				EndPosition:   22,
 			},
 		},
 	}
@@ -1349,81 +2351,124 @@ func TestCallFunctionWithArguments(t *testing.T) {
 	}
 }
 
-func compareAst(first *ast_node.ASTNode, second *ast_node.ASTNode) string {
-	return compareNodes(first, second)
-}
+func TestPositionalArgumentAfterNamed(t *testing.T) {
+	var src = source_mock.GetSourceMock(`f(admin: true, 1)`)
+	var parser = CreateParser(src, createMockStdout())
+	var _, err = parser.Parse(false)
 
-func compareNodes(first *ast_node.ASTNode, second *ast_node.ASTNode) string {
-	if first.Code != second.Code {
-		return "Different codes in nodes. First: " + first.Code + " Second: " + second.Code
+	if err == nil {
+		t.Errorf("Should fail with positional argument after named")
+		return
 	}
 
-	if first.StartPosition != second.StartPosition {
-		return "Different start position in nodes. First code: " + first.Code + " Second code: " + second.Code + "\n" + "First start position: " + fmt.Sprint(first.StartPosition) + " Second: " + fmt.Sprint(second.StartPosition)
-	}
+	var parserErr, isParserErr = err.(parser_error.ParserError)
 
-	if first.EndPosition != second.EndPosition {
-		return "Different end position in nodes. First code: " + first.Code + " Second code: " + second.Code + "\n" + "First end position: " + fmt.Sprint(first.EndPosition) + " Second: " + fmt.Sprint(second.EndPosition)
+	if !isParserErr || !strings.HasPrefix(parserErr.Message, "Positional argument should be before named arguments") || parserErr.StartPosition != 15 || parserErr.EndPosition != 15 {
+		t.Errorf("Should report positional argument, but received: %#v", err)
 	}
+}
 
-	var diffInParams = compareNodesParams(first, second)
+func TestDeferWithoutCall(t *testing.T) {
+	var src = source_mock.GetSourceMock(`function f() { defer value; }`)
+	var parser = CreateParser(src, createMockStdout())
+	var _, err = parser.Parse(false)
 
-	if len(diffInParams) > 0 {
-		return "Diff in nodes. First: " + first.Code + " Second: " + second.Code + "\n" + diffInParams
+	if err == nil {
+		t.Errorf("Should fail with defer without call")
+		return
 	}
 
-	if len(first.Body) != len(second.Body) {
-		return "Different body size. First code: " + first.Code + " Second code: " + second.Code + "\n" + "First body size: " + fmt.Sprint(len(first.Body)) + " Second: " + fmt.Sprint(len(second.Body))
+	if !strings.HasPrefix(err.Error(), "Defer should be followed by function call") {
+		t.Errorf("Should report defer without call, but received: \"%s\"", err.Error())
 	}
+}
 
-	for i := 0; i < len(first.Body); i++ {
-		var firstChild = first.Body[i]
-		var secondChild = second.Body[i]
-
-		var childDiff = compareNodes(firstChild, secondChild)
+func TestDecorators(t *testing.T) {
+	var src = source_mock.GetSourceMock(`@memo @trace function f() {}`)
+	var parser = CreateParser(src, createMockStdout())
+	var ast, err = parser.Parse(false)
 
-		if len(childDiff) > 0 {
-			return "Diff in child nodes. First parent node: " + first.Code + " Second parent node: " + second.Code + "\n" + childDiff
-		}
+	var expectedAst = ast_node.ASTNode{
+		Code: ast_node.AST_NODE_CODE_ROOT,
+		Body: []*ast_node.ASTNode{
+			{
+				Code: ast_node.AST_NODE_CODE_FUNCTION,
+				Params: []ast_node.ASTNodeParam{
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_NAME,
+						Value:         "f",
+						StartPosition: 22,
+						EndPosition:   22,
+					},
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_DECORATOR,
+						Value:         "memo",
+						StartPosition: 1,
+						EndPosition:   4,
+					},
+					{
+						Name:          ast_node.AST_PARAM_FUNCTION_DECORATOR,
+						Value:         "trace",
+						StartPosition: 7,
+						EndPosition:   11,
+					},
+				},
+				Body: []*ast_node.ASTNode{
+					{
+						Code:          ast_node.AST_NODE_CODE_BLOCK,
+						StartPosition: 26,
+						EndPosition:   27,
+					},
+				},
+				StartPosition: 0,
+				EndPosition:   27,
+			},
+		},
 	}
 
-	return ""
-}
+	var diff = compareAst(ast, &expectedAst)
 
-func compareNodesParams(first *ast_node.ASTNode, second *ast_node.ASTNode) string {
-	if len(first.Params) != len(second.Params) {
-		return "Different params count. First: " + fmt.Sprint(len(first.Params)) + " Second: " + fmt.Sprint(len(second.Params))
+	if len(diff) > 0 {
+		t.Errorf("Different AST")
+		t.Errorf("Message: %s", diff)
 	}
 
-	for i := 0; i < len(first.Params); i++ {
-		var firstParam = first.Params[i]
-		var secondParam = second.Params[i]
+	if err != nil {
+		t.Errorf("Should parse without errors")
+		t.Errorf("Failed with message: %s", err.Error())
+	}
+}
 
-		if firstParam.Name != secondParam.Name {
-			return "Different param name. First: " + firstParam.Name + " Second: " + secondParam.Name
-		}
+func TestDecoratorWithoutFunction(t *testing.T) {
+	var src = source_mock.GetSourceMock(`@memo var a = 1;`)
+	var parser = CreateParser(src, createMockStdout())
+	var _, err = parser.Parse(false)
 
-		if firstParam.Value != secondParam.Value {
-			return "Different param value. First: " + firstParam.Value + " Second: " + secondParam.Value
-		}
+	if err == nil {
+		t.Errorf("Should fail with decorator without function")
+		return
+	}
 
-		if firstParam.StartPosition != secondParam.StartPosition {
-			return "Different param start position. First: " + fmt.Sprint(firstParam.StartPosition) + " Second: " + fmt.Sprint(secondParam.StartPosition)
-		}
+	var parserErr, isParserErr = err.(parser_error.ParserError)
 
-		if firstParam.EndPosition != secondParam.EndPosition {
-			return "Different param end position. First: " + fmt.Sprint(firstParam.EndPosition) + " Second: " + fmt.Sprint(secondParam.EndPosition)
-		}
+	if !isParserErr || !strings.HasPrefix(parserErr.Message, "Decorator should be followed by function declaration") || parserErr.StartPosition != 0 || parserErr.EndPosition != 4 {
+		t.Errorf("Should report decorator without function, but received: %#v", err)
 	}
-
-	return ""
 }
 
-type mockStdout struct{}
+func TestUndeclaredVariable(t *testing.T) {
+	var src = source_mock.GetSourceMock(`var a = b + 1;`)
+	var parser = CreateParser(src, createMockStdout())
+	var _, err = parser.Parse(false)
 
-func (std *mockStdout) Print(line string)      {}
-func (std *mockStdout) PrintError(line string) {}
+	if err == nil {
+		t.Errorf("Should fail with undeclared variable")
+		return
+	}
 
-func createMockStdout() *mockStdout {
-	return &mockStdout{}
+	var parserErr, isParserErr = err.(parser_error.ParserError)
+
+	if !isParserErr || parserErr.Message != "Variable not declared: b" || parserErr.StartPosition != 8 || parserErr.EndPosition != 8 {
+		t.Errorf("Should report undeclared variable, but received: %#v", err)
+	}
 }
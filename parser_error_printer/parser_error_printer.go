@@ -16,28 +16,44 @@ func PrintError(code string, std iStdout, err error) {
 		panic(err)
 	}
 
+	// Positions are counted in symbols, so slice code by runes
+	var symbols = []rune(code)
+	var startPosition = clamp(re.StartPosition, 0, len(symbols))
+	var endPosition = clamp(re.EndPosition, startPosition-1, len(symbols)-1)
 	var i = 0
 
-	for ; i < re.StartPosition; i++ {
-		std.Print(string(code[i]))
+	for ; i < startPosition; i++ {
+		std.Print(string(symbols[i]))
 	}
 
 	// Print error parth
-	for ; i <= re.EndPosition; i++ {
-		std.PrintError(string(code[i]))
+	for ; i <= endPosition; i++ {
+		std.PrintError(string(symbols[i]))
 	}
 
 	// Print valid part, at the same line
-	for ; i < len(code) && string(code[i]) != "\n"; i++ {
-		std.Print(string(code[i]))
+	for ; i < len(symbols) && symbols[i] != '\n'; i++ {
+		std.Print(string(symbols[i]))
 	}
 
 	std.Print("\n")
 	std.PrintError(re.Message + "\n")
 
-	for ; i < len(code); i++ {
-		std.Print(string(code[i]))
+	for ; i < len(symbols); i++ {
+		std.Print(string(symbols[i]))
 	}
 
 	std.Print("\n")
 }
+
+func clamp(value int, lower int, upper int) int {
+	if value < lower {
+		return lower
+	}
+
+	if value > upper {
+		return upper
+	}
+
+	return value
+}
@@ -1,17 +1,25 @@
 package runtime
 
 import (
-	"strconv"
+	"math"
+	"math/rand"
+	"sync"
 
 	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/ast_resolver"
+	"github.com/VadimZvf/golang/runtime_bytecode"
+	"github.com/VadimZvf/golang/runtime_clock"
 	"github.com/VadimZvf/golang/runtime_error"
 	"github.com/VadimZvf/golang/runtime_heap"
+	"github.com/VadimZvf/golang/token_number"
 )
 
 type iHeap interface {
-	CreateVariable(name string) error
-	SetVariable(name string, variable *runtime_heap.VariableValue) error
-	GetVariable(name string) *runtime_heap.VariableValue
+	CreateVariable(index int) error
+	SetVariable(slot ast_node.VariableSlot, variable *runtime_heap.VariableValue) error
+	GetVariable(slot ast_node.VariableSlot) *runtime_heap.VariableValue
+	GetValues() []*runtime_heap.VariableValue
+	DefineVariable(index int, variable *runtime_heap.VariableValue) error
 	SetParentHeap(parent interface{})
 }
 
@@ -20,45 +28,157 @@ type IBridge interface {
 }
 
 type Runtime struct {
-	heap   iHeap
-	bridge IBridge
+	heap    iHeap
+	bridge  IBridge
+	frame   *frame
+	program *program
+	// Call of function, which body runs by runtime. Nil for top level code
+	call *callSite
+}
+
+// State shared by all calls of one program run
+type program struct {
+	coroutines []*coroutine
+	// Coroutines of generators, which were collected by garbage collector
+	released     []*coroutine
+	releasedLock sync.Mutex
+	loop         *eventLoop
+	// Tasks, which were not finished yet, and the one, which runs now
+	tasks       []*task
+	currentTask *task
+	// Type annotations are checked on assignments and calls
+	isChecked bool
+	// Nesting of calls of traced functions, used for indentation of trace
+	traceDepth int
+	// Code is compiled to bytecode and runs by stack machine
+	isBytecode bool
+	chunks     map[*ast_node.ASTNode]*runtime_bytecode.Chunk
+	// Parsed number literals. Values are immutable, so every run of literal gets the same value
+	literals map[*ast_node.ASTNode]*runtime_heap.VariableValue
+	limits   limits
 }
 
 func CreateRuntime(bridge IBridge) Runtime {
-	var heap = runtime_heap.CreateHeap()
+	// Variables of program are added after build in functions
+	var heap = runtime_heap.CreateHeap(len(ast_resolver.BUILD_IN_NAMES))
+	var clock = runtime_clock.CreateRealClock()
 
 	var rt = Runtime{
 		heap:   &heap,
 		bridge: bridge,
+		frame:  createFrame(),
+		program: &program{
+			loop: createEventLoop(&clock),
+			limits: limits{
+				options: Options{MaxCallDepth: DEFAULT_MAX_CALL_DEPTH},
+			},
+		},
 	}
 	rt.defineEnvByBridge()
 
 	return rt
 }
 
-type Visitor func(*ast_node.ASTNode) (*runtime_heap.VariableValue, error)
+// Replaces real clock of timers, for example by virtual one
+func (runtime *Runtime) SetClock(clock IClock) {
+	runtime.program.loop.clock = clock
+}
+
+// Makes scheduler pick ready tasks in pseudo-random, but reproducible order
+func (runtime *Runtime) SetSchedulerSeed(seed int64) {
+	runtime.program.loop.random = rand.New(rand.NewSource(seed))
+}
+
+// Makes runtime check values of annotated variables, arguments and returns
+func (runtime *Runtime) SetCheckedMode(isChecked bool) {
+	runtime.program.isChecked = isChecked
+}
+
+// Makes runtime compile code to bytecode and run it by stack machine, instead of walking AST
+func (runtime *Runtime) SetBytecodeMode(isBytecode bool) {
+	runtime.program.isBytecode = isBytecode
+}
+
+// Runtime for function call, with own heap and frame, but shared program state.
+// Built-in functions are found through closure heaps, so they are not defined again
+func (runtime *Runtime) createCallRuntime(scopeSize int, call *callSite) Runtime {
+	var heap = runtime_heap.CreateHeap(scopeSize)
+
+	return Runtime{
+		heap:    &heap,
+		bridge:  runtime.bridge,
+		frame:   createFrame(),
+		program: runtime.program,
+		call:    call,
+	}
+}
 
+type Visitor func(runtime *Runtime, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error)
+
+// Runs program as main task and then event loop, until no jobs are pending.
+// Tasks, which are not finished by end of main task, are dropped
 func (runtime *Runtime) Run(ast *ast_node.ASTNode) error {
-	var _, err = runtime.visitNode(ast)
+	var main = runtime.program.createTask("main", func() (*runtime_heap.VariableValue, error) {
+		return runtime.executeBody(ast)
+	})
+
+	runtime.frame.asyncCall = main
+	runtime.program.continueTask(main, nil, nil)
+
+	// Error of program stops event loop
+	runtime.program.loop.then(main.promise, func(value *runtime_heap.VariableValue, err error) error {
+		return err
+	})
+
+	var err = runtime.program.loop.run(&runtime.program.limits, ast)
+
+	if err == nil && !main.coroutine.isDone {
+		err = runtime.program.getDeadlockError(main)
+	}
+
+	runtime.program.closeCoroutines()
 
 	return err
 }
 
+// Visitors by code of node. Initialized in init, because visitors call visitNode
+var visitors map[string]Visitor
+
+func init() {
+	visitors = map[string]Visitor{
+		ast_node.AST_NODE_CODE_ROOT:                     (*Runtime).visitRootNode,
+		ast_node.AST_NODE_CODE_VARIABLE_DECLARATION:     (*Runtime).visitVariableDeclarationNode,
+		ast_node.AST_NODE_CODE_ASSIGNMENT:               (*Runtime).visitAssignmentNode,
+		ast_node.AST_NODE_CODE_REFERENCE:                (*Runtime).visitReferenceNode,
+		ast_node.AST_NODE_CODE_NUMBER:                   (*Runtime).visitNumberNode,
+		ast_node.AST_NODE_CODE_STRING:                   (*Runtime).visitStringNode,
+		ast_node.AST_NODE_CODE_BOOLEAN:                  (*Runtime).visitBooleanNode,
+		ast_node.AST_NODE_CODE_FUNCTION:                 (*Runtime).visitFunctionNode,
+		ast_node.AST_NODE_CODE_BINARY_EXPRESSION:        (*Runtime).visitBinaryExpressionNode,
+		ast_node.AST_NODE_CODE_PARENTHESIZED_EXPRESSION: (*Runtime).visitParenthesizedExpressionNode,
+		ast_node.AST_NODE_CODE_CALL_EXPRESSION:          (*Runtime).visitCallExpressionNode,
+		ast_node.AST_NODE_CODE_BLOCK:                    (*Runtime).visitBlockNode,
+		ast_node.AST_NODE_CODE_RETURN:                   (*Runtime).visitReturnNode,
+		ast_node.AST_NODE_CODE_MATCH:                    (*Runtime).visitMatchNode,
+		ast_node.AST_NODE_CODE_UNARY_EXPRESSION:         (*Runtime).visitUnaryExpressionNode,
+		ast_node.AST_NODE_CODE_READ_PROP:                (*Runtime).visitReadPropNode,
+		ast_node.AST_NODE_CODE_WHILE:                    (*Runtime).visitWhileNode,
+		ast_node.AST_NODE_CODE_FOR_OF:                   (*Runtime).visitForOfNode,
+		ast_node.AST_NODE_CODE_BREAK:                    (*Runtime).visitLoopControlNode,
+		ast_node.AST_NODE_CODE_CONTINUE:                 (*Runtime).visitLoopControlNode,
+		ast_node.AST_NODE_CODE_YIELD:                    (*Runtime).visitYieldNode,
+		ast_node.AST_NODE_CODE_SPAWN:                    (*Runtime).visitSpawnNode,
+		ast_node.AST_NODE_CODE_INDEX:                    (*Runtime).visitIndexNode,
+		ast_node.AST_NODE_CODE_DEFER:                    (*Runtime).visitDeferNode,
+		ast_node.AST_NODE_CODE_ENUM:                     (*Runtime).visitEnumNode,
+	}
+}
+
 func (runtime *Runtime) visitNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
-	var visitors = map[string]Visitor{
-		ast_node.AST_NODE_CODE_ROOT:                     runtime.visitRootNode,
-		ast_node.AST_NODE_CODE_VARIABLE_DECLARATION:     runtime.visitVariableDeclarationNode,
-		ast_node.AST_NODE_CODE_ASSIGNMENT:               runtime.visitAssignmentNode,
-		ast_node.AST_NODE_CODE_REFERENCE:                runtime.visitReferenceNode,
-		ast_node.AST_NODE_CODE_NUMBER:                   runtime.visitNumberNode,
-		ast_node.AST_NODE_CODE_STRING:                   runtime.visitStringNode,
-		ast_node.AST_NODE_CODE_BOOLEAN:                  runtime.visitBooleanNode,
-		ast_node.AST_NODE_CODE_FUNCTION:                 runtime.visitFunctionNode,
-		ast_node.AST_NODE_CODE_BINARY_EXPRESSION:        runtime.visitBinaryExpressionNode,
-		ast_node.AST_NODE_CODE_PARENTHESIZED_EXPRESSION: runtime.visitParenthesizedExpressionNode,
-		ast_node.AST_NODE_CODE_CALL_EXPRESSION:          runtime.visitCallExpressionNode,
-		ast_node.AST_NODE_CODE_BLOCK:                    runtime.visitBlockNode,
-		ast_node.AST_NODE_CODE_RETURN:                   runtime.visitReturnNode,
+	var stepErr = runtime.program.limits.step(node)
+
+	if stepErr != nil {
+		return nil, stepErr
 	}
 
 	var visitor = visitors[node.Code]
@@ -69,19 +189,19 @@ func (runtime *Runtime) visitNode(node *ast_node.ASTNode) (*runtime_heap.Variabl
 		)
 	}
 
-	return visitor(node)
+	return visitor(runtime, node)
 }
 
 func (runtime *Runtime) visitRootNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
-	for _, bodyNode := range node.Body {
-		var _, bodyNodeErr = runtime.visitNode(bodyNode)
+	var statementsErr = runtime.executeStatements(node.Body)
 
-		if bodyNodeErr != nil {
-			return nil, bodyNodeErr
-		}
+	if statementsErr != nil {
+		return nil, statementsErr
 	}
 
-	return nil, nil
+	var _, resultErr = runtime.getCallResult()
+
+	return nil, resultErr
 }
 
 func (runtime *Runtime) visitVariableDeclarationNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
@@ -94,9 +214,30 @@ func (runtime *Runtime) visitVariableDeclarationNode(node *ast_node.ASTNode) (*r
 		)
 	}
 
-	var err = runtime.heap.CreateVariable(variableNameParam.Value)
+	var slot, slotErr = getSlot(variableNameParam, node)
+
+	if slotErr != nil {
+		return nil, slotErr
+	}
 
-	return nil, err
+	return nil, runtime.declareVariable(slot, node)
+}
+
+// Declares variable without value. In checked mode variable keeps annotated type
+func (runtime *Runtime) declareVariable(slot ast_node.VariableSlot, node *ast_node.ASTNode) error {
+	var err = runtime.heap.CreateVariable(slot.Index)
+
+	if err != nil {
+		return err
+	}
+
+	var variableTypeParam = ast_node.GetVariableTypeParam(node)
+
+	if runtime.program.isChecked && variableTypeParam != nil {
+		runtime.heap.GetVariable(slot).DeclaredType = variableTypeParam.Value
+	}
+
+	return nil
 }
 
 func (runtime *Runtime) visitAssignmentNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
@@ -118,7 +259,15 @@ func (runtime *Runtime) visitAssignmentNode(node *ast_node.ASTNode) (*runtime_he
 		)
 	}
 
-	var variableName, getVariableNameErr = getVariableName(variableReferenceNode)
+	if variableReferenceNode.Code == ast_node.AST_NODE_CODE_READ_PROP || variableReferenceNode.Code == ast_node.AST_NODE_CODE_INDEX {
+		var enumAssignmentErr = runtime.checkEnumAssignment(variableReferenceNode)
+
+		if enumAssignmentErr != nil {
+			return nil, enumAssignmentErr
+		}
+	}
+
+	var variableNameParam, getVariableNameErr = getVariableNameParam(variableReferenceNode)
 
 	if getVariableNameErr != nil {
 		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
@@ -136,27 +285,70 @@ func (runtime *Runtime) visitAssignmentNode(node *ast_node.ASTNode) (*runtime_he
 		), variableValueError)
 	}
 
+	return runtime.assignVariable(node, variableNameParam.Value, *variableNameParam.Slot, value)
+}
+
+// Stores evaluated value of assignment node into variable
+func (runtime *Runtime) assignVariable(node *ast_node.ASTNode, variableName string, slot ast_node.VariableSlot, value *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
 	if value == nil {
 		return nil, runtime_error.CreateError(
 			"Cannot get value from right node",
-			variableValueNode,
+			node.Body[1],
 		)
 	}
 
-	var setVariableError = runtime.heap.SetVariable(variableName, value)
+	var typeErr = runtime.checkVariableType(variableName, slot, value, node.Body[1])
+
+	if typeErr != nil {
+		return nil, typeErr
+	}
+
+	var setVariableError = runtime.setVariable(slot, value, node.Body[1])
 
 	if setVariableError != nil {
 		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
 			"Cannot set variable",
-			variableReferenceNode,
+			node.Body[0],
 		), setVariableError)
 	}
 
 	return value, nil
 }
 
+// Stores value into declared variable, memory of replaced value is counted for new one
+func (runtime *Runtime) setVariable(slot ast_node.VariableSlot, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	var prevValue = runtime.heap.GetVariable(slot)
+
+	if prevValue != nil {
+		var memoryErr = runtime.program.limits.replaceValue(prevValue, value, node)
+
+		if memoryErr != nil {
+			return memoryErr
+		}
+	}
+
+	return runtime.heap.SetVariable(slot, value)
+}
+
+// Declares variable with value, value is counted like stored by assignment
+func (runtime *Runtime) defineVariable(slot ast_node.VariableSlot, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	var memoryErr = runtime.program.limits.replaceValue(nil, value, node)
+
+	if memoryErr != nil {
+		return memoryErr
+	}
+
+	var defineErr = runtime.heap.DefineVariable(slot.Index, value)
+
+	if defineErr != nil {
+		runtime.program.limits.release(getValueSize(value))
+	}
+
+	return defineErr
+}
+
 func (runtime *Runtime) visitReferenceNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
-	var variableName, variableNameErr = getVariableName(node)
+	var variableNameParam, variableNameErr = getVariableNameParam(node)
 
 	if variableNameErr != nil {
 		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
@@ -165,7 +357,12 @@ func (runtime *Runtime) visitReferenceNode(node *ast_node.ASTNode) (*runtime_hea
 		), variableNameErr)
 	}
 
-	var value = runtime.heap.GetVariable(variableName)
+	return runtime.getVariable(node, *variableNameParam.Slot)
+}
+
+// Variable, which is not declared yet, can't be read, even when outer scope has variable with the same name
+func (runtime *Runtime) getVariable(node *ast_node.ASTNode, slot ast_node.VariableSlot) (*runtime_heap.VariableValue, error) {
+	var value = runtime.heap.GetVariable(slot)
 
 	if value == nil {
 		return nil, runtime_error.CreateError(
@@ -178,6 +375,26 @@ func (runtime *Runtime) visitReferenceNode(node *ast_node.ASTNode) (*runtime_hea
 }
 
 func (runtime *Runtime) visitNumberNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if literal := runtime.program.literals[node]; literal != nil {
+		return literal, nil
+	}
+
+	var value, err = parseNumberNode(node)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.program.literals == nil {
+		runtime.program.literals = map[*ast_node.ASTNode]*runtime_heap.VariableValue{}
+	}
+
+	runtime.program.literals[node] = value
+
+	return value, nil
+}
+
+func parseNumberNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
 	var numberValue = ast_node.GetNumberValueParam(node)
 
 	if numberValue == nil {
@@ -187,7 +404,20 @@ func (runtime *Runtime) visitNumberNode(node *ast_node.ASTNode) (*runtime_heap.V
 		)
 	}
 
-	var number, numberParsError = strconv.ParseFloat(numberValue.Value, 64)
+	if token_number.IsInteger(numberValue.Value) {
+		var integer, integerParseError = token_number.ParseInteger(numberValue.Value)
+
+		if integerParseError != nil {
+			return nil, runtime_error.CreateError(
+				"Failed parse integer value",
+				node,
+			)
+		}
+
+		return runtime_heap.CreateInteger(integer), nil
+	}
+
+	var number, numberParsError = token_number.ParseNumber(numberValue.Value)
 
 	if numberParsError != nil {
 		return nil, runtime_error.CreateError(
@@ -196,7 +426,7 @@ func (runtime *Runtime) visitNumberNode(node *ast_node.ASTNode) (*runtime_heap.V
 		)
 	}
 
-	return &runtime_heap.VariableValue{NumberValue: number, ValueType: runtime_heap.TYPE_NUMBER}, nil
+	return runtime_heap.CreateNumber(number), nil
 }
 
 func (runtime *Runtime) visitBooleanNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
@@ -209,17 +439,27 @@ func (runtime *Runtime) visitBooleanNode(node *ast_node.ASTNode) (*runtime_heap.
 		)
 	}
 
-	return &runtime_heap.VariableValue{BooleanValue: booleanValue.Value, ValueType: runtime_heap.TYPE_BOOLEAN}, nil
+	return runtime_heap.CreateBoolean(booleanValue.Value == "true"), nil
 }
 
 func (runtime *Runtime) visitReturnNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
-	var bodyNode = node.Body[0]
+	var value = runtime_heap.CreateUnknown()
+
+	if len(node.Body) > 0 {
+		var bodyValue, bodyErr = runtime.visitNode(node.Body[0])
 
-	if bodyNode != nil {
-		return runtime.visitNode(bodyNode)
+		if bodyErr != nil {
+			return nil, bodyErr
+		}
+
+		if bodyValue != nil {
+			value = bodyValue
+		}
 	}
 
-	return nil, nil
+	runtime.frame.completeReturn(value, node)
+
+	return value, nil
 }
 
 func (runtime *Runtime) visitStringNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
@@ -232,7 +472,7 @@ func (runtime *Runtime) visitStringNode(node *ast_node.ASTNode) (*runtime_heap.V
 		)
 	}
 
-	return &runtime_heap.VariableValue{StringValue: stringValue.Value, ValueType: runtime_heap.TYPE_STRING}, nil
+	return runtime_heap.CreateString(stringValue.Value), nil
 }
 
 func (runtime *Runtime) visitParenthesizedExpressionNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
@@ -249,6 +489,11 @@ func (runtime *Runtime) visitParenthesizedExpressionNode(node *ast_node.ASTNode)
 }
 
 func (runtime *Runtime) visitBinaryExpressionNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	// Right side of pipe is not evaluated as value, it is called with left side
+	if ast_node.IsPipe(node) && len(node.Body) == 2 {
+		return runtime.visitCallExpressionNode(ast_node.CreatePipeCall(node))
+	}
+
 	var leftNode = node.Body[0]
 
 	if leftNode == nil {
@@ -286,6 +531,17 @@ func (runtime *Runtime) visitBinaryExpressionNode(node *ast_node.ASTNode) (*runt
 
 	}
 
+	var value, calculateErr = calculateBinaryExpression(node, leftNodeValue, rightNodeValue)
+
+	if calculateErr != nil {
+		return nil, calculateErr
+	}
+
+	return value, runtime.program.limits.checkValue(value, node)
+}
+
+// Applies operator of binary expression node to evaluated operands
+func calculateBinaryExpression(node *ast_node.ASTNode, leftNodeValue *runtime_heap.VariableValue, rightNodeValue *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
 	var expressionType = ast_node.GetBinaryExpressionTypeParam(node)
 
 	if expressionType == nil {
@@ -295,31 +551,41 @@ func (runtime *Runtime) visitBinaryExpressionNode(node *ast_node.ASTNode) (*runt
 		)
 	}
 
-	if leftNodeValue.ValueType == runtime_heap.TYPE_NUMBER && rightNodeValue.ValueType == runtime_heap.TYPE_NUMBER {
-		var leftNumberValue = leftNodeValue.NumberValue
-		var rightNumberValue = rightNodeValue.NumberValue
+	switch expressionType.Value {
+	case "==":
+		return runtime_heap.CreateBoolean(runtime_heap.IsEqual(leftNodeValue, rightNodeValue)), nil
+	case "!=":
+		return runtime_heap.CreateBoolean(!runtime_heap.IsEqual(leftNodeValue, rightNodeValue)), nil
+	case ">", "<", ">=", "<=":
+		return compareValues(expressionType.Value, leftNodeValue, rightNodeValue, node)
+	}
+
+	if isBitwiseOperator(expressionType.Value) {
+		return calculateBitwise(expressionType.Value, leftNodeValue, rightNodeValue, node)
+	}
+
+	if leftNodeValue.Kind == runtime_heap.TYPE_INTEGER && rightNodeValue.Kind == runtime_heap.TYPE_INTEGER {
+		return calculateIntegers(expressionType.Value, leftNodeValue.IntegerValue, rightNodeValue.IntegerValue, node)
+	}
+
+	// Integer mixed with float is converted to float
+	if isNumeric(leftNodeValue) && isNumeric(rightNodeValue) {
+		var leftNumber, _ = runtime_heap.CastToNumber(leftNodeValue)
+		var rightNumber, _ = runtime_heap.CastToNumber(rightNodeValue)
+		var leftNumberValue = leftNumber.NumberValue
+		var rightNumberValue = rightNumber.NumberValue
 
 		switch expressionType.Value {
 		case "+":
-			return &runtime_heap.VariableValue{
-				NumberValue: leftNumberValue + rightNumberValue,
-				ValueType:   runtime_heap.TYPE_NUMBER,
-			}, nil
+			return runtime_heap.CreateNumber(leftNumberValue + rightNumberValue), nil
 		case "-":
-			return &runtime_heap.VariableValue{
-				NumberValue: leftNumberValue - rightNumberValue,
-				ValueType:   runtime_heap.TYPE_NUMBER,
-			}, nil
+			return runtime_heap.CreateNumber(leftNumberValue - rightNumberValue), nil
 		case "/":
-			return &runtime_heap.VariableValue{
-				NumberValue: leftNumberValue / rightNumberValue,
-				ValueType:   runtime_heap.TYPE_NUMBER,
-			}, nil
+			return runtime_heap.CreateNumber(leftNumberValue / rightNumberValue), nil
 		case "*":
-			return &runtime_heap.VariableValue{
-				NumberValue: leftNumberValue * rightNumberValue,
-				ValueType:   runtime_heap.TYPE_NUMBER,
-			}, nil
+			return runtime_heap.CreateNumber(leftNumberValue * rightNumberValue), nil
+		case "%":
+			return runtime_heap.CreateNumber(math.Mod(leftNumberValue, rightNumberValue)), nil
 		}
 	}
 
@@ -327,7 +593,7 @@ func (runtime *Runtime) visitBinaryExpressionNode(node *ast_node.ASTNode) (*runt
 
 	if leftNodeCastErr != nil {
 		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
-			"Cannot convert variable value to string. Received: "+leftNodeValue.ValueType,
+			"Cannot convert variable value to string. Received: "+leftNodeValue.Kind.String(),
 			node,
 		), leftNodeCastErr)
 	}
@@ -336,17 +602,14 @@ func (runtime *Runtime) visitBinaryExpressionNode(node *ast_node.ASTNode) (*runt
 
 	if rightNodeCastErr != nil {
 		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
-			"Cannot convert variable value to string. Received: "+rightString.ValueType,
+			"Cannot convert variable value to string. Received: "+rightNodeValue.Kind.String(),
 			node,
 		), rightNodeCastErr)
 	}
 
 	switch expressionType.Value {
 	case "+":
-		return &runtime_heap.VariableValue{
-			StringValue: leftString.StringValue + rightString.StringValue,
-			ValueType:   runtime_heap.TYPE_STRING,
-		}, nil
+		return runtime_heap.CreateString(leftString.StringValue + rightString.StringValue), nil
 	}
 
 	return nil, runtime_error.CreateError(
@@ -355,45 +618,178 @@ func (runtime *Runtime) visitBinaryExpressionNode(node *ast_node.ASTNode) (*runt
 	)
 }
 
-func (runtime *Runtime) visitFunctionNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
-	var functionNameParam = ast_node.GetFunctionNameParam(node)
+func (runtime *Runtime) visitUnaryExpressionNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if len(node.Body) != 1 {
+		return nil, runtime_error.CreateError(
+			"Cannot get operand of unary expression",
+			node,
+		)
+	}
 
-	if functionNameParam == nil {
+	var operandValue, operandErr = runtime.visitNode(node.Body[0])
+
+	if operandErr != nil || operandValue == nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot get operand value",
+			node.Body[0],
+		), operandErr)
+	}
+
+	return runtime.calculateUnaryExpression(node, operandValue)
+}
+
+// Applies operator of unary expression node to evaluated operand
+func (runtime *Runtime) calculateUnaryExpression(node *ast_node.ASTNode, operandValue *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var expressionType = ast_node.GetUnaryExpressionTypeParam(node)
+
+	if expressionType == nil {
 		return nil, runtime_error.CreateError(
-			"Cannot define function without name",
+			"Unary expression type not defined",
+			node,
+		)
+	}
+
+	switch expressionType.Value {
+	case "typeof":
+		return runtime_heap.CreateString(runtime_heap.GetTypeName(operandValue)), nil
+	case "await":
+		return runtime.await(operandValue, node)
+	case "~":
+		var value, notErr = bitwiseNot(operandValue, node)
+
+		if notErr != nil {
+			return nil, notErr
+		}
+
+		return value, runtime.program.limits.checkValue(value, node)
+	}
+
+	return nil, runtime_error.CreateError(
+		"Unknown unary expression. Received: "+expressionType.Value,
+		node,
+	)
+}
+
+func (runtime *Runtime) visitReadPropNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var propertyName = ast_node.GetParam(node, ast_node.AST_PARAM_PROPERTY_NAME)
+
+	if propertyName == nil || len(node.Body) != 1 {
+		return nil, runtime_error.CreateError(
+			"Cannot read property without name",
 			node,
 		)
 	}
 
-	var createVariableForFuncErr = runtime.heap.CreateVariable(functionNameParam.Value)
+	var objectValue, objectErr = runtime.visitNode(node.Body[0])
 
-	if createVariableForFuncErr != nil {
+	if objectErr != nil || objectValue == nil {
 		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
-			"Cannot define function with name: "+functionNameParam.Value,
+			"Cannot get value for reading property: "+propertyName.Value,
+			node.Body[0],
+		), objectErr)
+	}
+
+	return readProperty(objectValue, propertyName.Value, node)
+}
+
+func readProperty(objectValue *runtime_heap.VariableValue, propertyName string, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var property = getNativeProperty(objectValue, propertyName)
+
+	if property == nil {
+		return nil, runtime_error.CreateError(
+			"Cannot read property "+propertyName+" of "+runtime_heap.GetTypeName(objectValue),
 			node,
-		), createVariableForFuncErr)
+		)
 	}
 
-	var functionVariable = runtime.heap.GetVariable(functionNameParam.Value)
+	return property, nil
+}
 
-	if functionVariable == nil {
+func (runtime *Runtime) visitIndexNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if len(node.Body) != 1 || len(node.Arguments) != 1 {
 		return nil, runtime_error.CreateError(
-			"Cannot get allocated variable for function with name: "+functionNameParam.Value,
+			"Index expression should have value and index",
 			node,
 		)
 	}
 
-	functionVariable.ValueType = runtime_heap.TYPE_FUNCTION
-	functionVariable.FunctionValue = node
-	functionVariable.FunctionClosureHeap = runtime.heap.(*runtime_heap.Heap)
+	var objectValue, objectErr = runtime.visitNode(node.Body[0])
+
+	if objectErr != nil || objectValue == nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot get value for reading index",
+			node.Body[0],
+		), objectErr)
+	}
 
-	var setFunctionError = runtime.heap.SetVariable(functionNameParam.Value, functionVariable)
+	var indexValue, indexErr = runtime.visitNode(node.Arguments[0])
 
-	if setFunctionError != nil {
+	if indexErr != nil || indexValue == nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot get index value",
+			node.Arguments[0],
+		), indexErr)
+	}
+
+	return getNativeIndex(objectValue, indexValue, node)
+}
+
+func (runtime *Runtime) visitFunctionNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var functionNameParam = ast_node.GetFunctionNameParam(node)
+
+	if functionNameParam == nil {
+		return nil, runtime_error.CreateError(
+			"Cannot define function without name",
+			node,
+		)
+	}
+
+	var slot, slotErr = getSlot(functionNameParam, node)
+
+	if slotErr != nil {
+		return nil, slotErr
+	}
+
+	var functionVariable, defineErr = runtime.declareFunction(functionNameParam.Value, slot, node)
+
+	if defineErr != nil {
+		return nil, defineErr
+	}
+
+	if len(ast_node.GetFunctionDecorators(node)) == 0 {
+		return functionVariable, nil
+	}
+
+	// Plain function is defined first, so decorator can call it.
+	// Then name is bound to decorated value, so recursive calls go through decorators too
+	var decorated, decorateErr = runtime.applyDecorators(node, functionVariable)
+
+	if decorateErr != nil {
+		return nil, decorateErr
+	}
+
+	var setDecoratedError = runtime.heap.SetVariable(slot, decorated)
+
+	if setDecoratedError != nil {
 		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
 			"Cannot set function into variable with name: "+functionNameParam.Value,
 			node,
-		), setFunctionError)
+		), setDecoratedError)
+	}
+
+	return decorated, nil
+}
+
+// Function value, which closure is current heap, is stored into new variable
+func (runtime *Runtime) declareFunction(name string, slot ast_node.VariableSlot, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var functionVariable = runtime_heap.CreateFunction(node, runtime.heap.(*runtime_heap.Heap))
+	var defineErr = runtime.heap.DefineVariable(slot.Index, functionVariable)
+
+	if defineErr != nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot define function with name: "+name,
+			node,
+		), defineErr)
 	}
 
 	return functionVariable, nil
@@ -418,12 +814,53 @@ func (runtime *Runtime) visitCallExpressionNode(node *ast_node.ASTNode) (*runtim
 		), funcionVariableErr)
 	}
 
+	var argumentsValues, argumentsErr = runtime.getArgumentsValues(node)
+
+	if argumentsErr != nil {
+		return nil, argumentsErr
+	}
+
+	var boundValues, bindErr = runtime.bindCallArguments(node, functionVariable, argumentsValues)
+
+	if bindErr != nil {
+		return nil, bindErr
+	}
+
+	return runtime.callFunction(functionVariable, boundValues, node)
+}
+
+// Checks evaluated function of call expression node and orders values of its arguments
+func (runtime *Runtime) bindCallArguments(node *ast_node.ASTNode, functionVariable *runtime_heap.VariableValue, argumentsValues []*runtime_heap.VariableValue) ([]*runtime_heap.VariableValue, error) {
+	if functionVariable == nil {
+		return nil, runtime_error.CreateError(
+			"Error in calling expression, here is no reference to a function",
+			node,
+		)
+	}
+
+	if functionVariable.Kind != runtime_heap.TYPE_FUNCTION && functionVariable.Kind != runtime_heap.TYPE_NATIVE_FUNCTION {
+		return nil, runtime_error.CreateError(
+			"Is not a function",
+			node.Body[0],
+		)
+	}
+
+	return runtime.bindArguments(functionVariable, node, argumentsValues)
+}
+
+func (runtime *Runtime) getArgumentsValues(node *ast_node.ASTNode) ([]*runtime_heap.VariableValue, error) {
 	var argumentsValues []*runtime_heap.VariableValue
 
 	for _, argumentNode := range node.Arguments {
-		var argumentValue, argumentValueErr = runtime.visitNode(argumentNode)
+		var valueNode = argumentNode
 
-		if argumentValueErr != nil {
+		if ast_node.IsNamedArgument(argumentNode) {
+			valueNode = argumentNode.Body[0]
+		}
+
+		var argumentValue, argumentValueErr = runtime.visitNode(valueNode)
+
+		if argumentValueErr != nil || argumentValue == nil {
 			return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
 				"Cannot get function argument",
 				argumentNode,
@@ -433,147 +870,433 @@ func (runtime *Runtime) visitCallExpressionNode(node *ast_node.ASTNode) (*runtim
 		argumentsValues = append(argumentsValues, argumentValue)
 	}
 
-	if functionVariable == nil {
+	return argumentsValues, nil
+}
+
+// Calls function value with already evaluated arguments. Node is used for errors
+func (runtime *Runtime) callFunction(functionVariable *runtime_heap.VariableValue, argumentsValues []*runtime_heap.VariableValue, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if functionVariable.Kind == runtime_heap.TYPE_NATIVE_FUNCTION {
+		return runtime.callNativeFunction(functionVariable, node, argumentsValues)
+	}
+
+	if functionVariable.Kind != runtime_heap.TYPE_FUNCTION {
 		return nil, runtime_error.CreateError(
-			"Error in calling expression, here is no reference to a function",
+			"Is not a function",
 			node,
 		)
 	}
 
-	if functionVariable.ValueType == runtime_heap.TYPE_NATIVE_FUNCTION {
-		runtime.callNativeFunction(functionVariable.NativeFunctionName, argumentsValues)
-		return nil, nil
+	var innerRuntime, callErr = runtime.createFunctionCall(functionVariable, argumentsValues, node)
+
+	if callErr != nil {
+		return nil, callErr
 	}
 
-	if functionVariable.ValueType != runtime_heap.TYPE_FUNCTION {
-		return nil, runtime_error.CreateError(
-			"Is not a function",
-			functionReference,
-		)
+	// Body of generator function runs lazily, by "next" calls
+	if ast_node.IsGeneratorFunction(functionVariable.Function().Node) {
+		return runtime.createGenerator(innerRuntime, functionVariable.Function().Node.Body[0]), nil
 	}
 
-	var innerRuntime = CreateRuntime(runtime.bridge)
-	var argumentsNames = []string{}
+	if ast_node.IsAsyncFunction(functionVariable.Function().Node) {
+		return runtime.startAsyncCall(innerRuntime, functionVariable.Function().Node.Body[0], getFunctionName(functionVariable)), nil
+	}
 
-	for _, funcParam := range functionVariable.FunctionValue.Params {
-		if funcParam.Name == ast_node.AST_PARAM_FUNCTION_ARGUMENT_NAME {
-			argumentsNames = append(argumentsNames, funcParam.Value)
-		}
+	var result, resultErr = innerRuntime.runBody(functionVariable.Function().Node.Body[0])
+
+	if resultErr != nil {
+		return nil, innerRuntime.call.attachStack(resultErr)
 	}
 
-	for index, argumentName := range argumentsNames {
-		var createArgumentValueError = innerRuntime.heap.CreateVariable(argumentName)
+	var returnTypeErr = runtime.checkReturnType(functionVariable, result, node)
 
-		if createArgumentValueError != nil {
-			return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
-				"Cannot create variable for argument: "+argumentName,
-				node,
-			), createArgumentValueError)
+	if returnTypeErr != nil {
+		return nil, returnTypeErr
+	}
+
+	return result, nil
+}
+
+// Runtime of function call with bound arguments, its body is not started yet
+func (runtime *Runtime) createFunctionCall(functionVariable *runtime_heap.VariableValue, argumentsValues []*runtime_heap.VariableValue, node *ast_node.ASTNode) (*Runtime, error) {
+	var call = runtime.call.enter(functionVariable, node)
+	var innerRuntime = runtime.createCallRuntime(functionVariable.Function().Node.ScopeSize, call)
+	var startErr = runtime.startFunctionCall(&innerRuntime, functionVariable, argumentsValues, node)
+
+	if startErr != nil {
+		return nil, startErr
+	}
+
+	return &innerRuntime, nil
+}
+
+// Checks limits and binds arguments in runtime of call, which heap, frame and call site are ready
+func (runtime *Runtime) startFunctionCall(innerRuntime *Runtime, functionVariable *runtime_heap.VariableValue, argumentsValues []*runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	var depthErr = runtime.program.limits.checkCallDepth(innerRuntime.call.depth, node)
+
+	if depthErr != nil {
+		return depthErr
+	}
+
+	var memoryErr = runtime.program.limits.allocate(getCallMemory(functionVariable), node)
+
+	if memoryErr != nil {
+		return memoryErr
+	}
+
+	innerRuntime.frame.isFunction = true
+
+	var argumentsErr = runtime.setCallArguments(innerRuntime, functionVariable, argumentsValues, node)
+
+	// Call is not started, so its heap is not used
+	if argumentsErr != nil {
+		innerRuntime.releaseCall()
+	}
+
+	return argumentsErr
+}
+
+func (runtime *Runtime) setCallArguments(innerRuntime *Runtime, functionVariable *runtime_heap.VariableValue, argumentsValues []*runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+
+	for index, argument := range ast_node.GetFunctionArguments(functionVariable.Function().Node) {
+		var argumentName = argument.Name.Value
+		var slot, slotErr = getSlot(&argument.Name, node)
+
+		if slotErr != nil {
+			return slotErr
 		}
 
+		var argumentValue *runtime_heap.VariableValue
+
 		if index < len(argumentsValues) {
-			var argumentValue = argumentsValues[index]
+			argumentValue = argumentsValues[index]
+		}
 
-			var setArgumentValueError = innerRuntime.heap.SetVariable(argumentName, argumentValue)
+		// Missing argument stays unknown, so it is checked as unknown value
+		var typeErr = runtime.checkArgumentType(functionVariable, argument, index, argumentValue, node)
 
-			if setArgumentValueError != nil {
-				return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
-					"Cannot set value for argument: "+argumentName,
-					node,
-				), setArgumentValueError)
-			}
+		if typeErr != nil {
+			return typeErr
+		}
+
+		var defineArgumentErr = innerRuntime.defineArgument(slot, argument, argumentValue, node)
+
+		if defineArgumentErr != nil {
+			return runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+				"Cannot create variable for argument: "+argumentName,
+				node,
+			), defineArgumentErr)
 		}
 	}
 
-	if functionVariable.FunctionClosureHeap == nil {
-		return nil, runtime_error.CreateError(
+	if functionVariable.Function().Closure == nil {
+		return runtime_error.CreateError(
 			"Function closure not found",
 			node,
 		)
 	}
 
-	innerRuntime.heap.SetParentHeap(functionVariable.FunctionClosureHeap)
+	innerRuntime.heap.SetParentHeap(functionVariable.Function().Closure)
 
-	if len(functionVariable.FunctionValue.Body) != 1 {
-		return nil, runtime_error.CreateError(
+	if len(functionVariable.Function().Node.Body) != 1 {
+		return runtime_error.CreateError(
 			"Function can has only one body node",
 			node,
 		)
 	}
 
-	var bodyNodeValue, bodyNodeErr = innerRuntime.visitNode(functionVariable.FunctionValue.Body[0])
+	return nil
+}
+
+// Missing argument gets empty value. Annotated argument of checked mode keeps its type,
+// so later assignments to argument are checked too
+func (runtime *Runtime) defineArgument(slot ast_node.VariableSlot, argument ast_node.FunctionArgument, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	if !runtime.program.isChecked || argument.Type == nil {
+		if value == nil {
+			value = &runtime_heap.VariableValue{}
+		}
+
+		return runtime.defineVariable(slot, value, node)
+	}
+
+	var createErr = runtime.heap.CreateVariable(slot.Index)
 
-	if bodyNodeErr != nil {
-		return bodyNodeValue, bodyNodeErr
+	if createErr != nil {
+		return createErr
 	}
 
-	return bodyNodeValue, nil
+	runtime.heap.GetVariable(slot).DeclaredType = argument.Type.Value
+
+	if value == nil {
+		return nil
+	}
+
+	return runtime.setVariable(slot, value, node)
 }
 
 func (runtime *Runtime) visitBlockNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
-	for _, blockBodyNode := range node.Body {
-		var bodyNodeValue, bodyNodeErr = runtime.visitNode(blockBodyNode)
+	return nil, runtime.executeStatements(node.Body)
+}
 
-		if bodyNodeErr != nil {
-			return nil, bodyNodeErr
+func (runtime *Runtime) visitMatchNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if len(node.Arguments) != 1 {
+		return nil, runtime_error.CreateError(
+			"Match expression should have one value",
+			node,
+		)
+	}
+
+	var value, valueErr = runtime.visitNode(node.Arguments[0])
+
+	if valueErr != nil || value == nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot get match value",
+			node.Arguments[0],
+		), valueErr)
+	}
+
+	var hasWildcard = false
+
+	for _, armNode := range node.Body {
+		var isMatched, armRuntime, patternErr = runtime.matchArmPatterns(armNode, value)
+
+		if patternErr != nil {
+			return nil, patternErr
+		}
+
+		// Bindings of arm are not counted, when match ends
+		if armRuntime != runtime {
+			defer runtime.program.limits.releaseScope(armRuntime.heap)
 		}
 
-		if blockBodyNode.Code == ast_node.AST_NODE_CODE_RETURN {
-			return bodyNodeValue, nil
+		if isWildcardArm(armNode) {
+			hasWildcard = true
+		}
+
+		if !isMatched {
+			continue
+		}
+
+		var valueNode *ast_node.ASTNode
+
+		for _, armBodyNode := range armNode.Body {
+			if armBodyNode.Code != ast_node.AST_NODE_CODE_MATCH_GUARD {
+				valueNode = armBodyNode
+				continue
+			}
+
+			var guardValue, guardErr = armRuntime.visitNode(armBodyNode.Body[0])
+
+			if guardErr != nil || guardValue == nil {
+				return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+					"Cannot get match guard value",
+					armBodyNode,
+				), guardErr)
+			}
+
+			isMatched = runtime_heap.IsTruthy(guardValue)
 		}
+
+		if !isMatched {
+			continue
+		}
+
+		if valueNode == nil {
+			return nil, runtime_error.CreateError(
+				"Match arm value not defined",
+				armNode,
+			)
+		}
+
+		return armRuntime.visitNode(valueNode)
 	}
 
-	return nil, nil
+	return nil, createMatchError(value, hasWildcard, node)
 }
 
-func (runtime *Runtime) defineEnvByBridge() error {
-	var printFuncName = "print"
-	var definePrintVariableErr = runtime.heap.CreateVariable(printFuncName)
+func createMatchError(value *runtime_heap.VariableValue, hasWildcard bool, node *ast_node.ASTNode) error {
+	var valueString, castErr = runtime_heap.CastToString(value)
+	var message = "Non-exhaustive match. No arm matched value"
 
-	if definePrintVariableErr != nil {
-		return runtime_error.MergeRuntimeErrors(runtime_error.RuntimeError{
-			Message: "Cannot create print env variable",
-		}, definePrintVariableErr)
+	if castErr == nil {
+		message = message + ": " + valueString.StringValue
 	}
 
-	var defineNativePrintErr = runtime.heap.SetVariable(printFuncName, &runtime_heap.VariableValue{
-		ValueType:          runtime_heap.TYPE_NATIVE_FUNCTION,
-		NativeFunctionName: printFuncName,
-	})
+	if !hasWildcard {
+		message = message + ". Add \"_\" arm to handle other values"
+	}
+
+	return runtime_error.CreateError(message, node)
+}
+
+// Arm with binding has own scope, even when value is matched by other pattern of arm
+func (runtime *Runtime) matchArmPatterns(armNode *ast_node.ASTNode, value *runtime_heap.VariableValue) (bool, *Runtime, error) {
+	var armRuntime = runtime
 
-	if defineNativePrintErr != nil {
-		return runtime_error.MergeRuntimeErrors(runtime_error.RuntimeError{
-			Message: "Cannot define print env variable",
-		}, defineNativePrintErr)
+	if armNode.ScopeSize > 0 {
+		var scopeRuntime = runtime.createScope(armNode.ScopeSize)
+		armRuntime = &scopeRuntime
 	}
 
-	return nil
+	for _, patternNode := range armNode.Arguments {
+		switch patternNode.Code {
+		case ast_node.AST_NODE_CODE_MATCH_WILDCARD:
+			return true, armRuntime, nil
+
+		case ast_node.AST_NODE_CODE_MATCH_BINDING:
+			var bindingErr = armRuntime.bindMatchValue(patternNode, value)
+
+			if bindingErr != nil {
+				return false, nil, bindingErr
+			}
+
+			return true, armRuntime, nil
+
+		default:
+			var patternValue, patternErr = runtime.visitNode(patternNode)
+
+			if patternErr != nil {
+				return false, nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+					"Cannot get match pattern value",
+					patternNode,
+				), patternErr)
+			}
+
+			if runtime_heap.IsEqual(value, patternValue) {
+				return true, armRuntime, nil
+			}
+		}
+	}
+
+	return false, armRuntime, nil
 }
 
-func (runtime *Runtime) callNativeFunction(name string, argumentsValues []*runtime_heap.VariableValue) error {
-	if name == "print" {
-		runtime.bridge.Print(argumentsValues...)
+func (runtime *Runtime) bindMatchValue(patternNode *ast_node.ASTNode, value *runtime_heap.VariableValue) error {
+	var bindingName = ast_node.GetVariableNameParam(patternNode)
+	var slot, createBindingErr = getSlot(bindingName, patternNode)
+
+	if createBindingErr == nil {
+		createBindingErr = runtime.heap.CreateVariable(slot.Index)
+	}
+
+	if createBindingErr == nil {
+		createBindingErr = runtime.setVariable(slot, value, patternNode)
+	}
+
+	if createBindingErr != nil {
+		return runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot bind match value to variable: "+bindingName.Value,
+			patternNode,
+		), createBindingErr)
 	}
 
 	return nil
 }
 
-func getVariableName(node *ast_node.ASTNode) (string, error) {
+func isWildcardArm(armNode *ast_node.ASTNode) bool {
+	for _, patternNode := range armNode.Arguments {
+		if patternNode.Code == ast_node.AST_NODE_CODE_MATCH_WILDCARD {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Runtime with own heap for nested scope, variables of current scope are still visible
+func (runtime *Runtime) createScope(scopeSize int) Runtime {
+	var heap = runtime_heap.CreateHeap(scopeSize)
+	heap.SetParentHeap(runtime.heap)
+
+	var scopeRuntime = *runtime
+	scopeRuntime.heap = &heap
+
+	return scopeRuntime
+}
+
+func compareValues(operator string, left *runtime_heap.VariableValue, right *runtime_heap.VariableValue, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if left.Kind == runtime_heap.TYPE_NUMBER && right.Kind == runtime_heap.TYPE_NUMBER {
+		return runtime_heap.CreateBoolean(compareNumbers(operator, left.NumberValue, right.NumberValue)), nil
+	}
+
+	if isNumeric(left) && isNumeric(right) {
+		return runtime_heap.CreateBoolean(compareMixedNumbers(operator, left, right)), nil
+	}
+
+	if left.Kind == runtime_heap.TYPE_STRING && right.Kind == runtime_heap.TYPE_STRING {
+		return runtime_heap.CreateBoolean(compareStrings(operator, left.StringValue, right.StringValue)), nil
+	}
+
+	return nil, runtime_error.CreateError(
+		"Cannot compare values. Received: "+left.Kind.String()+" and "+right.Kind.String(),
+		node,
+	)
+}
+
+func compareNumbers(operator string, left float64, right float64) bool {
+	switch operator {
+	case ">":
+		return left > right
+	case "<":
+		return left < right
+	case ">=":
+		return left >= right
+	case "<=":
+		return left <= right
+	}
+
+	return false
+}
+
+func compareStrings(operator string, left string, right string) bool {
+	switch operator {
+	case ">":
+		return left > right
+	case "<":
+		return left < right
+	case ">=":
+		return left >= right
+	case "<=":
+		return left <= right
+	}
+
+	return false
+}
+
+// Name of reference node with slot of variable
+func getVariableNameParam(node *ast_node.ASTNode) (*ast_node.ASTNodeParam, error) {
 	if node.Code == ast_node.AST_NODE_CODE_REFERENCE {
 		var variableNameParam = ast_node.GetVariableNameParam(node)
 
 		if variableNameParam == nil {
-			return "", runtime_error.CreateError(
+			return nil, runtime_error.CreateError(
 				"Reference without variable without name",
 				node,
 			)
 		}
 
-		return variableNameParam.Value, nil
+		var _, slotErr = getSlot(variableNameParam, node)
+
+		if slotErr != nil {
+			return nil, slotErr
+		}
+
+		return variableNameParam, nil
 	}
 
-	return "", runtime_error.CreateError(
+	return nil, runtime_error.CreateError(
 		"Cannot get name from node: "+node.Code,
 		node,
 	)
 }
+
+// Slot of variable, which is assigned to name by resolver
+func getSlot(nameParam *ast_node.ASTNodeParam, node *ast_node.ASTNode) (ast_node.VariableSlot, error) {
+	if nameParam.Slot == nil {
+		return ast_node.VariableSlot{}, runtime_error.CreateError(
+			"Variable is not resolved: "+nameParam.Value,
+			node,
+		)
+	}
+
+	return *nameParam.Slot, nil
+}
@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Orders values of call arguments by function arguments. Positional arguments go first,
+// named ones are placed by name. Not passed arguments are nil
+func (runtime *Runtime) bindArguments(functionVariable *runtime_heap.VariableValue, node *ast_node.ASTNode, argumentsValues []*runtime_heap.VariableValue) ([]*runtime_heap.VariableValue, error) {
+	var firstNamedIndex = len(node.Arguments)
+
+	for index, argumentNode := range node.Arguments {
+		if ast_node.IsNamedArgument(argumentNode) {
+			firstNamedIndex = index
+			break
+		}
+	}
+
+	if firstNamedIndex == len(node.Arguments) {
+		return argumentsValues, nil
+	}
+
+	if functionVariable.Kind == runtime_heap.TYPE_NATIVE_FUNCTION {
+		return nil, runtime_error.CreateError(
+			"Build in function "+getFunctionName(functionVariable)+" does not support named arguments",
+			node.Arguments[firstNamedIndex],
+		)
+	}
+
+	if functionVariable.Kind != runtime_heap.TYPE_FUNCTION {
+		return argumentsValues, nil
+	}
+
+	var functionArguments = ast_node.GetFunctionArguments(functionVariable.Function().Node)
+	var boundValues = make([]*runtime_heap.VariableValue, len(functionArguments))
+	copy(boundValues, argumentsValues[:firstNamedIndex])
+
+	for index := firstNamedIndex; index < len(node.Arguments); index++ {
+		var argumentNode = node.Arguments[index]
+		var name = ast_node.GetVariableNameParam(argumentNode).Value
+		var argumentIndex = findFunctionArgument(functionArguments, name)
+
+		if argumentIndex < 0 {
+			return nil, runtime_error.CreateError(
+				"Function "+getFunctionName(functionVariable)+" has no argument: "+name,
+				argumentNode,
+			)
+		}
+
+		if argumentIndex < firstNamedIndex || boundValues[argumentIndex] != nil {
+			return nil, runtime_error.CreateError(
+				"Argument "+name+" of function "+getFunctionName(functionVariable)+" is already passed",
+				argumentNode,
+			)
+		}
+
+		boundValues[argumentIndex] = argumentsValues[index]
+	}
+
+	return boundValues, nil
+}
+
+func findFunctionArgument(functionArguments []ast_node.FunctionArgument, name string) int {
+	for index, argument := range functionArguments {
+		if argument.Name.Value == name {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,39 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Body runs synchronously till first await, result is delivered by promise
+func (runtime *Runtime) startAsyncCall(callRuntime *Runtime, body *ast_node.ASTNode, name string) *runtime_heap.VariableValue {
+	var call = runtime.program.createTask(name, func() (*runtime_heap.VariableValue, error) {
+		var result, err = callRuntime.runBody(body)
+
+		return result, callRuntime.call.attachStack(err)
+	})
+
+	callRuntime.frame.asyncCall = call
+	runtime.program.continueTask(call, nil, nil)
+
+	return createPromiseValue(call.promise)
+}
+
+func (runtime *Runtime) await(value *runtime_heap.VariableValue, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if runtime.frame.asyncCall == nil {
+		return nil, runtime_error.CreateError(
+			"Cannot await outside of async function",
+			node,
+		)
+	}
+
+	// Not promise values are awaited as already fulfilled promise
+	if value.Kind != runtime_heap.TYPE_PROMISE {
+		var fulfilled = createPromise()
+		runtime.program.loop.settle(fulfilled, value, nil)
+		value = createPromiseValue(fulfilled)
+	}
+
+	return runtime.program.block(value.Promise().(*promise), "await", node)
+}
@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/parser"
+	"github.com/VadimZvf/golang/runtime_bridge_mock"
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/stdout_mock"
+)
+
+const fibonacciCode = `
+function fib(n) {
+	return match (n) {
+		x if x < 2 => x,
+		_ => fib(n - 1) + fib(n - 2)
+	};
+}
+
+fib(18);
+`
+
+const loopCode = `
+function sum(count) {
+	var total = 0;
+	var i = 0;
+	while (i < count) {
+		total = total + i * 2 % 7;
+		i = i + 1;
+	}
+	return total;
+}
+
+sum(20000);
+`
+
+const stringCode = `
+var text = "";
+var i = 0;
+while (i < 2000) {
+	text = text + i;
+	i = i + 1;
+}
+`
+
+func BenchmarkTreeWalkerFibonacci(b *testing.B) {
+	benchmarkCode(b, fibonacciCode, false)
+}
+
+func BenchmarkBytecodeFibonacci(b *testing.B) {
+	benchmarkCode(b, fibonacciCode, true)
+}
+
+func BenchmarkTreeWalkerLoop(b *testing.B) {
+	benchmarkCode(b, loopCode, false)
+}
+
+func BenchmarkBytecodeLoop(b *testing.B) {
+	benchmarkCode(b, loopCode, true)
+}
+
+func BenchmarkTreeWalkerStrings(b *testing.B) {
+	benchmarkCode(b, stringCode, false)
+}
+
+func BenchmarkBytecodeStrings(b *testing.B) {
+	benchmarkCode(b, stringCode, true)
+}
+
+// Parsing is not measured, every run gets new runtime
+func benchmarkCode(b *testing.B, code string, isBytecode bool) {
+	var src = source_mock.GetSourceMock(code)
+	var stdout = stdout_mock.CreateStdout()
+	var codeParser = parser.CreateParser(src, &stdout)
+	var astRoot, astError = codeParser.Parse(false)
+
+	if astError != nil {
+		b.Fatalf("Parsing failed: %s", astError.Error())
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var bridge = runtime_bridge_mock.CreateBridge()
+		var rt = CreateRuntime(&bridge)
+		rt.SetBytecodeMode(isBytecode)
+
+		var err = rt.Run(astRoot)
+
+		if err != nil {
+			b.Fatalf("Code failed: %s", err.Error())
+		}
+	}
+}
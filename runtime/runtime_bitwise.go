@@ -0,0 +1,133 @@
+package runtime
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Limits size of integer, which can be produced by one shift
+const maxIntegerShift = 1 << 24
+
+func isBitwiseOperator(operator string) bool {
+	switch operator {
+	case "&", "|", "^", "<<", ">>", ">>>":
+		return true
+	}
+
+	return false
+}
+
+// Integers are calculated with arbitrary precision, other numbers are converted to 32-bit integers
+func calculateBitwise(operator string, left *runtime_heap.VariableValue, right *runtime_heap.VariableValue, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if !isNumeric(left) || !isNumeric(right) {
+		var actual = left
+
+		if isNumeric(left) {
+			actual = right
+		}
+
+		return nil, runtime_error.CreateError(
+			"Operator "+operator+" expects numbers, but received: "+runtime_heap.GetTypeName(actual),
+			node,
+		)
+	}
+
+	if left.Kind == runtime_heap.TYPE_INTEGER && right.Kind == runtime_heap.TYPE_INTEGER {
+		return calculateBitwiseIntegers(operator, left.IntegerValue, right.IntegerValue, node)
+	}
+
+	var leftNumber, _ = runtime_heap.CastToNumber(left)
+	var rightNumber, _ = runtime_heap.CastToNumber(right)
+	var leftInt = toInt32(leftNumber.NumberValue)
+	var rightInt = toInt32(rightNumber.NumberValue)
+	// Only five lowest bits of shift count are used
+	var shiftCount = uint32(rightInt) & 31
+	var result float64
+
+	switch operator {
+	case "&":
+		result = float64(leftInt & rightInt)
+	case "|":
+		result = float64(leftInt | rightInt)
+	case "^":
+		result = float64(leftInt ^ rightInt)
+	case "<<":
+		result = float64(leftInt << shiftCount)
+	case ">>":
+		result = float64(leftInt >> shiftCount)
+	case ">>>":
+		result = float64(uint32(leftInt) >> shiftCount)
+	}
+
+	return runtime_heap.CreateNumber(result), nil
+}
+
+// Negative integers behave like infinite two's complement. Negative shift count shifts to other side
+func calculateBitwiseIntegers(operator string, left *big.Int, right *big.Int, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	switch operator {
+	case "&":
+		return runtime_heap.CreateInteger(new(big.Int).And(left, right)), nil
+	case "|":
+		return runtime_heap.CreateInteger(new(big.Int).Or(left, right)), nil
+	case "^":
+		return runtime_heap.CreateInteger(new(big.Int).Xor(left, right)), nil
+	case ">>>":
+		return nil, runtime_error.CreateError(
+			"Unsigned right shift is not defined for integers",
+			node,
+		)
+	}
+
+	if !right.IsInt64() || right.Int64() > maxIntegerShift || right.Int64() < -maxIntegerShift {
+		return nil, runtime_error.CreateError(
+			"Shift count is too big: "+right.String(),
+			node,
+		)
+	}
+
+	var count = right.Int64()
+
+	if operator == ">>" {
+		count = -count
+	}
+
+	if count < 0 {
+		return runtime_heap.CreateInteger(new(big.Int).Rsh(left, uint(-count))), nil
+	}
+
+	return runtime_heap.CreateInteger(new(big.Int).Lsh(left, uint(count))), nil
+}
+
+func bitwiseNot(value *runtime_heap.VariableValue, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	switch value.Kind {
+	case runtime_heap.TYPE_INTEGER:
+		return runtime_heap.CreateInteger(new(big.Int).Not(value.IntegerValue)), nil
+	case runtime_heap.TYPE_NUMBER:
+		return runtime_heap.CreateNumber(float64(^toInt32(value.NumberValue))), nil
+	}
+
+	return nil, runtime_error.CreateError(
+		"Operator ~ expects number, but received: "+runtime_heap.GetTypeName(value),
+		node,
+	)
+}
+
+// Converts number like ToInt32 of JavaScript: fraction is truncated,
+// result is wrapped modulo 2^32. NaN and infinity give zero
+func toInt32(value float64) int32 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+
+	var wrapped = math.Mod(math.Trunc(value), 1<<32)
+
+	if wrapped < 0 {
+		wrapped += 1 << 32
+	}
+
+	return int32(uint32(wrapped))
+}
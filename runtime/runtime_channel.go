@@ -0,0 +1,226 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Wait of task for first of several receives, like in select
+type selection struct {
+	promise *promise
+	isDone  bool
+	// Case, which received value. False received flag means closed channel
+	index      int
+	isReceived bool
+}
+
+type channelReceiver struct {
+	selection *selection
+	index     int
+}
+
+type channelSender struct {
+	value   *runtime_heap.VariableValue
+	promise *promise
+}
+
+// Queue of values between tasks. Channel without capacity passes value
+// only when sender and receiver meet, other channels buffer values
+type channel struct {
+	loop      *eventLoop
+	capacity  int
+	buffer    []*runtime_heap.VariableValue
+	receivers []*channelReceiver
+	senders   []*channelSender
+	isClosed  bool
+}
+
+func createChannel(loop *eventLoop, capacity int) *runtime_heap.VariableValue {
+	return runtime_heap.CreateReference(runtime_heap.TYPE_CHANNEL, &channel{
+		loop:     loop,
+		capacity: capacity,
+	})
+}
+
+func (ch *channel) Len() int {
+	return len(ch.buffer)
+}
+
+func (ch *channel) Cap() int {
+	return ch.capacity
+}
+
+func (ch *channel) IsClosed() bool {
+	return ch.isClosed
+}
+
+// Takes value without waiting. Ready flag is false, when task has to wait for sender
+func (ch *channel) tryReceive() (value *runtime_heap.VariableValue, isReceived bool, isReady bool) {
+	if len(ch.buffer) > 0 {
+		value = ch.buffer[0]
+		ch.buffer = ch.buffer[1:]
+
+		// Buffer got free place for value of waiting sender
+		if len(ch.senders) > 0 {
+			var sender = ch.senders[0]
+			ch.senders = ch.senders[1:]
+			ch.buffer = append(ch.buffer, sender.value)
+			ch.loop.settle(sender.promise, sender.value, nil)
+		}
+
+		return value, true, true
+	}
+
+	if len(ch.senders) > 0 {
+		var sender = ch.senders[0]
+		ch.senders = ch.senders[1:]
+		ch.loop.settle(sender.promise, sender.value, nil)
+
+		return sender.value, true, true
+	}
+
+	if ch.isClosed {
+		return runtime_heap.CreateUnknown(), false, true
+	}
+
+	return nil, false, false
+}
+
+// Passes value without waiting. False, when task has to wait for receiver
+func (ch *channel) trySend(value *runtime_heap.VariableValue) (bool, error) {
+	if ch.isClosed {
+		return false, runtime_error.RuntimeError{
+			Message: "Cannot send to closed channel",
+		}
+	}
+
+	for len(ch.receivers) > 0 {
+		var receiver = ch.receivers[0]
+		ch.receivers = ch.receivers[1:]
+
+		// Other case of select already received value
+		if receiver.selection.isDone {
+			continue
+		}
+
+		ch.complete(receiver, value, true)
+
+		return true, nil
+	}
+
+	if len(ch.buffer) < ch.capacity {
+		ch.buffer = append(ch.buffer, value)
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (ch *channel) complete(receiver *channelReceiver, value *runtime_heap.VariableValue, isReceived bool) {
+	receiver.selection.isDone = true
+	receiver.selection.index = receiver.index
+	receiver.selection.isReceived = isReceived
+	ch.loop.settle(receiver.selection.promise, value, nil)
+}
+
+// Wakes up all waiting tasks. Receivers get unknown value, senders fail
+func (ch *channel) close() error {
+	if ch.isClosed {
+		return runtime_error.RuntimeError{
+			Message: "Channel is already closed",
+		}
+	}
+
+	ch.isClosed = true
+
+	for _, receiver := range ch.receivers {
+		if !receiver.selection.isDone {
+			ch.complete(receiver, runtime_heap.CreateUnknown(), false)
+		}
+	}
+
+	for _, sender := range ch.senders {
+		ch.loop.settle(sender.promise, nil, runtime_error.RuntimeError{
+			Message: "Cannot send to closed channel",
+		})
+	}
+
+	ch.receivers = nil
+	ch.senders = nil
+
+	return nil
+}
+
+// Receives value, waits for sender, when channel is empty.
+// Received flag is false, when channel is closed and has no values
+func (runtime *Runtime) receive(ch *channel, node *ast_node.ASTNode) (*runtime_heap.VariableValue, bool, error) {
+	var value, isReceived, isReady = ch.tryReceive()
+
+	if isReady {
+		return value, isReceived, nil
+	}
+
+	var wait = &selection{promise: createPromise()}
+	ch.receivers = append(ch.receivers, &channelReceiver{selection: wait})
+
+	var received, err = runtime.program.block(wait.promise, "receive", node)
+
+	return received, wait.isReceived, err
+}
+
+// Sends value, waits for receiver or free place in buffer
+func (runtime *Runtime) send(ch *channel, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	value = copyValue(value)
+
+	var isSent, sendErr = ch.trySend(value)
+
+	if sendErr != nil {
+		return runtime_error.CreateError(sendErr.Error(), node)
+	}
+
+	if isSent {
+		return nil
+	}
+
+	var sender = &channelSender{value: value, promise: createPromise()}
+	ch.senders = append(ch.senders, sender)
+
+	var _, err = runtime.program.block(sender.promise, "send", node)
+
+	if err != nil {
+		return runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Send failed",
+			node,
+		), err)
+	}
+
+	return nil
+}
+
+// Receives from first ready channel of cases. Without ready channels
+// runs default case, or waits for first sent value, when there is no default
+func (runtime *Runtime) selectChannel(cases []*channel, node *ast_node.ASTNode, hasDefault bool) (int, *runtime_heap.VariableValue, error) {
+	for index, ch := range cases {
+		var value, _, isReady = ch.tryReceive()
+
+		if isReady {
+			return index, value, nil
+		}
+	}
+
+	if hasDefault {
+		return -1, nil, nil
+	}
+
+	var wait = &selection{promise: createPromise()}
+
+	for index, ch := range cases {
+		ch.receivers = append(ch.receivers, &channelReceiver{selection: wait, index: index})
+	}
+
+	var value, err = runtime.program.block(wait.promise, "select", node)
+
+	return wait.index, value, err
+}
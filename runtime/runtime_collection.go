@@ -0,0 +1,129 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Copy of value, so later assignment to variable doesn't change stored value
+func copyValue(value *runtime_heap.VariableValue) *runtime_heap.VariableValue {
+	var copied = *value
+
+	return &copied
+}
+
+func createCollection(kind runtime_heap.ValueKind) *runtime_heap.VariableValue {
+	return runtime_heap.CreateReference(kind, runtime_heap.CreateCollection())
+}
+
+func createEntry(entry *runtime_heap.CollectionEntry) *runtime_heap.VariableValue {
+	return runtime_heap.CreateReference(runtime_heap.TYPE_ENTRY, entry)
+}
+
+func nativeMap(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return createCollection(runtime_heap.TYPE_MAP), nil
+}
+
+func nativeSet(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return createCollection(runtime_heap.TYPE_SET), nil
+}
+
+// Value of key, unknown when map has no such key
+func nativeCollectionGet(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var value, isFound = arguments[0].Collection().Get(arguments[1])
+
+	if !isFound {
+		return runtime_heap.CreateUnknown(), nil
+	}
+
+	return value, nil
+}
+
+// Returns map, so calls can be chained
+func nativeMapSet(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var memoryErr = runtime.allocateEntry(arguments[0], arguments[1], node)
+
+	if memoryErr != nil {
+		return nil, memoryErr
+	}
+
+	arguments[0].Collection().Set(copyValue(arguments[1]), copyValue(arguments[2]))
+
+	return arguments[0], nil
+}
+
+func nativeSetAdd(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var memoryErr = runtime.allocateEntry(arguments[0], arguments[1], node)
+
+	if memoryErr != nil {
+		return nil, memoryErr
+	}
+
+	var value = copyValue(arguments[1])
+	arguments[0].Collection().Set(value, value)
+
+	return arguments[0], nil
+}
+
+func nativeCollectionHas(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return runtime_heap.CreateBoolean(arguments[0].Collection().Has(arguments[1])), nil
+}
+
+// True, when key existed
+func nativeCollectionDelete(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var isDeleted = arguments[0].Collection().Delete(arguments[1])
+
+	if isDeleted {
+		runtime.program.limits.release(getEntrySize(arguments[0]))
+	}
+
+	return runtime_heap.CreateBoolean(isDeleted), nil
+}
+
+// Map keeps key and value, set keeps only value
+func getEntrySize(collection *runtime_heap.VariableValue) int {
+	if collection.Kind == runtime_heap.TYPE_MAP {
+		return 2 * valueSize
+	}
+
+	return valueSize
+}
+
+// New key is counted, existing key only gets other value
+func (runtime *Runtime) allocateEntry(collection *runtime_heap.VariableValue, key *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	if collection.Collection().Has(key) {
+		return nil
+	}
+
+	return runtime.program.limits.allocate(getEntrySize(collection), node)
+}
+
+func nativeCollectionKeys(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var keys = []*runtime_heap.VariableValue{}
+
+	for _, entry := range arguments[0].Collection().Entries() {
+		keys = append(keys, entry.Key)
+	}
+
+	return createSequence(keys), nil
+}
+
+func nativeCollectionValues(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var values = []*runtime_heap.VariableValue{}
+
+	for _, entry := range arguments[0].Collection().Entries() {
+		values = append(values, entry.Value)
+	}
+
+	return createSequence(values), nil
+}
+
+func nativeCollectionEntries(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var entries = []*runtime_heap.VariableValue{}
+
+	for _, entry := range arguments[0].Collection().Entries() {
+		entries = append(entries, createEntry(entry))
+	}
+
+	return createSequence(entries), nil
+}
@@ -0,0 +1,173 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Statement completion types. Every type except normal is abrupt,
+// it stops execution of statements until completion reaches its boundary:
+// function call for return, loop for break and continue
+const COMPLETION_NORMAL = "NORMAL"
+const COMPLETION_RETURN = "RETURN"
+const COMPLETION_BREAK = "BREAK"
+const COMPLETION_CONTINUE = "CONTINUE"
+const COMPLETION_THROW = "THROW"
+
+type completion struct {
+	Type  string
+	Value *runtime_heap.VariableValue
+	Error error
+	// Statement, which made completion abrupt
+	Node *ast_node.ASTNode
+}
+
+// State of one function call, shared by all nested scopes of the call
+type frame struct {
+	completion completion
+	// Coroutine of generator or async task, which runs the call. Nil for regular function calls.
+	// Frame doesn't refer to generator value, so generator can be collected, while body is suspended
+	generatorCall *coroutine
+	asyncCall     *task
+	// Calls of "defer" statements, they run in reverse order when body ends
+	deferred []deferredCall
+	// False for top level code, which cannot defer calls
+	isFunction bool
+}
+
+func createFrame() *frame {
+	return &frame{
+		completion: completion{Type: COMPLETION_NORMAL},
+	}
+}
+
+// Generator or task of the call was stopped by runtime, before its body ended
+func (frame *frame) isClosed() bool {
+	if frame.generatorCall != nil && frame.generatorCall.isDone {
+		return true
+	}
+
+	return frame.asyncCall != nil && frame.asyncCall.coroutine.isDone
+}
+
+func (frame *frame) isAbrupt() bool {
+	return frame.completion.Type != COMPLETION_NORMAL
+}
+
+func (frame *frame) complete(completionType string, value *runtime_heap.VariableValue, node *ast_node.ASTNode) {
+	frame.completion = completion{
+		Type:  completionType,
+		Value: value,
+		Node:  node,
+	}
+}
+
+// Returned value is copied, so deferred calls, which assign returned variable, don't change result
+func (frame *frame) completeReturn(value *runtime_heap.VariableValue, node *ast_node.ASTNode) {
+	var result = *value
+	result.DeclaredType = ""
+	frame.complete(COMPLETION_RETURN, &result, node)
+}
+
+func (frame *frame) throw(err error, node *ast_node.ASTNode) {
+	frame.completion = completion{
+		Type:  COMPLETION_THROW,
+		Error: err,
+		Node:  node,
+	}
+}
+
+// Consumes break and continue at loop boundary.
+// Returns true, when loop should stop
+func (frame *frame) completeIteration() bool {
+	switch frame.completion.Type {
+	case COMPLETION_NORMAL:
+		return false
+
+	case COMPLETION_CONTINUE:
+		frame.completion = completion{Type: COMPLETION_NORMAL}
+		return false
+
+	case COMPLETION_BREAK:
+		frame.completion = completion{Type: COMPLETION_NORMAL}
+		return true
+	}
+
+	return true
+}
+
+// Runs statements one by one, until one of them completes abruptly.
+// Function declarations are hoisted, so they can be called before declaration.
+// Decorated functions are defined in place, because decorators can be values of statements above
+func (runtime *Runtime) executeStatements(nodes []*ast_node.ASTNode) error {
+	for _, node := range nodes {
+		if !ast_node.IsHoisted(node) {
+			continue
+		}
+
+		var _, err = runtime.visitFunctionNode(node)
+
+		if err != nil {
+			runtime.frame.throw(err, node)
+			return err
+		}
+	}
+
+	for _, node := range nodes {
+		if ast_node.IsHoisted(node) {
+			continue
+		}
+
+		var _, err = runtime.visitNode(node)
+
+		if err != nil {
+			runtime.frame.throw(err, node)
+			return err
+		}
+
+		if runtime.frame.isAbrupt() {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Runs function body and converts its completion into call result
+func (runtime *Runtime) runBody(body *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var _, bodyErr = runtime.executeBody(body)
+
+	if bodyErr != nil {
+		runtime.frame.throw(bodyErr, body)
+	}
+
+	var result, resultErr = runtime.getCallResult()
+	resultErr = runtime.runDeferredCalls(resultErr)
+	runtime.releaseCall()
+
+	return result, resultErr
+}
+
+// Converts completion of function body into result of function call
+func (runtime *Runtime) getCallResult() (*runtime_heap.VariableValue, error) {
+	var result = runtime.frame.completion
+
+	switch result.Type {
+	case COMPLETION_RETURN:
+		return result.Value, nil
+
+	case COMPLETION_THROW:
+		return nil, result.Error
+
+	case COMPLETION_BREAK, COMPLETION_CONTINUE:
+		return nil, runtime_error.CreateError(
+			"Cannot "+strings.ToLower(result.Type)+" outside of loop",
+			result.Node,
+		)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,187 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Value or error, which continues suspended coroutine
+type coroutineResume struct {
+	value *runtime_heap.VariableValue
+	err   error
+}
+
+// Value passed from coroutine, when it suspends or finishes
+type coroutineStep struct {
+	value  *runtime_heap.VariableValue
+	isDone bool
+	err    error
+}
+
+type coroutineCall func() (*runtime_heap.VariableValue, error)
+
+// Function call, which can be suspended in the middle of body.
+// Call runs in own goroutine. Caller and coroutine pass control
+// to each other through channels, so only one of them works at a time
+type coroutine struct {
+	call coroutineCall
+
+	resumes  chan coroutineResume
+	steps    chan coroutineStep
+	cancel   chan struct{}
+	finished chan struct{}
+
+	isStarted bool
+	isRunning bool
+	isDone    bool
+}
+
+var errCoroutineClosed = runtime_error.RuntimeError{
+	Message: "Coroutine closed",
+}
+
+func (program *program) createCoroutine(call coroutineCall) *coroutine {
+	var co = &coroutine{
+		call:     call,
+		resumes:  make(chan coroutineResume),
+		steps:    make(chan coroutineStep),
+		cancel:   make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+
+	program.addCoroutine(co)
+
+	return co
+}
+
+// Runs coroutine until next suspension or end of body
+func (co *coroutine) resume(value *runtime_heap.VariableValue, err error) coroutineStep {
+	if co.isDone {
+		return coroutineStep{
+			value:  runtime_heap.CreateUnknown(),
+			isDone: true,
+		}
+	}
+
+	if co.isRunning {
+		return coroutineStep{
+			err: runtime_error.RuntimeError{
+				Message: "Coroutine is already running",
+			},
+		}
+	}
+
+	if !co.isStarted {
+		co.isStarted = true
+		go co.run()
+	}
+
+	co.isRunning = true
+	co.resumes <- coroutineResume{value: value, err: err}
+	var step = <-co.steps
+	co.isRunning = false
+
+	if step.isDone {
+		co.isDone = true
+		<-co.finished
+	}
+
+	return step
+}
+
+// Stops suspended coroutine and waits until its goroutine exits
+func (co *coroutine) close() {
+	if co.isDone {
+		return
+	}
+
+	co.isDone = true
+	close(co.cancel)
+
+	if co.isStarted {
+		<-co.finished
+	}
+}
+
+func (co *coroutine) run() {
+	defer close(co.finished)
+
+	// Value of first resume has no suspension point to receive it
+	select {
+	case <-co.resumes:
+	case <-co.cancel:
+		return
+	}
+
+	var result, resultErr = co.call()
+
+	if result == nil {
+		result = runtime_heap.CreateUnknown()
+	}
+
+	select {
+	case co.steps <- coroutineStep{value: result, isDone: true, err: resultErr}:
+	case <-co.cancel:
+	}
+}
+
+// Called from coroutine goroutine. Passes value to caller and waits for next resume
+func (co *coroutine) suspend(value *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	select {
+	case co.steps <- coroutineStep{value: value}:
+	case <-co.cancel:
+		return nil, errCoroutineClosed
+	}
+
+	select {
+	case resume := <-co.resumes:
+		return resume.value, resume.err
+	case <-co.cancel:
+		return nil, errCoroutineClosed
+	}
+}
+
+func (program *program) addCoroutine(co *coroutine) {
+	program.closeReleasedCoroutines()
+
+	// Finished coroutines don't need to be closed anymore
+	var activeCoroutines = program.coroutines[:0]
+
+	for _, activeCoroutine := range program.coroutines {
+		if !activeCoroutine.isDone {
+			activeCoroutines = append(activeCoroutines, activeCoroutine)
+		}
+	}
+
+	program.coroutines = append(activeCoroutines, co)
+}
+
+// Called by finalizer of generator, from goroutine of garbage collector.
+// Coroutine is closed later by program, so its state is changed only by program
+func (program *program) releaseCoroutine(co *coroutine) {
+	program.releasedLock.Lock()
+	defer program.releasedLock.Unlock()
+
+	program.released = append(program.released, co)
+}
+
+func (program *program) closeReleasedCoroutines() {
+	program.releasedLock.Lock()
+	var released = program.released
+	program.released = nil
+	program.releasedLock.Unlock()
+
+	for _, co := range released {
+		co.close()
+	}
+}
+
+// Closes coroutines, which were not finished till end of program,
+// so their goroutines don't outlive the run
+func (program *program) closeCoroutines() {
+	for _, co := range program.coroutines {
+		co.close()
+	}
+
+	program.coroutines = nil
+}
@@ -0,0 +1,205 @@
+package runtime
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Passes declared function through its decorators. The last decorator is applied first,
+// so "@a @b function f() {}" is the same as "a(b(f))"
+func (runtime *Runtime) applyDecorators(node *ast_node.ASTNode, function *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var decorators = ast_node.GetFunctionDecorators(node)
+	var result = function
+
+	for index := len(decorators) - 1; index >= 0; index-- {
+		var decorator = decorators[index]
+		var decoratorNode = &ast_node.ASTNode{
+			StartPosition: decorator.StartPosition,
+			EndPosition:   decorator.EndPosition,
+		}
+		var slot, slotErr = getSlot(&decorator, decoratorNode)
+
+		if slotErr != nil {
+			return nil, slotErr
+		}
+
+		var decoratorFunction = runtime.heap.GetVariable(slot)
+
+		if decoratorFunction == nil {
+			return nil, runtime_error.CreateError(
+				"Decorator is not defined: "+decorator.Value,
+				decoratorNode,
+			)
+		}
+
+		if !isCallable(decoratorFunction) {
+			return nil, runtime_error.CreateError(
+				"Decorator "+decorator.Value+" is not a function. Received: "+runtime_heap.GetTypeName(decoratorFunction),
+				decoratorNode,
+			)
+		}
+
+		var decorated, decorateErr = runtime.callFunction(decoratorFunction, []*runtime_heap.VariableValue{result}, decoratorNode)
+
+		if decorateErr != nil {
+			return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+				"Decorator "+decorator.Value+" failed",
+				decoratorNode,
+			), decorateErr)
+		}
+
+		if decorated == nil || !isCallable(decorated) {
+			var typeName = "unknown"
+
+			if decorated != nil {
+				typeName = runtime_heap.GetTypeName(decorated)
+			}
+
+			return nil, runtime_error.CreateError(
+				"Decorator "+decorator.Value+" should return function. Received: "+typeName,
+				decoratorNode,
+			)
+		}
+
+		result = decorated
+	}
+
+	return result, nil
+}
+
+func isCallable(value *runtime_heap.VariableValue) bool {
+	return value.Kind == runtime_heap.TYPE_FUNCTION || value.Kind == runtime_heap.TYPE_NATIVE_FUNCTION
+}
+
+// Native function, which is created by program. It keeps name of wrapped function
+func createNativeClosure(function *runtime_heap.VariableValue, call nativeFunctionCall) *runtime_heap.VariableValue {
+	var name = getFunctionName(function)
+
+	return runtime_heap.CreateNativeFunction(name, nil, &nativeFunction{name: name, arity: -1, call: call})
+}
+
+func getFunctionArgument(arguments []*runtime_heap.VariableValue, functionName string, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if !isCallable(arguments[0]) {
+		return nil, runtime_error.CreateError(
+			"Function "+functionName+" expects function. But received: "+runtime_heap.GetTypeName(arguments[0]),
+			node,
+		)
+	}
+
+	// Copy, so later assignment to variable doesn't change wrapped function
+	var function = *arguments[0]
+
+	return &function, nil
+}
+
+// Wraps function with cache of results. Calls with the same primitive arguments
+// return cached result, calls with other values always run function
+func nativeMemo(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var function, functionErr = getFunctionArgument(arguments, "memo", node)
+
+	if functionErr != nil {
+		return nil, functionErr
+	}
+
+	var cache = map[string]*runtime_heap.VariableValue{}
+
+	return createNativeClosure(function, func(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+		var key, isCacheable = getMemoKey(arguments)
+
+		if isCacheable {
+			if result, isCached := cache[key]; isCached {
+				return result, nil
+			}
+		}
+
+		var result, err = runtime.callFunction(function, arguments, node)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isCacheable {
+			cache[key] = result
+		}
+
+		return result, nil
+	}), nil
+}
+
+// Key of arguments list. Types are part of key, so 1, 1n and "1" are cached separately
+func getMemoKey(arguments []*runtime_heap.VariableValue) (string, bool) {
+	var parts = make([]string, len(arguments))
+
+	for index, argument := range arguments {
+		switch argument.Kind {
+		case runtime_heap.TYPE_NUMBER:
+			// Zero and negative zero are the same key
+			if argument.NumberValue == 0 {
+				parts[index] = "number:0"
+			} else {
+				parts[index] = "number:" + strconv.FormatFloat(argument.NumberValue, 'g', -1, 64)
+			}
+		case runtime_heap.TYPE_INTEGER:
+			parts[index] = "integer:" + argument.IntegerValue.String()
+		case runtime_heap.TYPE_STRING:
+			parts[index] = "string:" + strconv.Quote(argument.StringValue)
+		case runtime_heap.TYPE_BOOLEAN:
+			parts[index] = "boolean:" + strconv.FormatBool(argument.BooleanValue)
+		case runtime_heap.TYPE_UNKNOWN:
+			parts[index] = "unknown"
+		default:
+			return "", false
+		}
+	}
+
+	return strings.Join(parts, ","), true
+}
+
+// Wraps function with logging of its calls and results, like "-> fib(2)" and "<- fib(2) = 1".
+// Nested calls are indented
+func nativeTrace(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var function, functionErr = getFunctionArgument(arguments, "trace", node)
+
+	if functionErr != nil {
+		return nil, functionErr
+	}
+
+	var name = getFunctionName(function)
+
+	return createNativeClosure(function, func(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+		var values = make([]string, len(arguments))
+
+		for index, argument := range arguments {
+			values[index] = runtime_heap.InspectCollection(argument)
+		}
+
+		var call = name + "(" + strings.Join(values, ", ") + ")"
+		var indent = strings.Repeat("  ", runtime.program.traceDepth)
+
+		runtime.bridge.Print(runtime_heap.CreateString(indent + "-> " + call))
+		runtime.program.traceDepth++
+
+		var result, err = runtime.callFunction(function, arguments, node)
+
+		runtime.program.traceDepth--
+
+		if err != nil {
+			runtime.bridge.Print(runtime_heap.CreateString(indent + "<- " + call + " failed"))
+			return nil, err
+		}
+
+		var resultText = "unknown"
+
+		if result != nil {
+			resultText = runtime_heap.InspectCollection(result)
+		}
+
+		runtime.bridge.Print(runtime_heap.CreateString(indent + "<- " + call + " = " + resultText))
+
+		return result, nil
+	}), nil
+}
@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Function and arguments are evaluated by "defer" statement, call runs when function body ends
+type deferredCall struct {
+	function  *runtime_heap.VariableValue
+	arguments []*runtime_heap.VariableValue
+	node      *ast_node.ASTNode
+}
+
+func (runtime *Runtime) visitDeferNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if !runtime.frame.isFunction {
+		return nil, runtime_error.CreateError(
+			"Cannot defer outside of function",
+			node,
+		)
+	}
+
+	var callNode = node.Body[0]
+	var functionVariable, functionErr = runtime.visitNode(callNode.Body[0])
+
+	if functionErr != nil {
+		return nil, functionErr
+	}
+
+	if functionVariable == nil || (functionVariable.Kind != runtime_heap.TYPE_FUNCTION && functionVariable.Kind != runtime_heap.TYPE_NATIVE_FUNCTION) {
+		return nil, runtime_error.CreateError(
+			"Is not a function",
+			callNode.Body[0],
+		)
+	}
+
+	var argumentsValues, argumentsErr = runtime.getArgumentsValues(callNode)
+
+	if argumentsErr != nil {
+		return nil, argumentsErr
+	}
+
+	argumentsValues, argumentsErr = runtime.bindArguments(functionVariable, callNode, argumentsValues)
+
+	if argumentsErr != nil {
+		return nil, argumentsErr
+	}
+
+	runtime.frame.deferred = append(runtime.frame.deferred, deferredCall{
+		function:  functionVariable,
+		arguments: argumentsValues,
+		node:      callNode,
+	})
+
+	return nil, nil
+}
+
+// Runs deferred calls in reverse order. Errors of deferred calls are chained to error of function body.
+// Generator or task, which was stopped before end of body, doesn't run deferred calls
+func (runtime *Runtime) runDeferredCalls(err error) error {
+	if runtime.frame.isClosed() {
+		return err
+	}
+
+	for len(runtime.frame.deferred) > 0 {
+		var lastIndex = len(runtime.frame.deferred) - 1
+		var call = runtime.frame.deferred[lastIndex]
+		runtime.frame.deferred = runtime.frame.deferred[:lastIndex]
+
+		var _, callErr = runtime.callFunction(call.function, call.arguments, call.node)
+
+		if callErr == nil {
+			continue
+		}
+
+		var deferredErr = runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Deferred call failed",
+			call.node,
+		), callErr)
+
+		if err == nil {
+			err = deferredErr
+		} else {
+			err = runtime_error.AddDeferredError(err, deferredErr)
+		}
+	}
+
+	return err
+}
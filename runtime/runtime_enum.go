@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"math/big"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+func (runtime *Runtime) visitEnumNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var enumNameParam = ast_node.GetVariableNameParam(node)
+
+	if enumNameParam == nil {
+		return nil, runtime_error.CreateError(
+			"Cannot define enum without name",
+			node,
+		)
+	}
+
+	var enum = runtime_heap.CreateEnum(enumNameParam.Value)
+	var value = runtime_heap.CreateNumber(0)
+
+	for _, memberNode := range node.Body {
+		var memberNameParam = ast_node.GetVariableNameParam(memberNode)
+
+		if memberNameParam == nil {
+			return nil, runtime_error.CreateError(
+				"Cannot define enum member without name",
+				memberNode,
+			)
+		}
+
+		if len(memberNode.Body) > 0 {
+			var memberValue, memberValueErr = runtime.visitNode(memberNode.Body[0])
+
+			if memberValueErr != nil {
+				return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+					"Cannot get value of enum member: "+memberNameParam.Value,
+					memberNode,
+				), memberValueErr)
+			}
+
+			value = memberValue
+		}
+
+		if value == nil {
+			return nil, runtime_error.CreateError(
+				"Enum member after string value should have value",
+				memberNode,
+			)
+		}
+
+		enum.AddMember(memberNameParam.Value, value)
+		value = getNextEnumValue(value)
+	}
+
+	var slot, createEnumErr = getSlot(enumNameParam, node)
+
+	if createEnumErr == nil {
+		createEnumErr = runtime.heap.CreateVariable(slot.Index)
+	}
+
+	if createEnumErr != nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot define enum with name: "+enumNameParam.Value,
+			node,
+		), createEnumErr)
+	}
+
+	var enumValue = runtime_heap.CreateReference(runtime_heap.TYPE_ENUM, enum)
+
+	var setEnumErr = runtime.heap.SetVariable(slot, enumValue)
+
+	if setEnumErr != nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot set enum into variable with name: "+enumNameParam.Value,
+			node,
+		), setEnumErr)
+	}
+
+	return enumValue, nil
+}
+
+// Value of member without own value, previous value plus one. String cannot be counted
+func getNextEnumValue(value *runtime_heap.VariableValue) *runtime_heap.VariableValue {
+	switch value.Kind {
+	case runtime_heap.TYPE_NUMBER:
+		return runtime_heap.CreateNumber(value.NumberValue + 1)
+	case runtime_heap.TYPE_INTEGER:
+		return runtime_heap.CreateInteger(new(big.Int).Add(value.IntegerValue, big.NewInt(1)))
+	}
+
+	return nil
+}
+
+// Members are fixed at declaration, so assignment like "Color.Red = 1" fails
+func (runtime *Runtime) checkEnumAssignment(targetNode *ast_node.ASTNode) error {
+	if len(targetNode.Body) == 0 {
+		return nil
+	}
+
+	var objectValue, objectErr = runtime.visitNode(targetNode.Body[0])
+
+	if objectErr != nil {
+		return objectErr
+	}
+
+	var enum *runtime_heap.Enum
+
+	switch {
+	case objectValue == nil:
+	case objectValue.Kind == runtime_heap.TYPE_ENUM:
+		enum = objectValue.Enum()
+	case objectValue.Kind == runtime_heap.TYPE_ENUM_MEMBER:
+		enum = objectValue.EnumMember().Enum
+	}
+
+	if enum == nil {
+		return nil
+	}
+
+	return runtime_error.CreateError(
+		"Cannot assign to member of enum "+enum.Name+", enums are immutable",
+		targetNode,
+	)
+}
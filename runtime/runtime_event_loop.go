@@ -0,0 +1,224 @@
+package runtime
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Source of time for timers, in milliseconds
+type IClock interface {
+	Now() float64
+	// Waits for duration, but returns earlier, when cancel is closed
+	Sleep(duration float64, cancel <-chan struct{})
+}
+
+// Unit of work for event loop, like timer callback or promise reaction
+type job func() error
+
+type timer struct {
+	id int
+	at float64
+	// Zero for timeout, delay between runs for interval
+	interval float64
+	job      job
+}
+
+// Runs jobs one by one. Ready jobs run first, then runtime waits for closest timer
+type eventLoop struct {
+	clock       IClock
+	jobs        []job
+	timers      []*timer
+	lastTimerId int
+	// Rejected promises, which had no handler at rejection
+	rejectedPromises []*promise
+	// Picks ready jobs in random order, when set. Nil runs jobs in order of enqueue
+	random *rand.Rand
+}
+
+func createEventLoop(clock IClock) *eventLoop {
+	return &eventLoop{
+		clock: clock,
+	}
+}
+
+func (loop *eventLoop) enqueue(newJob job) {
+	loop.jobs = append(loop.jobs, newJob)
+}
+
+func (loop *eventLoop) nextJob() job {
+	var index = 0
+
+	if loop.random != nil {
+		index = loop.random.Intn(len(loop.jobs))
+	}
+
+	var nextJob = loop.jobs[index]
+	loop.jobs = append(loop.jobs[:index], loop.jobs[index+1:]...)
+
+	return nextJob
+}
+
+func (loop *eventLoop) addTimer(delay float64, interval float64, timerJob job) int {
+	if delay < 0 {
+		delay = 0
+	}
+
+	loop.lastTimerId++
+	loop.timers = append(loop.timers, &timer{
+		id:       loop.lastTimerId,
+		at:       loop.clock.Now() + delay,
+		interval: interval,
+		job:      timerJob,
+	})
+
+	return loop.lastTimerId
+}
+
+func (loop *eventLoop) removeTimer(id int) {
+	for index, existingTimer := range loop.timers {
+		if existingTimer.id == id {
+			loop.timers = append(loop.timers[:index], loop.timers[index+1:]...)
+			return
+		}
+	}
+}
+
+// Closest timer. Timers with same time run in order of creation
+func (loop *eventLoop) nextTimer() *timer {
+	if len(loop.timers) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(loop.timers, func(i, j int) bool {
+		if loop.timers[i].at == loop.timers[j].at {
+			return loop.timers[i].id < loop.timers[j].id
+		}
+
+		return loop.timers[i].at < loop.timers[j].at
+	})
+
+	return loop.timers[0]
+}
+
+// Runs until no jobs and timers are pending. Waiting for timers is stopped by context of limits
+func (loop *eventLoop) run(limits *limits, node *ast_node.ASTNode) error {
+	for {
+		if len(loop.jobs) > 0 {
+			var currentJob = loop.nextJob()
+			var jobErr = currentJob()
+
+			if jobErr != nil {
+				return jobErr
+			}
+
+			continue
+		}
+
+		var closestTimer = loop.nextTimer()
+
+		if closestTimer == nil {
+			return loop.getUnhandledRejection()
+		}
+
+		var sleepErr = limits.sleep(loop.clock, closestTimer.at-loop.clock.Now(), node)
+
+		if sleepErr != nil {
+			return sleepErr
+		}
+
+		if closestTimer.interval > 0 {
+			closestTimer.at = closestTimer.at + closestTimer.interval
+		} else {
+			loop.removeTimer(closestTimer.id)
+		}
+
+		loop.enqueue(closestTimer.job)
+	}
+}
+
+func (loop *eventLoop) getUnhandledRejection() error {
+	for _, rejected := range loop.rejectedPromises {
+		if !rejected.isHandled {
+			return runtime_error.MergeRuntimeErrors(runtime_error.RuntimeError{
+				Message: "Unhandled promise rejection",
+			}, rejected.err)
+		}
+	}
+
+	return nil
+}
+
+const PROMISE_PENDING = "PENDING"
+const PROMISE_FULFILLED = "FULFILLED"
+const PROMISE_REJECTED = "REJECTED"
+
+type promiseReaction func(value *runtime_heap.VariableValue, err error) error
+
+// Result of async operation, which will be known later
+type promise struct {
+	state     string
+	value     *runtime_heap.VariableValue
+	err       error
+	reactions []promiseReaction
+	isHandled bool
+}
+
+func createPromise() *promise {
+	return &promise{
+		state: PROMISE_PENDING,
+	}
+}
+
+func (p *promise) GetState() string {
+	return p.state
+}
+
+func createPromiseValue(p *promise) *runtime_heap.VariableValue {
+	return runtime_heap.CreateReference(runtime_heap.TYPE_PROMISE, p)
+}
+
+// Fulfills promise with value, or rejects it with error. Reactions run as separate jobs
+func (loop *eventLoop) settle(p *promise, value *runtime_heap.VariableValue, err error) {
+	if p.state != PROMISE_PENDING {
+		return
+	}
+
+	p.value = value
+	p.err = err
+	p.state = PROMISE_FULFILLED
+
+	if err != nil {
+		p.state = PROMISE_REJECTED
+
+		if len(p.reactions) == 0 {
+			loop.rejectedPromises = append(loop.rejectedPromises, p)
+		}
+	}
+
+	for _, reaction := range p.reactions {
+		loop.enqueueReaction(p, reaction)
+	}
+
+	p.reactions = nil
+}
+
+func (loop *eventLoop) then(p *promise, reaction promiseReaction) {
+	p.isHandled = true
+
+	if p.state == PROMISE_PENDING {
+		p.reactions = append(p.reactions, reaction)
+		return
+	}
+
+	loop.enqueueReaction(p, reaction)
+}
+
+func (loop *eventLoop) enqueueReaction(p *promise, reaction promiseReaction) {
+	loop.enqueue(func() error {
+		return reaction(p.value, p.err)
+	})
+}
@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	goruntime "runtime"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Generator call, body is resumed by every "next" call till next yield
+type generator struct {
+	coroutine *coroutine
+}
+
+func (runtime *Runtime) createGenerator(callRuntime *Runtime, body *ast_node.ASTNode) *runtime_heap.VariableValue {
+	var gen = &generator{
+		coroutine: runtime.program.createCoroutine(func() (*runtime_heap.VariableValue, error) {
+			var result, err = callRuntime.runBody(body)
+
+			return result, callRuntime.call.attachStack(err)
+		}),
+	}
+
+	callRuntime.frame.generatorCall = gen.coroutine
+
+	// Generator, which is not reachable by program anymore, can't be resumed,
+	// so its suspended body is closed, instead of waiting for end of program
+	var program = runtime.program
+	goruntime.SetFinalizer(gen, func(gen *generator) {
+		program.releaseCoroutine(gen.coroutine)
+	})
+
+	return runtime_heap.CreateReference(runtime_heap.TYPE_GENERATOR, gen)
+}
+
+// Returns yielded value, or returned value when generator is done
+func (gen *generator) Next(value *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var step = gen.coroutine.resume(value, nil)
+
+	return step.value, step.err
+}
+
+func (gen *generator) IsDone() bool {
+	return gen.coroutine.isDone
+}
+
+func (gen *generator) Close() {
+	gen.coroutine.close()
+}
+
+func (runtime *Runtime) visitYieldNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if runtime.frame.generatorCall == nil {
+		return nil, runtime_error.CreateError(
+			"Cannot yield outside of generator function",
+			node,
+		)
+	}
+
+	var value = runtime_heap.CreateUnknown()
+
+	if len(node.Body) > 0 {
+		var bodyValue, bodyErr = runtime.visitNode(node.Body[0])
+
+		if bodyErr != nil {
+			return nil, bodyErr
+		}
+
+		if bodyValue != nil {
+			value = bodyValue
+		}
+	}
+
+	var resumeValue, resumeErr = runtime.frame.generatorCall.suspend(value)
+
+	if resumeErr != nil {
+		return nil, resumeErr
+	}
+
+	if resumeValue == nil {
+		return runtime_heap.CreateUnknown(), nil
+	}
+
+	return resumeValue, nil
+}
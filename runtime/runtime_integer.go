@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Exact arithmetic of integers. Division truncates toward zero
+// and remainder has sign of dividend, so (a / b) * b + a % b == a
+func calculateIntegers(operator string, left *big.Int, right *big.Int, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	switch operator {
+	case "+":
+		return runtime_heap.CreateInteger(new(big.Int).Add(left, right)), nil
+	case "-":
+		return runtime_heap.CreateInteger(new(big.Int).Sub(left, right)), nil
+	case "*":
+		return runtime_heap.CreateInteger(new(big.Int).Mul(left, right)), nil
+	case "/", "%":
+		if right.Sign() == 0 {
+			return nil, runtime_error.CreateError(
+				"Integer division by zero",
+				node,
+			)
+		}
+
+		if operator == "/" {
+			return runtime_heap.CreateInteger(new(big.Int).Quo(left, right)), nil
+		}
+
+		return runtime_heap.CreateInteger(new(big.Int).Rem(left, right)), nil
+	}
+
+	return nil, runtime_error.CreateError(
+		"Unknown integer expression. Received: "+operator,
+		node,
+	)
+}
+
+func isNumeric(value *runtime_heap.VariableValue) bool {
+	return value.Kind == runtime_heap.TYPE_NUMBER || value.Kind == runtime_heap.TYPE_INTEGER
+}
+
+// Compares integer with float exactly, without rounding of integer.
+// NaN is not ordered with any value
+func compareMixedNumbers(operator string, left *runtime_heap.VariableValue, right *runtime_heap.VariableValue) bool {
+	var leftFloat, isLeftValid = toExactFloat(left)
+	var rightFloat, isRightValid = toExactFloat(right)
+
+	if !isLeftValid || !isRightValid {
+		return false
+	}
+
+	var order = leftFloat.Cmp(rightFloat)
+
+	switch operator {
+	case ">":
+		return order > 0
+	case "<":
+		return order < 0
+	case ">=":
+		return order >= 0
+	case "<=":
+		return order <= 0
+	}
+
+	return false
+}
+
+func toExactFloat(value *runtime_heap.VariableValue) (*big.Float, bool) {
+	if value.Kind == runtime_heap.TYPE_INTEGER {
+		return new(big.Float).SetInt(value.IntegerValue), true
+	}
+
+	if math.IsNaN(value.NumberValue) {
+		return nil, false
+	}
+
+	return big.NewFloat(value.NumberValue), true
+}
@@ -0,0 +1,209 @@
+package runtime
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Depth of calls, when options don't set it. Deeper recursion of tree walker overflows stack of Go
+const DEFAULT_MAX_CALL_DEPTH = 10000
+
+// Context is checked once per this count of steps, not on every node
+const contextCheckInterval = 1024
+
+// Approximate size of one value in heap slot or collection
+const valueSize = int(unsafe.Sizeof(runtime_heap.VariableValue{}))
+
+// Size of one word of big integer
+const wordSize = int(unsafe.Sizeof(big.Word(0)))
+
+// Limits of program run. Zero limit is not checked, except call depth, which gets default value
+type Options struct {
+	// Count of evaluated nodes. In bytecode mode executed instructions are counted too
+	MaxSteps int
+	// Count of nested function calls
+	MaxCallDepth int
+	// Approximate count of bytes used by heaps of running calls, strings and integers stored in variables,
+	// and collection entries. Heaps and values of variables are returned to the budget, when variable gets
+	// other value or its call or scope ends. Entries are counted, until they are deleted from collection.
+	// Temporary string or integer is only checked to fit into the budget
+	MaxMemory int
+	// Program stops, when context is cancelled or its deadline passes
+	Context context.Context
+}
+
+// Usage of limits by program run
+type limits struct {
+	options Options
+	steps   int
+	memory  int
+}
+
+// Makes runtime stop program with error, when it exceeds one of limits
+func (runtime *Runtime) SetOptions(options Options) {
+	if options.MaxCallDepth == 0 {
+		options.MaxCallDepth = DEFAULT_MAX_CALL_DEPTH
+	}
+
+	runtime.program.limits.options = options
+}
+
+// Counts step of evaluation of node, context is checked on every few steps
+func (limits *limits) step(node *ast_node.ASTNode) error {
+	limits.steps++
+
+	if limits.options.MaxSteps > 0 && limits.steps > limits.options.MaxSteps {
+		return runtime_error.CreateLimitError(
+			runtime_error.KIND_STEP_LIMIT,
+			"Step limit exceeded: "+strconv.Itoa(limits.options.MaxSteps),
+			node,
+		)
+	}
+
+	if limits.options.Context != nil && limits.steps%contextCheckInterval == 0 {
+		return limits.checkContext(node)
+	}
+
+	return nil
+}
+
+func (limits *limits) checkContext(node *ast_node.ASTNode) error {
+	var err = limits.options.Context.Err()
+
+	// Timer of deadline can't fire, while busy program holds the only thread of wasm
+	if deadline, hasDeadline := limits.options.Context.Deadline(); err == nil && hasDeadline && !time.Now().Before(deadline) {
+		err = context.DeadlineExceeded
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	return runtime_error.CreateLimitError(
+		runtime_error.KIND_CANCELLED,
+		"Execution cancelled: "+err.Error(),
+		node,
+	)
+}
+
+func (limits *limits) checkCallDepth(depth int, node *ast_node.ASTNode) error {
+	if depth <= limits.options.MaxCallDepth {
+		return nil
+	}
+
+	return runtime_error.CreateLimitError(
+		runtime_error.KIND_CALL_DEPTH_LIMIT,
+		"Call depth limit exceeded: "+strconv.Itoa(limits.options.MaxCallDepth),
+		node,
+	)
+}
+
+// Waits for timer, but stops waiting, when context is cancelled or its deadline passes
+func (limits *limits) sleep(clock IClock, duration float64, node *ast_node.ASTNode) error {
+	if limits.options.Context == nil {
+		clock.Sleep(duration, nil)
+
+		return nil
+	}
+
+	var contextErr = limits.checkContext(node)
+
+	if contextErr != nil {
+		return contextErr
+	}
+
+	clock.Sleep(duration, limits.options.Context.Done())
+
+	return limits.checkContext(node)
+}
+
+// Adds size in bytes to used memory
+func (limits *limits) allocate(size int, node *ast_node.ASTNode) error {
+	var err = limits.checkMemory(size, node)
+
+	if err == nil {
+		limits.memory += size
+	}
+
+	return err
+}
+
+// Checks, that size in bytes can be allocated, before value is built
+func (limits *limits) checkMemory(size int, node *ast_node.ASTNode) error {
+	if limits.options.MaxMemory <= 0 || size <= limits.options.MaxMemory-limits.memory {
+		return nil
+	}
+
+	return runtime_error.CreateLimitError(
+		runtime_error.KIND_MEMORY_LIMIT,
+		"Memory limit exceeded: "+strconv.Itoa(limits.options.MaxMemory)+" bytes",
+		node,
+	)
+}
+
+// Returns size in bytes, which is not used anymore
+func (limits *limits) release(size int) {
+	limits.memory -= size
+
+	if limits.memory < 0 {
+		limits.memory = 0
+	}
+}
+
+// Size of string or big integer in bytes. Other values have fixed size, which is counted by heap of call,
+// integer of one word is counted as fixed size too, otherwise every counter of loop would use budget
+func getValueSize(value *runtime_heap.VariableValue) int {
+	if value == nil {
+		return 0
+	}
+
+	switch value.Kind {
+	case runtime_heap.TYPE_STRING:
+		return valueSize + len(value.StringValue)
+	case runtime_heap.TYPE_INTEGER:
+		if value.IntegerValue == nil || len(value.IntegerValue.Bits()) <= 1 {
+			return 0
+		}
+
+		return valueSize + len(value.IntegerValue.Bits())*wordSize
+	}
+
+	return 0
+}
+
+// Checks, that new value fits into budget. Value is counted, when it is stored in variable
+func (limits *limits) checkValue(value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	return limits.checkMemory(getValueSize(value), node)
+}
+
+// Counts value stored in variable instead of replaced one
+func (limits *limits) replaceValue(prevValue *runtime_heap.VariableValue, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	return limits.allocate(getValueSize(value)-getValueSize(prevValue), node)
+}
+
+// Values of variables of finished scope are not counted anymore
+func (limits *limits) releaseScope(heap iHeap) {
+	for _, value := range heap.GetValues() {
+		limits.release(getValueSize(value))
+	}
+}
+
+// Size of heap of function call
+func getCallMemory(function *runtime_heap.VariableValue) int {
+	return function.Function().Node.ScopeSize * valueSize
+}
+
+// Heap of finished call is not counted anymore, even when closure still refers to it
+func (runtime *Runtime) releaseCall() {
+	if runtime.call != nil {
+		runtime.program.limits.releaseScope(runtime.heap)
+		runtime.program.limits.release(getCallMemory(runtime.call.function))
+	}
+}
@@ -0,0 +1,183 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Sequence of values, which can be consumed by for loop
+type iterator struct {
+	next func() (value *runtime_heap.VariableValue, isDone bool, err error)
+	// Called when loop stops before sequence end
+	close func()
+}
+
+func (runtime *Runtime) getIterator(value *runtime_heap.VariableValue, node *ast_node.ASTNode) (*iterator, error) {
+	switch value.Kind {
+	case runtime_heap.TYPE_GENERATOR:
+		var gen = value.Generator()
+
+		return &iterator{
+			next: func() (*runtime_heap.VariableValue, bool, error) {
+				var item, err = gen.Next(nil)
+
+				return item, gen.IsDone(), err
+			},
+			close: gen.Close,
+		}, nil
+
+	// Map gives entries, set gives values
+	case runtime_heap.TYPE_MAP:
+		var entries, _ = nativeCollectionEntries(runtime, node, []*runtime_heap.VariableValue{value})
+
+		return runtime.getIterator(entries, node)
+
+	case runtime_heap.TYPE_SET:
+		var values, _ = nativeCollectionValues(runtime, node, []*runtime_heap.VariableValue{value})
+
+		return runtime.getIterator(values, node)
+
+	// Values are received until channel is closed
+	case runtime_heap.TYPE_CHANNEL:
+		var ch = value.Channel().(*channel)
+
+		return &iterator{
+			next: func() (*runtime_heap.VariableValue, bool, error) {
+				var item, isReceived, err = runtime.receive(ch, node)
+
+				return item, !isReceived, err
+			},
+			close: func() {},
+		}, nil
+	}
+
+	return nil, runtime_error.CreateError(
+		"Value is not iterable. Received: "+runtime_heap.GetTypeName(value),
+		node,
+	)
+}
+
+func (runtime *Runtime) visitWhileNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if len(node.Arguments) != 1 || len(node.Body) != 1 {
+		return nil, runtime_error.CreateError(
+			"While loop should have condition and body",
+			node,
+		)
+	}
+
+	for {
+		var conditionValue, conditionErr = runtime.visitNode(node.Arguments[0])
+
+		if conditionErr != nil || conditionValue == nil {
+			return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+				"Cannot get while loop condition value",
+				node.Arguments[0],
+			), conditionErr)
+		}
+
+		if !runtime_heap.IsTruthy(conditionValue) {
+			return nil, nil
+		}
+
+		// Every iteration has own scope, so variables of body can be declared again
+		var iterationRuntime = runtime.createScope(node.ScopeSize)
+		var _, bodyErr = iterationRuntime.visitNode(node.Body[0])
+		runtime.program.limits.releaseScope(iterationRuntime.heap)
+
+		if bodyErr != nil {
+			return nil, bodyErr
+		}
+
+		if runtime.frame.completeIteration() {
+			return nil, nil
+		}
+	}
+}
+
+func (runtime *Runtime) visitForOfNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var itemName = ast_node.GetVariableNameParam(node)
+
+	if itemName == nil || len(node.Arguments) != 1 || len(node.Body) != 1 {
+		return nil, runtime_error.CreateError(
+			"For loop should have variable, iterable value and body",
+			node,
+		)
+	}
+
+	var iterableValue, iterableErr = runtime.visitNode(node.Arguments[0])
+
+	if iterableErr != nil || iterableValue == nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Cannot get iterable value of for loop",
+			node.Arguments[0],
+		), iterableErr)
+	}
+
+	var items, iteratorErr = runtime.getIterator(iterableValue, node.Arguments[0])
+
+	if iteratorErr != nil {
+		return nil, iteratorErr
+	}
+
+	for {
+		var item, isDone, itemErr = items.next()
+
+		if itemErr != nil {
+			return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+				"Cannot get next item of for loop",
+				node.Arguments[0],
+			), itemErr)
+		}
+
+		if isDone {
+			return nil, nil
+		}
+
+		var iterationRuntime = runtime.createScope(node.ScopeSize)
+		var slot, createItemErr = getSlot(itemName, node)
+
+		if createItemErr == nil {
+			createItemErr = iterationRuntime.heap.CreateVariable(slot.Index)
+		}
+
+		if createItemErr == nil {
+			createItemErr = iterationRuntime.setVariable(slot, item, node)
+		}
+
+		if createItemErr != nil {
+			items.close()
+			runtime.program.limits.releaseScope(iterationRuntime.heap)
+
+			return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+				"Cannot create loop variable: "+itemName.Value,
+				node,
+			), createItemErr)
+		}
+
+		var _, bodyErr = iterationRuntime.visitNode(node.Body[0])
+		runtime.program.limits.releaseScope(iterationRuntime.heap)
+
+		if bodyErr != nil {
+			items.close()
+
+			return nil, bodyErr
+		}
+
+		if runtime.frame.completeIteration() {
+			items.close()
+
+			return nil, nil
+		}
+	}
+}
+
+func (runtime *Runtime) visitLoopControlNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if node.Code == ast_node.AST_NODE_CODE_BREAK {
+		runtime.frame.complete(COMPLETION_BREAK, nil, node)
+	} else {
+		runtime.frame.complete(COMPLETION_CONTINUE, nil, node)
+	}
+
+	return nil, nil
+}
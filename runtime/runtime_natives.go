@@ -0,0 +1,603 @@
+package runtime
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/ast_resolver"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+type nativeFunctionCall func(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error)
+
+type nativeFunction struct {
+	name string
+	// Expected arguments count, -1 for any count
+	arity int
+	call  nativeFunctionCall
+}
+
+var nativeFunctions []nativeFunction
+
+// Methods of native types, receiver is passed as first argument
+var nativeMethods map[runtime_heap.ValueKind][]nativeFunction
+
+func init() {
+	nativeFunctions = []nativeFunction{
+		{name: "print", arity: -1, call: nativePrint},
+		{name: "isNumber", arity: 1, call: createTypeCheck(runtime_heap.TYPE_NUMBER)},
+		{name: "isString", arity: 1, call: createTypeCheck(runtime_heap.TYPE_STRING)},
+		{name: "isInteger", arity: 1, call: createTypeCheck(runtime_heap.TYPE_INTEGER)},
+		{name: "isBoolean", arity: 1, call: createTypeCheck(runtime_heap.TYPE_BOOLEAN)},
+		{name: "isFunction", arity: 1, call: createTypeCheck(runtime_heap.TYPE_FUNCTION, runtime_heap.TYPE_NATIVE_FUNCTION)},
+		{name: "isNative", arity: 1, call: createTypeCheck(runtime_heap.TYPE_NATIVE_FUNCTION)},
+		{name: "isMap", arity: 1, call: createTypeCheck(runtime_heap.TYPE_MAP)},
+		{name: "isSet", arity: 1, call: createTypeCheck(runtime_heap.TYPE_SET)},
+		{name: "isUnknown", arity: 1, call: createTypeCheck(runtime_heap.TYPE_UNKNOWN)},
+		{name: "toInteger", arity: 1, call: createCast(runtime_heap.CastToInteger)},
+		{name: "toNumber", arity: 1, call: createCast(runtime_heap.CastToNumber)},
+		{name: "fnName", arity: 1, call: nativeFunctionName},
+		{name: "fnArity", arity: 1, call: nativeFunctionArity},
+		{name: "setTimeout", arity: 2, call: createTimerSetter(false)},
+		{name: "setInterval", arity: 2, call: createTimerSetter(true)},
+		{name: "clearTimeout", arity: 1, call: nativeClearTimer},
+		{name: "clearInterval", arity: 1, call: nativeClearTimer},
+		{name: "sleep", arity: 1, call: nativeSleep},
+		{name: "now", arity: 0, call: nativeNow},
+		{name: "Map", arity: 0, call: nativeMap},
+		{name: "Set", arity: 0, call: nativeSet},
+		{name: "chan", arity: -1, call: nativeChan},
+		{name: "send", arity: 2, call: nativeSend},
+		{name: "receive", arity: 1, call: nativeReceive},
+		{name: "close", arity: 1, call: nativeClose},
+		{name: "select", arity: -1, call: nativeSelect},
+		{name: "memo", arity: 1, call: nativeMemo},
+		{name: "trace", arity: 1, call: nativeTrace},
+	}
+
+	nativeMethods = map[runtime_heap.ValueKind][]nativeFunction{
+		runtime_heap.TYPE_GENERATOR: {
+			{name: "next", arity: -1, call: nativeGeneratorNext},
+		},
+		runtime_heap.TYPE_STRING: {
+			{name: "toUpper", arity: 0, call: nativeStringToUpper},
+			{name: "toLower", arity: 0, call: nativeStringToLower},
+			{name: "trim", arity: 0, call: nativeStringTrim},
+			{name: "split", arity: 1, call: nativeStringSplit},
+			{name: "slice", arity: -1, call: nativeStringSlice},
+			{name: "indexOf", arity: 1, call: nativeStringIndexOf},
+			{name: "includes", arity: 1, call: nativeStringIncludes},
+			{name: "startsWith", arity: 1, call: nativeStringStartsWith},
+			{name: "endsWith", arity: 1, call: nativeStringEndsWith},
+			{name: "replace", arity: 2, call: createStringReplace(1)},
+			{name: "replaceAll", arity: 2, call: createStringReplace(-1)},
+			{name: "repeat", arity: 1, call: nativeStringRepeat},
+			{name: "at", arity: 1, call: nativeStringAt},
+		},
+		runtime_heap.TYPE_NUMBER: {
+			{name: "toFixed", arity: 1, call: nativeNumberToFixed},
+			{name: "toString", arity: 0, call: nativeToString},
+		},
+		runtime_heap.TYPE_INTEGER: {
+			{name: "toString", arity: 0, call: nativeToString},
+		},
+		runtime_heap.TYPE_MAP: {
+			{name: "get", arity: 1, call: nativeCollectionGet},
+			{name: "set", arity: 2, call: nativeMapSet},
+			{name: "has", arity: 1, call: nativeCollectionHas},
+			{name: "delete", arity: 1, call: nativeCollectionDelete},
+			{name: "keys", arity: 0, call: nativeCollectionKeys},
+			{name: "values", arity: 0, call: nativeCollectionValues},
+			{name: "entries", arity: 0, call: nativeCollectionEntries},
+		},
+		runtime_heap.TYPE_SET: {
+			{name: "add", arity: 1, call: nativeSetAdd},
+			{name: "has", arity: 1, call: nativeCollectionHas},
+			{name: "delete", arity: 1, call: nativeCollectionDelete},
+			{name: "keys", arity: 0, call: nativeCollectionKeys},
+			{name: "values", arity: 0, call: nativeCollectionValues},
+			{name: "entries", arity: 0, call: nativeCollectionEntries},
+		},
+	}
+}
+
+func getNativeFunction(name string) *nativeFunction {
+	for index := range nativeFunctions {
+		if nativeFunctions[index].name == name {
+			return &nativeFunctions[index]
+		}
+	}
+
+	return nil
+}
+
+func getNativeMethod(kind runtime_heap.ValueKind, name string) *nativeFunction {
+	var methods = nativeMethods[kind]
+
+	for index := range methods {
+		if methods[index].name == name {
+			return &methods[index]
+		}
+	}
+
+	return nil
+}
+
+// Native function or method, which is stored in variable
+func getNativeOf(function *runtime_heap.VariableValue) *nativeFunction {
+	if native, isClosure := function.NativeFunction().Closure.(*nativeFunction); isClosure {
+		return native
+	}
+
+	if function.NativeFunction().Receiver != nil {
+		return getNativeMethod(function.NativeFunction().Receiver.Kind, function.NativeFunction().Name)
+	}
+
+	return getNativeFunction(function.NativeFunction().Name)
+}
+
+// Property of native type value. Methods are bound to the value
+func getNativeProperty(value *runtime_heap.VariableValue, name string) *runtime_heap.VariableValue {
+	if getNativeMethod(value.Kind, name) != nil {
+		// Copy, so later assignment to variable doesn't change receiver
+		var receiver = *value
+
+		return runtime_heap.CreateNativeFunction(name, &receiver, nil)
+	}
+
+	if value.Kind == runtime_heap.TYPE_GENERATOR && name == "done" {
+		return runtime_heap.CreateBoolean(value.Generator().IsDone())
+	}
+
+	if value.Kind == runtime_heap.TYPE_STRING && name == "length" {
+		return runtime_heap.CreateNumber(float64(utf8.RuneCountInString(value.StringValue)))
+	}
+
+	if (value.Kind == runtime_heap.TYPE_MAP || value.Kind == runtime_heap.TYPE_SET) && name == "size" {
+		return runtime_heap.CreateNumber(float64(value.Collection().Size()))
+	}
+
+	if value.Kind == runtime_heap.TYPE_ENTRY && name == "key" {
+		return value.Entry().Key
+	}
+
+	if value.Kind == runtime_heap.TYPE_ENTRY && name == "value" {
+		return value.Entry().Value
+	}
+
+	if value.Kind == runtime_heap.TYPE_ENUM {
+		var member = value.Enum().GetMember(name)
+
+		if member != nil {
+			return runtime_heap.CreateEnumMember(member)
+		}
+	}
+
+	if value.Kind == runtime_heap.TYPE_ENUM_MEMBER && name == "name" {
+		return runtime_heap.CreateString(value.EnumMember().Name)
+	}
+
+	if value.Kind == runtime_heap.TYPE_ENUM_MEMBER && name == "value" {
+		return value.EnumMember().Value
+	}
+
+	return nil
+}
+
+// Value by index, like character of string
+func getNativeIndex(value *runtime_heap.VariableValue, index *runtime_heap.VariableValue, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if value.Kind == runtime_heap.TYPE_STRING {
+		var position, positionErr = getIntegerArgument([]*runtime_heap.VariableValue{index}, 0, "index", node)
+
+		if positionErr != nil {
+			return nil, positionErr
+		}
+
+		return getCharacter(value.StringValue, position), nil
+	}
+
+	if value.Kind == runtime_heap.TYPE_MAP {
+		return nativeCollectionGet(nil, node, []*runtime_heap.VariableValue{value, index})
+	}
+
+	// Reverse lookup, member by its value
+	if value.Kind == runtime_heap.TYPE_ENUM {
+		var member = value.Enum().FindMember(index)
+
+		if member == nil {
+			return runtime_heap.CreateUnknown(), nil
+		}
+
+		return runtime_heap.CreateEnumMember(member), nil
+	}
+
+	return nil, runtime_error.CreateError(
+		"Cannot read index of "+runtime_heap.GetTypeName(value),
+		node,
+	)
+}
+
+func (runtime *Runtime) defineEnvByBridge() error {
+	for _, native := range nativeFunctions {
+		// Build in functions take slots, which resolver gives to their names
+		var slot = ast_node.VariableSlot{Index: ast_resolver.GetBuildInIndex(native.name)}
+
+		if slot.Index < 0 {
+			return runtime_error.RuntimeError{
+				Message: "Cannot find slot of " + native.name + " env variable",
+			}
+		}
+
+		var defineVariableErr = runtime.heap.CreateVariable(slot.Index)
+
+		if defineVariableErr != nil {
+			return runtime_error.MergeRuntimeErrors(runtime_error.RuntimeError{
+				Message: "Cannot create " + native.name + " env variable",
+			}, defineVariableErr)
+		}
+
+		var defineNativeErr = runtime.heap.SetVariable(slot, runtime_heap.CreateNativeFunction(native.name, nil, nil))
+
+		if defineNativeErr != nil {
+			return runtime_error.MergeRuntimeErrors(runtime_error.RuntimeError{
+				Message: "Cannot define " + native.name + " env variable",
+			}, defineNativeErr)
+		}
+	}
+
+	return nil
+}
+
+func (runtime *Runtime) callNativeFunction(function *runtime_heap.VariableValue, node *ast_node.ASTNode, argumentsValues []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var name = function.NativeFunction().Name
+	var native = getNativeOf(function)
+
+	if native == nil {
+		return nil, runtime_error.CreateError(
+			"Unknown native function: "+name,
+			node,
+		)
+	}
+
+	if native.arity >= 0 && len(argumentsValues) != native.arity {
+		return nil, runtime_error.CreateError(
+			fmt.Sprintf("Function %s expects %d arguments. But received: %d", name, native.arity, len(argumentsValues)),
+			node,
+		)
+	}
+
+	if function.NativeFunction().Receiver != nil {
+		argumentsValues = append([]*runtime_heap.VariableValue{function.NativeFunction().Receiver}, argumentsValues...)
+	}
+
+	var result, callErr = native.call(runtime, node, argumentsValues)
+
+	if callErr != nil {
+		return nil, runtime.call.enter(function, node).attachStack(callErr)
+	}
+
+	return result, runtime.program.limits.checkValue(result, node)
+}
+
+func nativePrint(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	runtime.bridge.Print(arguments...)
+
+	return nil, nil
+}
+
+func createTypeCheck(kinds ...runtime_heap.ValueKind) nativeFunctionCall {
+	return func(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+		for _, kind := range kinds {
+			if arguments[0].Kind == kind {
+				return runtime_heap.CreateBoolean(true), nil
+			}
+		}
+
+		return runtime_heap.CreateBoolean(false), nil
+	}
+}
+
+func createCast(cast func(variable *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error)) nativeFunctionCall {
+	return func(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+		var value, castErr = cast(arguments[0])
+
+		if castErr != nil {
+			return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+				"Cannot convert value. Received: "+runtime_heap.GetTypeName(arguments[0]),
+				node,
+			), castErr)
+		}
+
+		return value, nil
+	}
+}
+
+func nativeFunctionName(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var function = arguments[0]
+
+	if function.Kind == runtime_heap.TYPE_NATIVE_FUNCTION {
+		return runtime_heap.CreateString(function.NativeFunction().Name), nil
+	}
+
+	if function.Kind != runtime_heap.TYPE_FUNCTION {
+		return nil, runtime_error.CreateError(
+			"Cannot get name, value is not a function. Received: "+runtime_heap.GetTypeName(function),
+			node,
+		)
+	}
+
+	var functionName = ast_node.GetFunctionNameParam(function.Function().Node)
+
+	if functionName == nil {
+		return nil, runtime_error.CreateError(
+			"Function name not defined",
+			node,
+		)
+	}
+
+	return runtime_heap.CreateString(functionName.Value), nil
+}
+
+func nativeFunctionArity(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var function = arguments[0]
+
+	if function.Kind == runtime_heap.TYPE_NATIVE_FUNCTION {
+		var native = getNativeOf(function)
+
+		if native == nil {
+			return nil, runtime_error.CreateError(
+				"Unknown native function: "+function.NativeFunction().Name,
+				node,
+			)
+		}
+
+		return runtime_heap.CreateNumber(float64(native.arity)), nil
+	}
+
+	if function.Kind != runtime_heap.TYPE_FUNCTION {
+		return nil, runtime_error.CreateError(
+			"Cannot get arity, value is not a function. Received: "+runtime_heap.GetTypeName(function),
+			node,
+		)
+	}
+
+	var arity = 0
+
+	for _, param := range function.Function().Node.Params {
+		if param.Name == ast_node.AST_PARAM_FUNCTION_ARGUMENT_NAME {
+			arity++
+		}
+	}
+
+	return runtime_heap.CreateNumber(float64(arity)), nil
+}
+
+func nativeGeneratorNext(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var gen = arguments[0].Generator()
+	var resumeValue *runtime_heap.VariableValue
+
+	if len(arguments) > 2 {
+		return nil, runtime_error.CreateError(
+			fmt.Sprintf("Function next expects 0 or 1 arguments. But received: %d", len(arguments)-1),
+			node,
+		)
+	}
+
+	if len(arguments) == 2 {
+		resumeValue = arguments[1]
+	}
+
+	var value, err = gen.Next(resumeValue)
+
+	if err != nil {
+		return nil, runtime_error.MergeRuntimeErrors(runtime_error.CreateError(
+			"Generator failed",
+			node,
+		), err)
+	}
+
+	return value, nil
+}
+
+func createTimerSetter(isRepeated bool) nativeFunctionCall {
+	return func(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+		var callback = arguments[0]
+
+		if callback.Kind != runtime_heap.TYPE_FUNCTION && callback.Kind != runtime_heap.TYPE_NATIVE_FUNCTION {
+			return nil, runtime_error.CreateError(
+				"Timer callback should be a function. Received: "+runtime_heap.GetTypeName(callback),
+				node,
+			)
+		}
+
+		var delay, delayErr = getDelayArgument(arguments[1], node)
+
+		if delayErr != nil {
+			return nil, delayErr
+		}
+
+		var interval float64
+
+		if isRepeated {
+			// Interval without delay would never let time go forward
+			interval = delay
+
+			if interval < 1 {
+				interval = 1
+			}
+		}
+
+		var id = runtime.program.loop.addTimer(delay, interval, func() error {
+			var _, callbackErr = runtime.callFunction(callback, []*runtime_heap.VariableValue{}, node)
+
+			return callbackErr
+		})
+
+		return runtime_heap.CreateNumber(float64(id)), nil
+	}
+}
+
+func nativeClearTimer(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	if arguments[0].Kind != runtime_heap.TYPE_NUMBER {
+		return nil, runtime_error.CreateError(
+			"Timer id should be a number. Received: "+runtime_heap.GetTypeName(arguments[0]),
+			node,
+		)
+	}
+
+	runtime.program.loop.removeTimer(int(arguments[0].NumberValue))
+
+	return nil, nil
+}
+
+func nativeSleep(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var delay, delayErr = getDelayArgument(arguments[0], node)
+
+	if delayErr != nil {
+		return nil, delayErr
+	}
+
+	var sleepPromise = createPromise()
+	var loop = runtime.program.loop
+
+	loop.addTimer(delay, 0, func() error {
+		loop.settle(sleepPromise, runtime_heap.CreateUnknown(), nil)
+
+		return nil
+	})
+
+	return createPromiseValue(sleepPromise), nil
+}
+
+func nativeNow(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return runtime_heap.CreateNumber(runtime.program.loop.clock.Now()), nil
+}
+
+func getDelayArgument(delay *runtime_heap.VariableValue, node *ast_node.ASTNode) (float64, error) {
+	if delay.Kind != runtime_heap.TYPE_NUMBER {
+		return 0, runtime_error.CreateError(
+			"Delay should be a number of milliseconds. Received: "+runtime_heap.GetTypeName(delay),
+			node,
+		)
+	}
+
+	return delay.NumberValue, nil
+}
+
+func nativeChan(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	if len(arguments) > 1 {
+		return nil, runtime_error.CreateError(
+			fmt.Sprintf("Function chan expects 0 or 1 arguments. But received: %d", len(arguments)),
+			node,
+		)
+	}
+
+	var capacity = 0
+
+	if len(arguments) == 1 {
+		if arguments[0].Kind != runtime_heap.TYPE_NUMBER || arguments[0].NumberValue < 0 {
+			return nil, runtime_error.CreateError(
+				"Channel capacity should be a positive number. Received: "+runtime_heap.GetTypeName(arguments[0]),
+				node,
+			)
+		}
+
+		capacity = int(arguments[0].NumberValue)
+	}
+
+	return createChannel(runtime.program.loop, capacity), nil
+}
+
+func getChannelArgument(value *runtime_heap.VariableValue, node *ast_node.ASTNode) (*channel, error) {
+	if value.Kind != runtime_heap.TYPE_CHANNEL {
+		return nil, runtime_error.CreateError(
+			"Expected channel. Received: "+runtime_heap.GetTypeName(value),
+			node,
+		)
+	}
+
+	return value.Channel().(*channel), nil
+}
+
+func nativeSend(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var ch, chErr = getChannelArgument(arguments[0], node)
+
+	if chErr != nil {
+		return nil, chErr
+	}
+
+	return nil, runtime.send(ch, arguments[1], node)
+}
+
+func nativeReceive(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var ch, chErr = getChannelArgument(arguments[0], node)
+
+	if chErr != nil {
+		return nil, chErr
+	}
+
+	var value, _, err = runtime.receive(ch, node)
+
+	return value, err
+}
+
+func nativeClose(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var ch, chErr = getChannelArgument(arguments[0], node)
+
+	if chErr != nil {
+		return nil, chErr
+	}
+
+	var closeErr = ch.close()
+
+	if closeErr != nil {
+		return nil, runtime_error.CreateError(closeErr.Error(), node)
+	}
+
+	return nil, nil
+}
+
+// Arguments are pairs of channel and handler of received value,
+// optionally followed by default handler, which runs when no channel is ready
+func nativeSelect(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var cases = []*channel{}
+	var handlers = []*runtime_heap.VariableValue{}
+	var defaultHandler *runtime_heap.VariableValue
+
+	for index := 0; index < len(arguments); index += 2 {
+		if index == len(arguments)-1 {
+			defaultHandler = arguments[index]
+			break
+		}
+
+		var ch, chErr = getChannelArgument(arguments[index], node)
+
+		if chErr != nil {
+			return nil, chErr
+		}
+
+		cases = append(cases, ch)
+		handlers = append(handlers, arguments[index+1])
+	}
+
+	if len(cases) == 0 {
+		return nil, runtime_error.CreateError(
+			"Select should have at least one channel",
+			node,
+		)
+	}
+
+	var index, value, selectErr = runtime.selectChannel(cases, node, defaultHandler != nil)
+
+	if selectErr != nil {
+		return nil, selectErr
+	}
+
+	if index < 0 {
+		return runtime.callFunction(defaultHandler, []*runtime_heap.VariableValue{}, node)
+	}
+
+	return runtime.callFunction(handlers[index], []*runtime_heap.VariableValue{value}, node)
+}
@@ -0,0 +1,52 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Call of function, linked to calls of callers. Runtime of function body keeps its call,
+// so call leaves stack together with runtime, when body ends
+type callSite struct {
+	function *runtime_heap.VariableValue
+	node     *ast_node.ASTNode
+	caller   *callSite
+	depth    int
+}
+
+// Call of function from current call, nil current call is top level code
+func (call *callSite) enter(function *runtime_heap.VariableValue, node *ast_node.ASTNode) *callSite {
+	var depth = 1
+
+	if call != nil {
+		depth = call.depth + 1
+	}
+
+	return &callSite{
+		function: function,
+		node:     node,
+		caller:   call,
+		depth:    depth,
+	}
+}
+
+// Adds stack of calls to runtime error, which doesn't have stack yet.
+// So error keeps stack of the deepest call, which it passed
+func (call *callSite) attachStack(err error) error {
+	var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+	if call == nil || !isRuntimeErr || runtimeErr.Stack != nil {
+		return err
+	}
+
+	for site := call; site != nil; site = site.caller {
+		runtimeErr.Stack = append(runtimeErr.Stack, runtime_error.StackFrame{
+			Name:          getFunctionName(site.function),
+			StartPosition: site.node.StartPosition,
+			EndPosition:   site.node.EndPosition,
+		})
+	}
+
+	return runtimeErr
+}
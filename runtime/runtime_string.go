@@ -0,0 +1,295 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Methods of strings count positions in runes, not in bytes
+
+func getStringArgument(arguments []*runtime_heap.VariableValue, index int, name string, node *ast_node.ASTNode) (string, error) {
+	if arguments[index].Kind != runtime_heap.TYPE_STRING {
+		return "", runtime_error.CreateError(
+			fmt.Sprintf("Argument %d of %s should be a string. Received: %s", index, name, runtime_heap.GetTypeName(arguments[index])),
+			node,
+		)
+	}
+
+	return arguments[index].StringValue, nil
+}
+
+// Integer number or integer value, like index or count
+func getIntegerArgument(arguments []*runtime_heap.VariableValue, index int, name string, node *ast_node.ASTNode) (int, error) {
+	var argument = arguments[index]
+
+	if argument.Kind == runtime_heap.TYPE_INTEGER && argument.IntegerValue.IsInt64() {
+		return int(argument.IntegerValue.Int64()), nil
+	}
+
+	if argument.Kind == runtime_heap.TYPE_NUMBER && argument.NumberValue == float64(int(argument.NumberValue)) {
+		return int(argument.NumberValue), nil
+	}
+
+	return 0, runtime_error.CreateError(
+		fmt.Sprintf("Argument %d of %s should be an integer number. Received: %s", index, name, runtime_heap.GetTypeName(argument)),
+		node,
+	)
+}
+
+// Position from end for negative values, like in "slice(-2)". Result is inside of [0, length]
+func clampPosition(position int, length int) int {
+	if position < 0 {
+		position = length + position
+	}
+
+	if position < 0 {
+		return 0
+	}
+
+	if position > length {
+		return length
+	}
+
+	return position
+}
+
+// Character by rune index. Unknown for index out of string
+func getCharacter(text string, index int) *runtime_heap.VariableValue {
+	var runes = []rune(text)
+
+	if index < 0 || index >= len(runes) {
+		return runtime_heap.CreateUnknown()
+	}
+
+	return runtime_heap.CreateString(string(runes[index]))
+}
+
+func nativeStringToUpper(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return runtime_heap.CreateString(strings.ToUpper(arguments[0].StringValue)), nil
+}
+
+func nativeStringToLower(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return runtime_heap.CreateString(strings.ToLower(arguments[0].StringValue)), nil
+}
+
+func nativeStringTrim(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return runtime_heap.CreateString(strings.TrimSpace(arguments[0].StringValue)), nil
+}
+
+// Parts are returned by generator, empty separator splits string into characters
+func nativeStringSplit(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var separator, separatorErr = getStringArgument(arguments, 1, "split", node)
+
+	if separatorErr != nil {
+		return nil, separatorErr
+	}
+
+	var parts = []*runtime_heap.VariableValue{}
+
+	for _, part := range strings.Split(arguments[0].StringValue, separator) {
+		parts = append(parts, runtime_heap.CreateString(part))
+	}
+
+	return createSequence(parts), nil
+}
+
+func nativeStringSlice(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	if len(arguments) < 2 || len(arguments) > 3 {
+		return nil, runtime_error.CreateError(
+			fmt.Sprintf("Function slice expects 1 or 2 arguments. But received: %d", len(arguments)-1),
+			node,
+		)
+	}
+
+	var runes = []rune(arguments[0].StringValue)
+	var start, startErr = getIntegerArgument(arguments, 1, "slice", node)
+
+	if startErr != nil {
+		return nil, startErr
+	}
+
+	var end = len(runes)
+
+	if len(arguments) == 3 {
+		var endArgument, endErr = getIntegerArgument(arguments, 2, "slice", node)
+
+		if endErr != nil {
+			return nil, endErr
+		}
+
+		end = endArgument
+	}
+
+	start = clampPosition(start, len(runes))
+	end = clampPosition(end, len(runes))
+
+	if start >= end {
+		return runtime_heap.CreateString(""), nil
+	}
+
+	return runtime_heap.CreateString(string(runes[start:end])), nil
+}
+
+// Rune index of first occurrence, -1 when string doesn't contain value
+func nativeStringIndexOf(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var search, searchErr = getStringArgument(arguments, 1, "indexOf", node)
+
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	var text = arguments[0].StringValue
+	var byteIndex = strings.Index(text, search)
+
+	if byteIndex < 0 {
+		return runtime_heap.CreateNumber(-1), nil
+	}
+
+	return runtime_heap.CreateNumber(float64(utf8.RuneCountInString(text[:byteIndex]))), nil
+}
+
+func nativeStringIncludes(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var search, searchErr = getStringArgument(arguments, 1, "includes", node)
+
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	return runtime_heap.CreateBoolean(strings.Contains(arguments[0].StringValue, search)), nil
+}
+
+func nativeStringStartsWith(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var prefix, prefixErr = getStringArgument(arguments, 1, "startsWith", node)
+
+	if prefixErr != nil {
+		return nil, prefixErr
+	}
+
+	return runtime_heap.CreateBoolean(strings.HasPrefix(arguments[0].StringValue, prefix)), nil
+}
+
+func nativeStringEndsWith(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var suffix, suffixErr = getStringArgument(arguments, 1, "endsWith", node)
+
+	if suffixErr != nil {
+		return nil, suffixErr
+	}
+
+	return runtime_heap.CreateBoolean(strings.HasSuffix(arguments[0].StringValue, suffix)), nil
+}
+
+func createStringReplace(count int) nativeFunctionCall {
+	return func(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+		var search, searchErr = getStringArgument(arguments, 1, "replace", node)
+
+		if searchErr != nil {
+			return nil, searchErr
+		}
+
+		var replacement, replacementErr = getStringArgument(arguments, 2, "replace", node)
+
+		if replacementErr != nil {
+			return nil, replacementErr
+		}
+
+		return runtime_heap.CreateString(strings.Replace(arguments[0].StringValue, search, replacement, count)), nil
+	}
+}
+
+func nativeStringRepeat(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var count, countErr = getIntegerArgument(arguments, 1, "repeat", node)
+
+	if countErr != nil {
+		return nil, countErr
+	}
+
+	if count < 0 {
+		return nil, runtime_error.CreateError(
+			"Repeat count should not be negative",
+			node,
+		)
+	}
+
+	// Huge string is not built, when it doesn't fit memory limit
+	var memoryErr = runtime.program.limits.checkMemory(len(arguments[0].StringValue)*count, node)
+
+	if memoryErr != nil {
+		return nil, memoryErr
+	}
+
+	return runtime_heap.CreateString(strings.Repeat(arguments[0].StringValue, count)), nil
+}
+
+// Character by rune index, negative index counts from end
+func nativeStringAt(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var index, indexErr = getIntegerArgument(arguments, 1, "at", node)
+
+	if indexErr != nil {
+		return nil, indexErr
+	}
+
+	if index < 0 {
+		index = utf8.RuneCountInString(arguments[0].StringValue) + index
+	}
+
+	return getCharacter(arguments[0].StringValue, index), nil
+}
+
+func nativeNumberToFixed(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	var digits, digitsErr = getIntegerArgument(arguments, 1, "toFixed", node)
+
+	if digitsErr != nil {
+		return nil, digitsErr
+	}
+
+	if digits < 0 || digits > 100 {
+		return nil, runtime_error.CreateError(
+			"Digits of toFixed should be between 0 and 100",
+			node,
+		)
+	}
+
+	return runtime_heap.CreateString(strconv.FormatFloat(arguments[0].NumberValue, 'f', digits, 64)), nil
+}
+
+func nativeToString(runtime *Runtime, node *ast_node.ASTNode, arguments []*runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	return runtime_heap.CastToString(arguments[0])
+}
+
+// Generator over already known values, like parts of split string
+type sequence struct {
+	items  []*runtime_heap.VariableValue
+	index  int
+	isDone bool
+}
+
+func createSequence(items []*runtime_heap.VariableValue) *runtime_heap.VariableValue {
+	return runtime_heap.CreateReference(runtime_heap.TYPE_GENERATOR, &sequence{items: items})
+}
+
+func (seq *sequence) Next(value *runtime_heap.VariableValue) (*runtime_heap.VariableValue, error) {
+	if seq.index >= len(seq.items) {
+		seq.isDone = true
+
+		return runtime_heap.CreateUnknown(), nil
+	}
+
+	var item = seq.items[seq.index]
+	seq.index++
+
+	return item, nil
+}
+
+func (seq *sequence) IsDone() bool {
+	return seq.isDone
+}
+
+func (seq *sequence) Close() {
+	seq.isDone = true
+}
@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Coroutine driven by event loop: main program, async function call or spawned task.
+// Task suspends, when it waits for promise, and is continued, when promise is settled
+type task struct {
+	name      string
+	coroutine *coroutine
+	promise   *promise
+	// Operation, which task waits for, empty while task runs
+	blockedOn   string
+	blockedNode *ast_node.ASTNode
+}
+
+func (program *program) createTask(name string, call coroutineCall) *task {
+	var newTask = &task{
+		name:      name,
+		coroutine: program.createCoroutine(call),
+		promise:   createPromise(),
+	}
+
+	// Finished tasks can't be blocked anymore
+	var activeTasks = program.tasks[:0]
+
+	for _, activeTask := range program.tasks {
+		if !activeTask.coroutine.isDone {
+			activeTasks = append(activeTasks, activeTask)
+		}
+	}
+
+	program.tasks = append(activeTasks, newTask)
+
+	return newTask
+}
+
+func (program *program) continueTask(current *task, value *runtime_heap.VariableValue, err error) {
+	var previousTask = program.currentTask
+	program.currentTask = current
+	var step = current.coroutine.resume(value, err)
+	program.currentTask = previousTask
+
+	if step.isDone || step.err != nil {
+		program.loop.settle(current.promise, step.value, step.err)
+		return
+	}
+
+	// Task is suspended, step value is promise, which task waits for
+	program.loop.then(step.value.Promise().(*promise), func(value *runtime_heap.VariableValue, err error) error {
+		program.continueTask(current, value, err)
+
+		return nil
+	})
+}
+
+// Suspends current task until promise is settled. Can be called from any depth
+// of calls inside the task, even from generator, which is resumed by the task
+func (program *program) block(waited *promise, operation string, node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var current = program.currentTask
+
+	if current == nil {
+		return nil, runtime_error.CreateError(
+			"Cannot "+operation+" outside of task",
+			node,
+		)
+	}
+
+	current.blockedOn = operation
+	current.blockedNode = node
+	var result, err = current.coroutine.suspend(createPromiseValue(waited))
+	current.blockedOn = ""
+	current.blockedNode = nil
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return runtime_heap.CreateUnknown(), nil
+	}
+
+	return result, nil
+}
+
+// Error, which lists blocked tasks, when main task can never be continued
+func (program *program) getDeadlockError(main *task) error {
+	var blockedTasks = []string{}
+
+	for _, blockedTask := range program.tasks {
+		if !blockedTask.coroutine.isDone && blockedTask.blockedOn != "" {
+			blockedTasks = append(blockedTasks, blockedTask.name+" ("+blockedTask.blockedOn+")")
+		}
+	}
+
+	return runtime_error.CreateError(
+		"Deadlock, all tasks are blocked: "+strings.Join(blockedTasks, ", "),
+		main.blockedNode,
+	)
+}
+
+// Calls function in new task. Task starts, when event loop reaches it
+func (runtime *Runtime) visitSpawnNode(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	var callNode = node.Body[0]
+	var functionVariable, functionErr = runtime.visitNode(callNode.Body[0])
+
+	if functionErr != nil {
+		return nil, functionErr
+	}
+
+	// Arguments are evaluated by spawning task, like in go statement
+	var argumentsValues, argumentsErr = runtime.getArgumentsValues(callNode)
+
+	if argumentsErr != nil {
+		return nil, argumentsErr
+	}
+
+	argumentsValues, argumentsErr = runtime.bindArguments(functionVariable, callNode, argumentsValues)
+
+	if argumentsErr != nil {
+		return nil, argumentsErr
+	}
+
+	var spawned = runtime.program.createTask(getFunctionName(functionVariable), func() (*runtime_heap.VariableValue, error) {
+		return runtime.callFunction(functionVariable, argumentsValues, callNode)
+	})
+
+	runtime.program.loop.enqueue(func() error {
+		runtime.program.continueTask(spawned, nil, nil)
+
+		return nil
+	})
+
+	return createPromiseValue(spawned.promise), nil
+}
+
+func getFunctionName(function *runtime_heap.VariableValue) string {
+	if function.Kind == runtime_heap.TYPE_NATIVE_FUNCTION {
+		return function.NativeFunction().Name
+	}
+
+	var nameParam = ast_node.GetFunctionNameParam(function.Function().Node)
+
+	if nameParam == nil {
+		return "anonymous"
+	}
+
+	return nameParam.Value
+}
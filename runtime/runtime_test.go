@@ -1,10 +1,19 @@
 package runtime
 
 import (
+	"context"
+	"fmt"
+	goruntime "runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/VadimZvf/golang/parser"
+	"github.com/VadimZvf/golang/parser_error"
 	"github.com/VadimZvf/golang/runtime_bridge_mock"
+	"github.com/VadimZvf/golang/runtime_clock"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
 	"github.com/VadimZvf/golang/source_mock"
 	"github.com/VadimZvf/golang/stdout_mock"
 )
@@ -137,6 +146,82 @@ func TestFunctionClosure(t *testing.T) {
 	}
 }
 
+func TestArgumentShadowsBuiltInFunction(t *testing.T) {
+	var bridge, err = runCode(`
+	function later(now) {
+		return now + 1
+	}
+
+	print(later(3))
+	print(isFunction(now))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "4 true" {
+		t.Errorf("Code should print \"4 true\", but received: \"%s\"", strings.Join(bridge.GetLog(), " "))
+	}
+}
+
+func TestClosureUsesVariableDeclaredLater(t *testing.T) {
+	var bridge, err = runCode(`
+	function counter() {
+		return function next() {
+			count = count + 1
+			return count
+		}
+	}
+
+	var count = 10
+	var step = counter()
+	step()
+	print(step())
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "12" {
+		t.Errorf("Code should print message \"12\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestReadVariableBeforeDeclaration(t *testing.T) {
+	var _, err = runCode(`
+	var name = "outer"
+
+	function read() {
+		print(name)
+		var name = "inner"
+	}
+
+	read()
+	`)
+
+	var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+	if !isRuntimeErr || !strings.Contains(runtimeErr.Message, "Cannot get variable reference") || runtimeErr.StartPosition != 49 {
+		t.Errorf("Should fail to read variable of function before its declaration, but received: %#v", err)
+	}
+}
+
+func TestUndeclaredVariable(t *testing.T) {
+	var _, err = runCode(`
+	function greet() {
+		print(greeting)
+	}
+	`)
+
+	var parserErr, isParserErr = err.(parser_error.ParserError)
+
+	if !isParserErr || parserErr.Message != "Variable not declared: greeting" || parserErr.StartPosition != 29 || parserErr.EndPosition != 36 {
+		t.Errorf("Should report undeclared variable before run, but received: %#v", err)
+	}
+}
+
 func TestCallbackFunction(t *testing.T) {
 	var bridge, err = runCode(`
 	function baz(cb) {
@@ -231,25 +316,2276 @@ func TestIIFE(t *testing.T) {
 	}
 }
 
-func runCode(code string) (*runtime_bridge_mock.Bridge, error) {
-	var src = source_mock.GetSourceMock(code)
-	var bridge = runtime_bridge_mock.CreateBridge()
-	var stdout = stdout_mock.CreateStdout()
+func TestOperatorsPrecedence(t *testing.T) {
+	var bridge, err = runCode(`
+	print(1 + 2 * 3 - 4 / 2)
+	`)
 
-	var parser = parser.CreateParser(src, &stdout)
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
 
-	var astRoot, astError = parser.Parse(false)
+	if bridge.GetLastPring() != "5" {
+		t.Errorf("Code should print message \"5\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
 
-	if astError != nil {
-		return &bridge, astError
+func TestComparison(t *testing.T) {
+	var bridge, err = runCode(`
+	var a = 5
+	print(a + 1 > 5)
+	print(a == "5")
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
 	}
 
-	var rt = CreateRuntime(&bridge)
-	var runtimeErr = rt.Run(astRoot)
+	if bridge.GetLastPring() != "false" {
+		t.Errorf("Code should print message \"false\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
 
-	if runtimeErr != nil {
-		return &bridge, runtimeErr
+func TestMatchExpression(t *testing.T) {
+	var bridge, err = runCode(`
+	function describe(value) {
+		return match (value) {
+			1 => "one",
+			"a" | "b" => "letter",
+			x if x > 10 => "big " + x,
+			_ => "other"
+		}
 	}
 
-	return &bridge, nil
+	print(describe(1) + " " + describe("b") + " " + describe(42) + " " + describe(5))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "one letter big 42 other" {
+		t.Errorf("Code should print message \"one letter big 42 other\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestNonExhaustiveMatchError(t *testing.T) {
+	var _, err = runCode(`
+	var a = match (3) {
+		1 => "one",
+		2 => "two"
+	}
+	`)
+
+	if err == nil {
+		t.Errorf("Should return a error")
+		return
+	}
+
+	re, ok := err.(runtime_error.RuntimeError)
+
+	if !ok {
+		t.Errorf("Should return runtime error")
+		return
+	}
+
+	if !strings.HasPrefix(re.Message, "Non-exhaustive match. No arm matched value: 3. Add \"_\" arm to handle other values") {
+		t.Errorf("Should return non-exhaustive match error, but received: \"%s\"", re.Message)
+	}
+
+	if re.StartPosition != 10 || re.EndPosition != 50 {
+		t.Errorf("Should point to match expression. Received start: %d, end: %d", re.StartPosition, re.EndPosition)
+	}
+}
+
+func TestMatchArmScopeAndReadErrors(t *testing.T) {
+	var bridge, err = runCode(`
+	function pick(value) {
+		match (value) {
+			x if x > 1 => { return "big " + x },
+			y => print("small " + y)
+		}
+		return "done"
+	}
+
+	print(pick(5))
+	print(pick(0))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "big 5 small 0 done" {
+		t.Errorf("Should return from arm and bind value in arm scope, but received: %v", bridge.GetLog())
+	}
+
+	var _, indexErr = runCode(`
+	var number = 5
+	print(number[number.size])
+	`)
+
+	if indexErr == nil || !strings.HasPrefix(indexErr.Error(), "Cannot read property size of number\n  Cannot get index value") {
+		t.Errorf("Should fail on reading property of number, but received: %v", indexErr)
+	}
+}
+
+func TestTypeof(t *testing.T) {
+	var bridge, err = runCode(`
+	function foo() {}
+	var a
+
+	print(typeof 1 + " " + typeof "a" + " " + typeof true + " " + typeof foo + " " + typeof print + " " + typeof a)
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "number string boolean function native_function unknown" {
+		t.Errorf("Code should print type names, but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestTypeofPrecedence(t *testing.T) {
+	var bridge, err = runCode(`
+	print(typeof 1 == "number")
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "true" {
+		t.Errorf("Code should print message \"true\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestTypeCheckFunctions(t *testing.T) {
+	var bridge, err = runCode(`
+	function foo() {}
+
+	print(isNumber(1) + " " + isNumber("1") + " " + isFunction(foo) + " " + isFunction(print) + " " + isNative(foo) + " " + isNative(print))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "true false true true false true" {
+		t.Errorf("Code should print type checks, but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestFunctionIntrospection(t *testing.T) {
+	var bridge, err = runCode(`
+	function summ(first, second) {
+		return first + second
+	}
+
+	print(fnName(summ) + "/" + fnArity(summ) + " " + fnName(print))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "summ/2 print" {
+		t.Errorf("Code should print message \"summ/2 print\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestNativeFunctionArgumentsCount(t *testing.T) {
+	var _, err = runCode(`
+	isNumber(1, 2)
+	`)
+
+	if err == nil {
+		t.Errorf("Should return a error")
+		return
+	}
+
+	if err.Error() != "Function isNumber expects 1 arguments. But received: 2" {
+		t.Errorf("Should return arguments count error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestNumberFormats(t *testing.T) {
+	var bridge, err = runCode(`
+	print(0xFF + 0b1010 + 0o17 + 1_000 + 2.5e1 + .5)
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "1305.5" {
+		t.Errorf("Code should print message \"1305.5\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestUnicodeText(t *testing.T) {
+	var bridge, err = runCode(`
+	var приветствие = "Привет"
+	print(приветствие + ", мир!")
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "Привет, мир!" {
+		t.Errorf("Code should print message \"Привет, мир!\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestUnicodeErrorPosition(t *testing.T) {
+	var _, err = runCode(`var имя = "Мир"; неизвестно()`)
+
+	re, ok := err.(parser_error.ParserError)
+
+	if !ok {
+		t.Errorf("Should return parser error")
+		return
+	}
+
+	if re.StartPosition != 17 || re.EndPosition != 26 {
+		t.Errorf("Should count error position in symbols. Received start: %d, end: %d", re.StartPosition, re.EndPosition)
+	}
+}
+
+func TestReturnFromNestedBlock(t *testing.T) {
+	var bridge, err = runCode(`
+	function foo(value) {
+		{
+			{
+				return value + 1
+			}
+			print("Unreachable")
+		}
+		print("Unreachable")
+	}
+
+	print(foo(1))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "2" {
+		t.Errorf("Code should print message \"2\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestEmptyReturn(t *testing.T) {
+	var bridge, err = runCode(`
+	function foo() {
+		return;
+		print("Unreachable")
+	}
+
+	function bar() {
+		return
+	}
+
+	print(typeof foo() + " " + typeof bar())
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "unknown unknown" {
+		t.Errorf("Code should print message \"unknown unknown\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestFunctionHoisting(t *testing.T) {
+	var bridge, err = runCode(`
+	print(welcome("Tleng"))
+
+	function welcome(name) {
+		return prefix() + name
+		function prefix() {
+			return "Hi "
+		}
+	}
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "Hi Tleng" {
+		t.Errorf("Code should print message \"Hi Tleng\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestMutualRecursion(t *testing.T) {
+	var bridge, err = runCode(`
+	function isEven(value) {
+		return match (value) {
+			0 => true,
+			_ => isOdd(value - 1)
+		}
+	}
+
+	print(isEven(10) + " " + isOdd(7))
+
+	function isOdd(value) {
+		return match (value) {
+			0 => false,
+			_ => isEven(value - 1)
+		}
+	}
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "true true" {
+		t.Errorf("Code should print message \"true true\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestWhileLoop(t *testing.T) {
+	var bridge, err = runCode(`
+	var index = 0
+	var result = ""
+
+	while (index < 5) {
+		var label = "#" + index
+		index = index + 1
+		result = result + label
+	}
+
+	print(result)
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "#0#1#2#3#4" {
+		t.Errorf("Code should print message \"#0#1#2#3#4\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestBreakAndContinue(t *testing.T) {
+	var bridge, err = runCode(`
+	var index = 0
+
+	while (index < 3) {
+		index = index + 1
+		continue
+		print("unreachable")
+	}
+
+	while (true) {
+		break
+		print("unreachable")
+	}
+
+	print(index)
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "3" {
+		t.Errorf("Code should print message \"3\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestBreakOutsideOfLoop(t *testing.T) {
+	var _, err = runCode(`
+	function test() {
+		break
+	}
+
+	test()
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.HasPrefix(err.Error(), "Cannot break outside of loop") {
+		t.Errorf("Should fail with break error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestGeneratorNext(t *testing.T) {
+	var bridge, err = runCode(`
+	function* range(n) {
+		var i = 0
+		while (i < n) {
+			yield i
+			i = i + 1
+		}
+		return "end"
+	}
+
+	var numbers = range(2)
+	var result = numbers.next() + " " + numbers.next() + " " + numbers.done
+	result = result + " " + numbers.next() + " " + numbers.done + " " + numbers.next()
+
+	print(result)
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "0 1 false end true " {
+		t.Errorf("Code should print message \"0 1 false end true \", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestGeneratorReceivesValue(t *testing.T) {
+	var bridge, err = runCode(`
+	function* echo() {
+		var value = yield "ready"
+		while (true) {
+			value = yield "got " + value
+		}
+	}
+
+	var generator = echo()
+	print(generator.next() + ", " + generator.next("a") + ", " + generator.next("b"))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "ready, got a, got b" {
+		t.Errorf("Code should print message \"ready, got a, got b\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestForOfGenerator(t *testing.T) {
+	var bridge, err = runCode(`
+	function* range(n) {
+		var i = 0
+		while (i < n) {
+			yield i
+			i = i + 1
+		}
+	}
+
+	var sum = 0
+
+	for (var item of range(5)) {
+		var double = item * 2
+		sum = sum + double
+	}
+
+	print(sum)
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "20" {
+		t.Errorf("Code should print message \"20\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestReturnFromInfiniteGeneratorLoop(t *testing.T) {
+	var bridge, err = runCode(`
+	function* naturals() {
+		var n = 1
+		while (true) {
+			yield n
+			n = n + 1
+		}
+	}
+
+	function firstOver(limit) {
+		for (n of naturals()) {
+			match (n > limit) { true => return n, _ => 0 }
+		}
+	}
+
+	print(firstOver(10))
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "11" {
+		t.Errorf("Code should print message \"11\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestYieldOutsideOfGenerator(t *testing.T) {
+	var _, err = runCode(`
+	function test() {
+		yield 1
+	}
+
+	test()
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.HasPrefix(err.Error(), "Cannot yield outside of generator function") {
+		t.Errorf("Should fail with yield error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestAbandonedGeneratorsAreClosed(t *testing.T) {
+	var goroutinesBefore = goruntime.NumGoroutine()
+
+	var _, err = runCode(`
+	function* naturals() {
+		var n = 1
+		while (true) {
+			yield n
+			n = n + 1
+		}
+	}
+
+	var index = 0
+
+	while (index < 20) {
+		var numbers = naturals()
+		numbers.next()
+		index = index + 1
+	}
+
+	for (n of naturals()) {
+		break
+	}
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	// Goroutines are closed before run returns, wait only for scheduler cleanup
+	var deadline = time.Now().Add(time.Second)
+
+	for goruntime.NumGoroutine() > goroutinesBefore && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if goruntime.NumGoroutine() > goroutinesBefore {
+		t.Errorf("Generators should not leak goroutines. Before: %d, after: %d", goroutinesBefore, goruntime.NumGoroutine())
+	}
+}
+
+// Collects garbage on "collect" and counts goroutines on "count"
+type goroutinesBridge struct {
+	counts []int
+}
+
+func (bridge *goroutinesBridge) Print(args ...*runtime_heap.VariableValue) {
+	switch args[0].StringValue {
+	case "collect":
+		goruntime.GC()
+		// Finalizers run in own goroutine after collection
+		time.Sleep(10 * time.Millisecond)
+	case "count":
+		bridge.counts = append(bridge.counts, goruntime.NumGoroutine())
+	}
+}
+
+func TestCollectedGeneratorsAreClosedWhileProgramRuns(t *testing.T) {
+	var src = source_mock.GetSourceMock(`
+	function* naturals() {
+		var n = 1
+		while (true) {
+			yield n
+			n = n + 1
+		}
+	}
+
+	function take() {
+		var numbers = naturals()
+		return numbers.next()
+	}
+
+	print("count")
+	var index = 0
+
+	while (index < 100) {
+		take()
+		index = index + 1
+	}
+
+	print("collect")
+	take()
+	print("count")
+	`)
+	var stdout = stdout_mock.CreateStdout()
+	var codeParser = parser.CreateParser(src, &stdout)
+	var astRoot, astError = codeParser.Parse(false)
+
+	if astError != nil {
+		t.Errorf("Code should be parsed, but failed with error: \"%s\"", astError.Error())
+		return
+	}
+
+	for _, isBytecode := range []bool{false, true} {
+		var bridge = goroutinesBridge{}
+		var rt = CreateRuntime(&bridge)
+		rt.SetBytecodeMode(isBytecode)
+		var err = rt.Run(astRoot)
+
+		if err != nil {
+			t.Errorf("Code failed with error: \"%s\"", err.Error())
+			return
+		}
+
+		// Generator of the last call can be still reachable
+		if len(bridge.counts) != 2 || bridge.counts[1] > bridge.counts[0]+10 {
+			t.Errorf("Collected generators should not keep goroutines, but received counts: %v", bridge.counts)
+		}
+	}
+}
+
+func TestAsyncFunctionAwait(t *testing.T) {
+	var bridge, err = runCode(`
+	async function load(name, delay) {
+		await sleep(delay)
+		print(name + " loaded at " + now())
+		return name
+	}
+
+	async function main() {
+		var first = load("first", 100)
+		var second = load("second", 50)
+		print("waiting")
+		var result = await first + await second
+		print(result + " at " + now())
+	}
+
+	main()
+	print("sync end")
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	var expectedLog = []string{"waiting", "sync end", "second loaded at 50", "first loaded at 100", "firstsecond at 100"}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expectedLog, "|") {
+		t.Errorf("Code should print \"%s\", but received: \"%s\"", strings.Join(expectedLog, "|"), strings.Join(bridge.GetLog(), "|"))
+	}
+}
+
+func TestAsyncFunctionReturnsPromise(t *testing.T) {
+	var bridge, err = runCode(`
+	async function getValue() {
+		return 1
+	}
+
+	async function main() {
+		var value = getValue()
+		print(typeof value + " " + await value + " " + await 2)
+	}
+
+	main()
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	if bridge.GetLastPring() != "promise 1 2" {
+		t.Errorf("Code should print message \"promise 1 2\", but received: \"%s\"", bridge.GetLastPring())
+	}
+}
+
+func TestTimersOrder(t *testing.T) {
+	var bridge, err = runCode(`
+	var count = 0
+	var id = setInterval(function tick() {
+		count = count + 1
+		print("tick " + count + " at " + now())
+		match (count) { 3 => clearInterval(id), _ => 0 }
+	}, 30)
+
+	setTimeout(function late() {
+		print("timeout at " + now())
+	}, 60)
+
+	var canceled = setTimeout(function never() {
+		print("never")
+	}, 10)
+	clearTimeout(canceled)
+	`)
+
+	if err != nil {
+		t.Errorf("Code failed with error: \"%s\"", err.Error())
+	}
+
+	var expectedLog = []string{"tick 1 at 30", "tick 2 at 60", "timeout at 60", "tick 3 at 90"}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expectedLog, "|") {
+		t.Errorf("Code should print \"%s\", but received: \"%s\"", strings.Join(expectedLog, "|"), strings.Join(bridge.GetLog(), "|"))
+	}
+}
+
+func TestAwaitOutsideOfAsyncFunction(t *testing.T) {
+	var _, err = runCode(`
+	function test() {
+		await sleep(1)
+	}
+
+	test()
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.HasPrefix(err.Error(), "Cannot await outside of async function") {
+		t.Errorf("Should fail with await error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestUnhandledRejection(t *testing.T) {
+	var _, err = runCode(`
+	async function fail() {
+		await sleep(10)
+		var notFunction = 1
+		notFunction()
+	}
+
+	fail()
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.Contains(err.Error(), "Unhandled promise rejection") {
+		t.Errorf("Should fail with unhandled rejection, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestRejectionPropagatesToAwait(t *testing.T) {
+	var _, err = runCode(`
+	async function fail() {
+		var notFunction = 1
+		notFunction()
+	}
+
+	async function main() {
+		await fail()
+		print("unreachable")
+	}
+
+	main()
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.HasPrefix(err.Error(), "Is not a function") {
+		t.Errorf("Should fail with error of awaited function, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestChannelPassesValuesBetweenTasks(t *testing.T) {
+	var bridge, err = runCode(`
+	function producer(ch, count) {
+		var index = 0
+
+		while (index < count) {
+			send(ch, index)
+			index = index + 1
+		}
+
+		close(ch)
+	}
+
+	var ch = chan()
+	spawn producer(ch, 3)
+
+	for (var item of ch) {
+		print("received", item)
+	}
+
+	print(receive(ch))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	// Receive from closed channel gives unknown
+	var expected = []string{"received", "0", "received", "1", "received", "2", "unknown"}
+
+	if strings.Join(bridge.GetLog(), " ") != strings.Join(expected, " ") {
+		t.Errorf("Should pass values in order, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestBufferedChannel(t *testing.T) {
+	var bridge, err = runCode(`
+	var ch = chan(2)
+	send(ch, "first")
+	send(ch, "second")
+	print(receive(ch))
+	print(receive(ch))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "first second" {
+		t.Errorf("Should buffer values, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestSelect(t *testing.T) {
+	var bridge, err = runCode(`
+	var numbers = chan()
+	var words = chan()
+
+	function sendWord(ch) {
+		send(ch, "hello")
+	}
+
+	spawn sendWord(words)
+
+	select(
+		numbers, function onNumber(value) { print("number", value) },
+		words, function onWord(value) { print("word", value) }
+	)
+
+	select(
+		numbers, function onNextNumber(value) { print("number", value) },
+		function onIdle() { print("idle") }
+	)
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "word hello idle" {
+		t.Errorf("Should select ready channel, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestSendToClosedChannel(t *testing.T) {
+	var _, err = runCode(`
+	var ch = chan(1)
+	close(ch)
+	send(ch, 1)
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if err.Error() != "Cannot send to closed channel" {
+		t.Errorf("Should fail with closed channel error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestDeadlockReportsBlockedTasks(t *testing.T) {
+	var _, err = runCode(`
+	var results = chan()
+	var jobs = chan()
+
+	function worker(jobs) {
+		send(jobs, 1)
+	}
+
+	spawn worker(jobs)
+	receive(results)
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if err.Error() != "Deadlock, all tasks are blocked: main (receive), worker (send)" {
+		t.Errorf("Should report blocked tasks, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestSeededSchedulerIsReproducible(t *testing.T) {
+	var code = `
+	function worker(name, done) {
+		print(name)
+		send(done, name)
+	}
+
+	var done = chan(3)
+	spawn worker("a", done)
+	spawn worker("b", done)
+	spawn worker("c", done)
+	receive(done)
+	receive(done)
+	receive(done)
+	`
+
+	var orders = map[string]bool{}
+
+	for seed := int64(0); seed < 10; seed++ {
+		var first, firstErr = runCodeWith(code, func(rt *Runtime) { rt.SetSchedulerSeed(seed) })
+		var second, secondErr = runCodeWith(code, func(rt *Runtime) { rt.SetSchedulerSeed(seed) })
+
+		if firstErr != nil || secondErr != nil {
+			t.Errorf("Code should run without errors, but received: \"%v\" \"%v\"", firstErr, secondErr)
+			return
+		}
+
+		var order = strings.Join(first.GetLog(), "")
+
+		if order != strings.Join(second.GetLog(), "") {
+			t.Errorf("Same seed should give same order, but received: %v and %v", first.GetLog(), second.GetLog())
+		}
+
+		orders[order] = true
+	}
+
+	if len(orders) < 2 {
+		t.Errorf("Different seeds should give different orders, but received only: %v", orders)
+	}
+}
+
+func TestIntegerArithmeticIsExact(t *testing.T) {
+	var bridge, err = runCode(`
+	var id = 9007199254740993n
+	print(id + 1n)
+	print(id * id)
+	print(0xFFn - 0b1n)
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = "9007199254740994 81129638414606699710187514626049 254"
+
+	if strings.Join(bridge.GetLog(), " ") != expected {
+		t.Errorf("Should calculate integers exactly, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestIntegerDivision(t *testing.T) {
+	var bridge, err = runCode(`
+	print(7n / 2n, 7n % 2n)
+	print((0n - 7n) / 2n, (0n - 7n) % 2n)
+	print(1 + 7 % 4)
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	// Division truncates toward zero, remainder has sign of dividend
+	var expected = "3 1 -3 -1 4"
+
+	if strings.Join(bridge.GetLog(), " ") != expected {
+		t.Errorf("Should truncate integer division, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestIntegerDivisionByZero(t *testing.T) {
+	var _, err = runCode(`
+	var result = 1n / 0n
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.HasPrefix(err.Error(), "Integer division by zero") {
+		t.Errorf("Should fail with division error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestIntegerMixedWithFloat(t *testing.T) {
+	var bridge, err = runCode(`
+	print(typeof (1n + 0.5), 1n + 0.5)
+	print(9007199254740993n > 9007199254740992)
+	print(1n == 1, 2n == 2n, 2n >= 2)
+	print("id: " + 42n)
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = "number 1.5 true false true true id: 42"
+
+	if strings.Join(bridge.GetLog(), " ") != expected {
+		t.Errorf("Should promote integer to float, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestIntegerConversions(t *testing.T) {
+	var bridge, err = runCode(`
+	print(toInteger(3.9), toInteger(0 - 3.9), toInteger("12345678901234567890"), toInteger(true))
+	print(toNumber(3n), typeof toNumber(3n), isInteger(3n), isInteger(3))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = "3 -3 12345678901234567890 1 3 number true false"
+
+	if strings.Join(bridge.GetLog(), " ") != expected {
+		t.Errorf("Should convert values, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestStringMethods(t *testing.T) {
+	var bridge, err = runCode(`
+	var text = "  Hello, World  ".trim()
+	print(text.toUpper(), text.toLower())
+	print(text.slice(0, 5), text.slice(7), text.slice(0 - 5))
+	print(text.indexOf("World"), text.indexOf("x"), text.includes("lo"))
+	print(text.startsWith("He"), text.endsWith("!"))
+	print("a-b-a".replace("a", "c"), "a-b-a".replaceAll("a", "c"), "ab".repeat(2))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{
+		"HELLO, WORLD", "hello, world",
+		"Hello", "World", "World",
+		"7", "-1", "true",
+		"true", "false",
+		"c-b-a", "c-b-c", "abab",
+	}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Should call string methods, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestMethodEquality(t *testing.T) {
+	var bridge, err = runCode(`
+	var text = "a"
+	print(text.trim == text.trim, text.trim == "a".trim, text.trim == "b".trim, text.trim == text.toUpper)
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), "|") != "true|true|false|false" {
+		t.Errorf("Methods should be equal by receiver value, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestStringMethodsCountRunes(t *testing.T) {
+	var bridge, err = runCode(`
+	var text = "héllo wörld"
+	print(text.length, text[1], text[4], text[100])
+	print(text.slice(1, 3), text.indexOf("w"), text.at(0 - 1))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = "11 é o unknown él 6 d"
+
+	if strings.Join(bridge.GetLog(), " ") != expected {
+		t.Errorf("Should count positions in runes, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestStringSplit(t *testing.T) {
+	var bridge, err = runCode(`
+	for (var part of "a,b,,c".split(",")) {
+		print("[" + part + "]")
+	}
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), "") != "[a][b][][c]" {
+		t.Errorf("Should split string, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestNumberMethods(t *testing.T) {
+	var bridge, err = runCode(`
+	var pi = 3.14159
+	print(pi.toFixed(2), pi.toFixed(0), (2).toString(), 12n.toString())
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "3.14 3 2 12" {
+		t.Errorf("Should format numbers, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestUnknownStringMethod(t *testing.T) {
+	var _, err = runCode(`
+	"text".unknownMethod()
+	`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.Contains(err.Error(), "Cannot read property unknownMethod of string") {
+		t.Errorf("Should fail with unknown property error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestMapKeyEquality(t *testing.T) {
+	var bridge, err = runCode(`
+	function createCounter() {
+		return function counter() {}
+	}
+
+	var first = createCounter()
+	var second = createCounter()
+	var key = 1
+
+	var items = Map()
+	items.set(key, "number").set("1", "string").set(1n, "integer").set(true, "boolean")
+	items.set(first, "first").set(second, "second")
+	key = 2
+
+	print(items.get(1), items.get("1"), items.get(1n), items.get(true))
+	print(items.get(first), items.get(second), items[1], items.get(2))
+	print(items.size, items.has(key), items.delete("1"), items.delete("1"))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{
+		"number", "string", "integer", "boolean",
+		"first", "second", "number", "unknown",
+		"6", "false", "true", "false",
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != strings.Join(expected, " ") {
+		t.Errorf("Should compare keys by value and functions by identity, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestMapInsertionOrder(t *testing.T) {
+	var bridge, err = runCode(`
+	var items = Map()
+	items.set("b", 1).set("a", 2).set("c", 3)
+	items.set("b", 10)
+	items.delete("a")
+	items.set("a", 20)
+
+	for (var entry of items) {
+		print(entry.key, entry.value)
+	}
+
+	for (var key of items.keys()) {
+		print(key)
+	}
+
+	print(items)
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{
+		"b", "10", "c", "3", "a", "20",
+		"b", "c", "a",
+		`Map(3) {"b" => 10, "c" => 3, "a" => 20}`,
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != strings.Join(expected, " ") {
+		t.Errorf("Should keep insertion order, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestSet(t *testing.T) {
+	var bridge, err = runCode(`
+	var items = Set()
+	items.add(3).add("x").add(3).add(1)
+	print(items.size, items.has(3), items.has("3"))
+	items.delete("x")
+
+	for (var item of items) {
+		print(item)
+	}
+
+	print(items, Set())
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"3", "true", "false", "3", "1", "Set(2) {3, 1}", "Set(0) {}"}
+
+	if strings.Join(bridge.GetLog(), " ") != strings.Join(expected, " ") {
+		t.Errorf("Should keep unique values, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestAnnotationsAreIgnoredByDefault(t *testing.T) {
+	var bridge, err = runCode(`
+	var a: number = "text"
+
+	function twice(value: number): number {
+		return value + value
+	}
+
+	print(a, twice("b"))
+	`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "text bb" {
+		t.Errorf("Should ignore annotations, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestCheckedMode(t *testing.T) {
+	var checked = func(rt *Runtime) { rt.SetCheckedMode(true) }
+
+	var cases = []struct {
+		code    string
+		message string
+	}{
+		{
+			code:    `var a: number = 1; a = "text";`,
+			message: "Type error, variable a expects number, but received string",
+		},
+		{
+			code:    `function f(value: string) { value = 1; } f("a");`,
+			message: "Type error, variable value expects string, but received number",
+		},
+		{
+			code:    `function f(value: string) {} f(1n);`,
+			message: "Type error, argument value of function f expects string, but received integer",
+		},
+		{
+			code:    `function f(): boolean { return 1; } f();`,
+			message: "Type error, return value of function f expects boolean, but received number",
+		},
+		{
+			code:    `function f(value: string) {} f();`,
+			message: "Type error, argument value of function f expects string, but received unknown",
+		},
+		{
+			code:    `function f(): number {} f();`,
+			message: "Type error, return value of function f expects number, but received unknown",
+		},
+		{
+			code:    `var a: foo = 1;`,
+			message: "Unknown type: foo",
+		},
+		{
+			code:    `function f(value: foo) {} f(1);`,
+			message: "Unknown type: foo",
+		},
+		{
+			code:    `var callback: function = 1;`,
+			message: "Type error, variable callback expects function, but received number",
+		},
+	}
+
+	for _, testCase := range cases {
+		var _, err = runCodeWith(testCase.code, checked)
+
+		if err == nil || !strings.HasPrefix(err.Error(), testCase.message) {
+			t.Errorf("Should fail with %q, but received: %v", testCase.message, err)
+		}
+	}
+
+	var bridge, err = runCodeWith(`
+	var a: number = 1
+	a = a + 1
+	var callback: function = print
+	var value: any = "a"
+	value = 1
+
+	function greet(name: string, times: number): string {
+		return name.repeat(times)
+	}
+
+	callback(a, greet("ab", 2), value)
+	`, checked)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "2 abab 1" {
+		t.Errorf("Should accept values of annotated types, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestEnum(t *testing.T) {
+	var bridge, err = runCode(`
+enum Color { Red, Green, Blue = 10, Last }
+
+print(Color);
+print(Color.Red);
+print(Color.Last.value);
+print(Color.Green.name);
+print(Color[10]);
+print(Color[5]);
+print(Color.Red == Color.Red);
+print(Color.Red == Color.Green);
+print(Color.Red == 0);
+print(typeof Color);
+print(typeof Color.Red);
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{
+		"enum Color {Red = 0, Green = 1, Blue = 10, Last = 11}",
+		"Color.Red",
+		"11",
+		"Green",
+		"Color.Blue",
+		"unknown",
+		"true",
+		"false",
+		"false",
+		"enum",
+		"enum_member",
+	}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Should print enum values, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestEnumMemberAsMapKey(t *testing.T) {
+	var bridge, err = runCode(`
+enum Level { Low = "low", High = "high" }
+
+var names = Map();
+names.set(Level.Low, "calm");
+names.set(Level.High, "alarm");
+print(names.get(Level.High));
+print(Level["low"]);
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "alarm Level.Low" {
+		t.Errorf("Should use enum members as keys, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestEnumIsImmutable(t *testing.T) {
+	var _, err = runCode(`
+enum Color { Red, Green }
+
+Color.Red = 5;
+`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.HasPrefix(err.Error(), "Cannot assign to member of enum Color, enums are immutable") {
+		t.Errorf("Should fail with immutability error, but received: \"%s\"", err.Error())
+	}
+
+	var _, bindingErr = runCode(`
+enum E { A }
+
+E = 5;
+`)
+
+	if bindingErr == nil || !strings.HasPrefix(bindingErr.Error(), "Cannot assign to enum E, enums are immutable") {
+		t.Errorf("Should fail to reassign enum, but received: %v", bindingErr)
+	}
+}
+
+func TestNamedArguments(t *testing.T) {
+	var bridge, err = runCode(`
+function createUser(name, age, admin) {
+	print(name + " " + age + " " + admin);
+}
+
+createUser(name: "Ann", admin: true, age: 31);
+createUser("Bob", admin: false, age: 27);
+
+function describe(name, age, admin) {
+	print(typeof age);
+}
+
+describe("Eve", admin: true);
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"Ann 31 true", "Bob 27 false", "unknown"}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Should bind arguments by name, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestNamedArgumentErrors(t *testing.T) {
+	var cases = []struct {
+		code          string
+		message       string
+		startPosition int
+		endPosition   int
+	}{
+		{
+			code:          `function f(a, b) {} f(a: 1, c: 2);`,
+			message:       "Function f has no argument: c",
+			startPosition: 28,
+			endPosition:   31,
+		},
+		{
+			code:          `function f(a, b) {} f(1, a: 2);`,
+			message:       "Argument a of function f is already passed",
+			startPosition: 25,
+			endPosition:   28,
+		},
+		{
+			code:          `function f(a, b) {} f(b: 1, b: 2);`,
+			message:       "Argument b of function f is already passed",
+			startPosition: 28,
+			endPosition:   31,
+		},
+		{
+			code:          `print(value: 1);`,
+			message:       "Build in function print does not support named arguments",
+			startPosition: 6,
+			endPosition:   13,
+		},
+	}
+
+	for _, testCase := range cases {
+		var _, err = runCode(testCase.code)
+
+		if err == nil {
+			t.Errorf("Code should fail: %s", testCase.code)
+			continue
+		}
+
+		var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+		if !isRuntimeErr || runtimeErr.Message != testCase.message || runtimeErr.StartPosition != testCase.startPosition || runtimeErr.EndPosition != testCase.endPosition {
+			t.Errorf("Should fail with \"%s\" at %d-%d, but received: %#v", testCase.message, testCase.startPosition, testCase.endPosition, err)
+		}
+	}
+}
+
+func TestBitwiseOperators(t *testing.T) {
+	var bridge, err = runCode(`
+print(5 & 3);
+print(5 | 3);
+print(5 ^ 3);
+print(~5);
+print((0 - 16) >> 2);
+print((0 - 16) >>> 28);
+print(1 << 32);
+print(2.9 | 0);
+print(4294967297 | 0);
+print(6 & 3 ^ 1 | 8);
+print(1 + 2 << 1);
+print((1 | 2) == 3);
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"1", "7", "6", "-6", "-4", "15", "1", "2", "1", "11", "6", "true"}
+
+	if strings.Join(bridge.GetLog(), " ") != strings.Join(expected, " ") {
+		t.Errorf("Should calculate bitwise operators, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestBitwiseIntegers(t *testing.T) {
+	var bridge, err = runCode(`
+print(12n & 10n);
+print(12n | 3n);
+print(1n << 70n);
+print((0n - 16n) >> 2n);
+print(~5n);
+print(1n << (0n - 1n));
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"8", "15", "1180591620717411303424", "-4", "-6", "0"}
+
+	if strings.Join(bridge.GetLog(), " ") != strings.Join(expected, " ") {
+		t.Errorf("Should calculate bitwise operators on integers, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestBitwiseErrors(t *testing.T) {
+	var cases = map[string]string{
+		`print(1 & "a");`:   "Operator & expects numbers, but received: string",
+		`print(~true);`:     "Operator ~ expects number, but received: boolean",
+		`print(1n >>> 1n);`: "Unsigned right shift is not defined for integers",
+	}
+
+	for code, message := range cases {
+		var _, err = runCode(code)
+
+		if err == nil {
+			t.Errorf("Code should fail: %s", code)
+			continue
+		}
+
+		if !strings.HasPrefix(err.Error(), message) {
+			t.Errorf("Should fail with \"%s\", but received: \"%s\"", message, err.Error())
+		}
+	}
+}
+
+func TestPipe(t *testing.T) {
+	var bridge, err = runCode(`
+function trim(text) { return text.trim(); }
+function wrap(text, left, right) { return left + text + right; }
+
+print("  hi  " |> trim |> wrap("[", "]"));
+print(1 + 2 |> wrap("<", ">"));
+print("a" |> wrap(right: ")", left: "("));
+print(3 |> "x".repeat);
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"[hi]", "<3>", "(a)", "xxx"}
+
+	if strings.Join(bridge.GetLog(), " ") != strings.Join(expected, " ") {
+		t.Errorf("Should call stages of pipe, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestPipeErrorPosition(t *testing.T) {
+	var _, err = runCode(`function f(a) { return a; } var n = 1; n |> f |> n |> f;`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+	if !isRuntimeErr || !strings.HasPrefix(runtimeErr.Message, "Is not a function") || runtimeErr.StartPosition != 49 || runtimeErr.EndPosition != 49 {
+		t.Errorf("Should point to failed stage of pipe, but received: %#v", err)
+	}
+}
+
+func TestDefer(t *testing.T) {
+	var bridge, err = runCode(`
+function work(name) {
+	defer print("close " + name);
+	var i = 0;
+	while (i < 2) {
+		defer print("iteration " + i);
+		i = i + 1;
+	}
+	print("work " + name);
+	return name + "!";
+}
+
+function early(value) {
+	defer print("early");
+	while (true) {
+		return value;
+	}
+}
+
+print(work("a"));
+print(early(1));
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"work a", "iteration 1", "iteration 0", "close a", "a!", "early", "1"}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Should run deferred calls in reverse order, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestDeferArgumentsAreEvaluatedByStatement(t *testing.T) {
+	var bridge, err = runCode(`
+function f() {
+	var value = 1;
+	defer print(value);
+	value = 2;
+}
+
+f();
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "1" {
+		t.Errorf("Should pass value from moment of defer, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestDeferDoesNotChangeReturnedValue(t *testing.T) {
+	var bridge, err = runCode(`
+function f() {
+	var x = 1;
+	function bump() {
+		x = 100;
+	}
+	defer bump();
+	return x;
+}
+
+print(f());
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "1" {
+		t.Errorf("Should return value from moment of return, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestDeferAfterError(t *testing.T) {
+	var bridge, err = runCode(`
+function fail() {
+	return 1 & "b";
+}
+
+function f() {
+	defer print("cleanup");
+	defer fail();
+	var a = 1 & "a";
+}
+
+f();
+`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "cleanup" {
+		t.Errorf("Should run deferred calls after error, but received: %v", bridge.GetLog())
+	}
+
+	var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+	if !isRuntimeErr || !strings.HasPrefix(runtimeErr.Message, "Operator & expects numbers, but received: string") {
+		t.Errorf("Should fail with error of function body, but received: %#v", err)
+		return
+	}
+
+	if strings.Contains(runtimeErr.Message, "Deferred call failed") {
+		t.Errorf("Should keep message of body error, but received: \"%s\"", runtimeErr.Message)
+	}
+
+	if len(runtimeErr.Deferred) != 1 || runtimeErr.Deferred[0].Message != "Operator & expects numbers, but received: string\n  Deferred call failed" ||
+		runtimeErr.Deferred[0].StartPosition != 29 || describeStack(runtimeErr.Deferred[0]) != "fail:90 f:116" {
+		t.Errorf("Should chain error of deferred call with its position and stack, but received: %#v", runtimeErr.Deferred)
+	}
+
+	if runtimeErr.StartPosition != 105 || runtimeErr.EndPosition != 105 {
+		t.Errorf("Should point to error of function body, but received: %d-%d", runtimeErr.StartPosition, runtimeErr.EndPosition)
+	}
+}
+
+func TestDeferInGenerator(t *testing.T) {
+	var bridge, err = runCode(`
+function* items() {
+	defer print("done");
+	yield 1;
+	yield 2;
+}
+
+for (var item of items()) {
+	print(item);
+}
+
+for (var item of items()) {
+	break;
+}
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "1 2 done" {
+		t.Errorf("Should run deferred calls only after end of generator body, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestDeferOutsideOfFunction(t *testing.T) {
+	var _, err = runCode(`defer print(1);`)
+
+	if err == nil {
+		t.Errorf("Code should fail")
+		return
+	}
+
+	if !strings.HasPrefix(err.Error(), "Cannot defer outside of function") {
+		t.Errorf("Should fail with defer error, but received: \"%s\"", err.Error())
+	}
+}
+
+func TestDecorators(t *testing.T) {
+	var bridge, err = runCode(`
+var calls = 0;
+
+@memo
+function fib(n) {
+	calls = calls + 1;
+	return match (n) {
+		x if x < 2 => x,
+		_ => fib(n - 1) + fib(n - 2)
+	};
+}
+
+print(fib(30), calls);
+print(fib(30), calls);
+
+@twice @exclaim
+function greet(name) {
+	return "hi " + name;
+}
+
+print(greet("ann"));
+
+function twice(f) {
+	function wrapped(value) {
+		return f(f(value));
+	}
+	return wrapped;
+}
+
+function exclaim(f) {
+	function wrapped(value) {
+		return f(value) + "!";
+	}
+	return wrapped;
+}
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"832040", "31", "832040", "31", "hi hi ann!!"}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Should apply decorators, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestMemoCacheKeys(t *testing.T) {
+	var bridge, err = runCode(`
+@memo
+function describe(value) {
+	print("call");
+	return typeof value;
+}
+
+describe(1);
+describe(1);
+describe("1");
+describe(1n);
+describe(Map());
+describe(Map());
+print(fnName(describe));
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{"call", "call", "call", "call", "call", "describe"}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Should cache calls by primitive values, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestTraceDecorator(t *testing.T) {
+	var bridge, err = runCode(`
+@trace
+function fact(n) {
+	return match (n) {
+		x if x < 2 => 1,
+		_ => n * fact(n - 1)
+	};
+}
+
+fact(3);
+
+@trace
+function join(a, b) {
+	return a + b;
+}
+
+join("a", 1n);
+`)
+
+	if err != nil {
+		t.Errorf("Code should run without errors, but received: \"%s\"", err.Error())
+		return
+	}
+
+	var expected = []string{
+		"-> fact(3)",
+		"  -> fact(2)",
+		"    -> fact(1)",
+		"    <- fact(1) = 1",
+		"  <- fact(2) = 2",
+		"<- fact(3) = 6",
+		"-> join(\"a\", 1n)",
+		"<- join(\"a\", 1n) = \"a1\"",
+	}
+
+	if strings.Join(bridge.GetLog(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Should trace calls, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestDecoratorErrors(t *testing.T) {
+	var cases = []struct {
+		code     string
+		message  string
+		position int
+	}{
+		{"@later function f() {} var later = 1", "Decorator is not defined: later", 1},
+		{"var a = 1; @a function f() {}", "Decorator a is not a function. Received: number", 12},
+		{"function id() {} @id function f() {}", "Decorator id should return function. Received: unknown", 18},
+	}
+
+	for _, testCase := range cases {
+		var _, err = runCode(testCase.code)
+
+		if err == nil {
+			t.Errorf("Code should fail: %s", testCase.code)
+			continue
+		}
+
+		var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+		if !isRuntimeErr || !strings.HasPrefix(runtimeErr.Message, testCase.message) || runtimeErr.StartPosition != testCase.position {
+			t.Errorf("Should fail with \"%s\" at %d, but received: %#v", testCase.message, testCase.position, err)
+		}
+	}
+}
+
+func TestCallDepthLimit(t *testing.T) {
+	var _, err = runCode(`
+function f(n) {
+	return f(n + 1)
+}
+f(0)
+`)
+
+	expectLimitError(t, err, runtime_error.KIND_CALL_DEPTH_LIMIT, "Call depth limit exceeded: 10000", 26)
+
+	var bridge, limitedErr = runCodeWith(`
+function f(n) {
+	print(n)
+	return f(n + 1)
+}
+f(0)
+`, func(rt *Runtime) { rt.SetOptions(Options{MaxCallDepth: 3}) })
+
+	expectLimitError(t, limitedErr, runtime_error.KIND_CALL_DEPTH_LIMIT, "Call depth limit exceeded: 3", 36)
+
+	if strings.Join(bridge.GetLog(), "|") != "0|1|2" {
+		t.Errorf("Should run calls until limit, but received: %v", bridge.GetLog())
+	}
+	if runtimeErr, _ := limitedErr.(runtime_error.RuntimeError); len(runtimeErr.Stack) != 3 {
+		t.Errorf("Should keep stack of calls till limit, but received: %#v", limitedErr)
+	}
+}
+
+func TestStepLimit(t *testing.T) {
+	var limited = func(rt *Runtime) { rt.SetOptions(Options{MaxSteps: 1000}) }
+
+	// Tree walker and bytecode machine count different steps, so limit is hit at different nodes
+	for _, isBytecode := range []bool{false, true} {
+		var _, err = runCodeIn(`
+var i = 0
+while (true) {
+	i = i + 1
+}
+`, isBytecode, limited)
+
+		var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+		if !isRuntimeErr || runtimeErr.Kind != runtime_error.KIND_STEP_LIMIT || !strings.HasPrefix(runtimeErr.Message, "Step limit exceeded: 1000") {
+			t.Errorf("Infinite loop should exceed step limit, but received: %#v", err)
+		}
+	}
+
+	var bridge, err = runCodeWith(`print(fib(5))
+function fib(n) {
+	return match (n) {
+		x if x < 2 => n,
+		_ => fib(n - 1) + fib(n - 2)
+	}
+}`, limited)
+
+	if err != nil || strings.Join(bridge.GetLog(), "|") != "5" {
+		t.Errorf("Code should run within step limit, but received: %v, %v", bridge.GetLog(), err)
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	var limited = func(rt *Runtime) { rt.SetOptions(Options{MaxMemory: 1 << 20}) }
+
+	var _, err = runCodeWith(`
+var text = "ab"
+while (true) {
+	text = text + text
+}
+`, limited)
+
+	expectLimitError(t, err, runtime_error.KIND_MEMORY_LIMIT, "Memory limit exceeded: 1048576 bytes", 45)
+
+	var _, repeatErr = runCodeWith(`var text = "ab".repeat(1000000000)`, limited)
+
+	expectLimitError(t, repeatErr, runtime_error.KIND_MEMORY_LIMIT, "Memory limit exceeded: 1048576 bytes", 22)
+
+	var _, mapErr = runCodeWith(`
+var squares = Map()
+var i = 0
+while (true) {
+	squares.set(i, i * i)
+	i = i + 1
+}
+`, limited)
+
+	expectLimitError(t, mapErr, runtime_error.KIND_MEMORY_LIMIT, "Memory limit exceeded: 1048576 bytes", 58)
+
+	var _, integerErr = runCodeWith(`
+var big = 3n
+while (true) {
+	big = big * big
+}
+`, limited)
+
+	expectLimitError(t, integerErr, runtime_error.KIND_MEMORY_LIMIT, "Memory limit exceeded: 1048576 bytes", 40)
+
+	var _, notErr = runCodeWith(`
+var big = 1n << 10000000n
+var inverted = ~big
+`, func(rt *Runtime) { rt.SetOptions(Options{MaxMemory: 2 << 20}) })
+
+	expectLimitError(t, notErr, runtime_error.KIND_MEMORY_LIMIT, "Memory limit exceeded: 2097152 bytes", 42)
+}
+
+func TestMemoryOfFinishedCallsIsReturned(t *testing.T) {
+	var bridge, err = runCodeWith(`
+function sum(a, b, c) {
+	var d = a + b + c
+	return d
+}
+var total = 0
+var i = 0
+while (i < 100000) {
+	total = sum(total, i, 1)
+	i = i + 1
+}
+print(total)
+`, func(rt *Runtime) { rt.SetOptions(Options{MaxMemory: 64 << 10}) })
+
+	if err != nil {
+		t.Errorf("Should return memory of finished calls, but received error: %v", err)
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "5000050000" {
+		t.Errorf("Should print sum, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestMemoryOfReplacedValuesIsReturned(t *testing.T) {
+	var bridge, err = runCodeWith(`
+var total = 0
+var i = 0
+while (i < 300000) {
+	var s = "abcdefghij" + i
+	var last = s
+	last = match (last) { text => text + "!" }
+	total = total + s.length
+	i = i + 1
+}
+var entries = Map()
+for (var item of "1,2,3".split(",")) {
+	entries.set("key", "item " + item)
+	entries.set("other", item)
+	entries.delete("other")
+}
+print(total)
+`, func(rt *Runtime) { rt.SetOptions(Options{MaxMemory: 64 << 10}) })
+
+	if err != nil {
+		t.Errorf("Should return memory of replaced values and ended scopes, but received error: %v", err)
+	}
+
+	if strings.Join(bridge.GetLog(), " ") != "4688890" {
+		t.Errorf("Should print total length, but received: %v", bridge.GetLog())
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	for _, isBytecode := range []bool{false, true} {
+		var _, err = runCodeIn(`while (true) {}`, isBytecode, func(rt *Runtime) {
+			rt.SetOptions(Options{Context: ctx})
+		})
+
+		var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+		if !isRuntimeErr || runtimeErr.Kind != runtime_error.KIND_CANCELLED || !strings.HasPrefix(runtimeErr.Message, "Execution cancelled: context canceled") {
+			t.Errorf("Cancelled context should stop program, but received: %#v", err)
+		}
+	}
+}
+
+func TestContextStopsWaitingForTimer(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	for _, isBytecode := range []bool{false, true} {
+		var start = time.Now()
+		var bridge, err = runCodeIn(`setTimeout(function late() { print("late") }, 60000)`, isBytecode, func(rt *Runtime) {
+			var clock = runtime_clock.CreateRealClock()
+			rt.SetClock(&clock)
+			rt.SetOptions(Options{Context: ctx})
+		})
+
+		var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+		if !isRuntimeErr || runtimeErr.Kind != runtime_error.KIND_CANCELLED || !strings.HasPrefix(runtimeErr.Message, "Execution cancelled: context deadline exceeded") {
+			t.Errorf("Deadline should stop waiting for timer, but received: %#v", err)
+		}
+
+		if time.Since(start) > 5*time.Second || len(bridge.GetLog()) != 0 {
+			t.Errorf("Timer should not run, but waited %v and printed %v", time.Since(start), bridge.GetLog())
+		}
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	var cases = []struct {
+		code  string
+		stack string
+	}{
+		{`
+function inner(text) {
+	return text.repeat(0 - 1)
+}
+function outer() {
+	return inner("a")
+}
+outer()
+`, "repeat:43 inner:85 outer:98"},
+		{`
+function check(n) {
+	return n()
+}
+function run() {
+	var value = 1
+	return check(value)
+}
+run()
+`, "check:80 run:93"},
+		{`
+function* numbers() {
+	yield 1
+	var value = 1
+	return value()
+}
+function take() {
+	var items = numbers()
+	items.next()
+	return items.next()
+}
+take()
+`, "numbers:103 take:147"},
+		{`
+async function load() {
+	await 1
+	return missing()
+}
+function missing() {
+	var value = 1
+	return value()
+}
+load()
+`, "missing:49 load:112"},
+		{`var value = 1; value()`, ""},
+	}
+
+	for _, testCase := range cases {
+		var _, err = runCode(testCase.code)
+
+		if err == nil {
+			t.Errorf("Code should fail: %s", testCase.code)
+			continue
+		}
+
+		if describeStack(err) != testCase.stack {
+			t.Errorf("Should fail with stack \"%s\", but received: \"%s\", %#v", testCase.stack, describeStack(err), err)
+		}
+	}
+}
+
+func runCode(code string) (*runtime_bridge_mock.Bridge, error) {
+	return runCodeWith(code, func(rt *Runtime) {})
+}
+
+// Runs code with virtual clock by tree walker and by bytecode machine.
+// Fails, when results differ. Configure can change runtime before run
+func runCodeWith(code string, configure func(rt *Runtime)) (*runtime_bridge_mock.Bridge, error) {
+	var bridge, err = runCodeIn(code, false, configure)
+	var compiledBridge, compiledErr = runCodeIn(code, true, configure)
+
+	if fmt.Sprintf("%#v", err) != fmt.Sprintf("%#v", compiledErr) {
+		return bridge, fmt.Errorf("Bytecode error differs. Tree walker: %#v, bytecode: %#v", err, compiledErr)
+	}
+
+	if strings.Join(bridge.GetLog(), "\n") != strings.Join(compiledBridge.GetLog(), "\n") {
+		return bridge, fmt.Errorf("Bytecode output differs. Tree walker: %v, bytecode: %v", bridge.GetLog(), compiledBridge.GetLog())
+	}
+
+	return bridge, err
+}
+
+func runCodeIn(code string, isBytecode bool, configure func(rt *Runtime)) (*runtime_bridge_mock.Bridge, error) {
+	var src = source_mock.GetSourceMock(code)
+	var bridge = runtime_bridge_mock.CreateBridge()
+	var stdout = stdout_mock.CreateStdout()
+
+	var parser = parser.CreateParser(src, &stdout)
+
+	var astRoot, astError = parser.Parse(false)
+
+	if astError != nil {
+		return &bridge, astError
+	}
+
+	var clock = runtime_clock.CreateVirtualClock()
+	var rt = CreateRuntime(&bridge)
+	rt.SetClock(&clock)
+	rt.SetBytecodeMode(isBytecode)
+	configure(&rt)
+	var runtimeErr = rt.Run(astRoot)
+
+	if runtimeErr != nil {
+		return &bridge, runtimeErr
+	}
+
+	return &bridge, nil
+}
+
+func expectLimitError(t *testing.T, err error, kind string, message string, position int) {
+	var runtimeErr, isRuntimeErr = err.(runtime_error.RuntimeError)
+
+	if !isRuntimeErr || runtimeErr.Kind != kind || !strings.HasPrefix(runtimeErr.Message, message) || runtimeErr.StartPosition != position {
+		t.Errorf("Should fail with %s error \"%s\" at %d, but received: %#v", kind, message, position, err)
+	}
+}
+
+// Stack as "name:position" of call arguments, the most recent call first
+func describeStack(err error) string {
+	var runtimeErr, _ = err.(runtime_error.RuntimeError)
+	var frames = []string{}
+
+	for _, frame := range runtimeErr.Stack {
+		frames = append(frames, fmt.Sprintf("%s:%d", frame.Name, frame.StartPosition))
+	}
+
+	return strings.Join(frames, " ")
 }
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+	"github.com/VadimZvf/golang/type_checker"
+)
+
+// Checks value against annotation, only in checked mode. Empty annotation accepts any value, nil value is unknown
+func (runtime *Runtime) checkType(subject string, expected string, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	if !runtime.program.isChecked || expected == "" {
+		return nil
+	}
+
+	if !type_checker.IsKnownType(expected) {
+		return runtime_error.CreateError(type_checker.CreateUnknownTypeMessage(expected), node)
+	}
+
+	var actual = type_checker.TYPE_UNKNOWN
+
+	// Function, which finishes without return, and missing argument have no value
+	if value != nil {
+		actual = runtime_heap.GetTypeName(value)
+	}
+
+	if type_checker.IsAssignable(expected, actual) {
+		return nil
+	}
+
+	return runtime_error.CreateError(type_checker.CreateMismatchMessage(subject, expected, actual), node)
+}
+
+func (runtime *Runtime) checkVariableType(name string, slot ast_node.VariableSlot, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	if !runtime.program.isChecked {
+		return nil
+	}
+
+	var variable = runtime.heap.GetVariable(slot)
+
+	if variable == nil {
+		return nil
+	}
+
+	return runtime.checkType("variable "+name, variable.DeclaredType, value, node)
+}
+
+func (runtime *Runtime) checkArgumentType(function *runtime_heap.VariableValue, argument ast_node.FunctionArgument, index int, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	if argument.Type == nil {
+		return nil
+	}
+
+	// Error points to argument, when function is called from code
+	var argumentNode = node
+
+	if node.Code == ast_node.AST_NODE_CODE_CALL_EXPRESSION {
+		var callArgumentNode = ast_node.FindCallArgument(node, index, argument.Name.Value)
+
+		if callArgumentNode != nil {
+			argumentNode = callArgumentNode
+		}
+	}
+
+	var subject = "argument " + argument.Name.Value + " of function " + getFunctionName(function)
+
+	return runtime.checkType(subject, argument.Type.Value, value, argumentNode)
+}
+
+// Returned value of generator and async function is checked only by static checker
+func (runtime *Runtime) checkReturnType(function *runtime_heap.VariableValue, value *runtime_heap.VariableValue, node *ast_node.ASTNode) error {
+	var returnType = ast_node.GetFunctionReturnTypeParam(function.Function().Node)
+
+	if returnType == nil {
+		return nil
+	}
+
+	return runtime.checkType("return value of function "+getFunctionName(function), returnType.Value, value, node)
+}
@@ -0,0 +1,360 @@
+package runtime
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_bytecode"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+// Call of compiled chunk. Calls of regular functions from compiled code get own frame
+// of the same machine, other calls run by their runtime
+type vmFrame struct {
+	chunk   *runtime_bytecode.Chunk
+	pc      int
+	runtime Runtime
+	// Heaps of outer scopes, restored when scope of loop iteration ends
+	scopes []iHeap
+	// Scope without variables has no state, so next iteration of loop reuses it, while outer scope is the same
+	emptyScope      iHeap
+	emptyScopeOuter iHeap
+	// Size of stack, when call started
+	base int
+	// Called function and call node, nil for the first frame
+	function *runtime_heap.VariableValue
+	callNode *ast_node.ASTNode
+	// Heap and completion of called function are kept by frame, so compiled call
+	// doesn't build separate runtime for them. Call site is not kept, because stacks
+	// of suspended generators refer to it and should not keep heap of caller
+	heap       runtime_heap.Heap
+	completion frame
+}
+
+type vm struct {
+	stack  []*runtime_heap.VariableValue
+	frames []*vmFrame
+}
+
+// Runs body node by tree walker, or by bytecode machine in bytecode mode
+func (runtime *Runtime) executeBody(node *ast_node.ASTNode) (*runtime_heap.VariableValue, error) {
+	if !runtime.program.isBytecode {
+		return runtime.visitNode(node)
+	}
+
+	var machine = vm{
+		frames: []*vmFrame{{chunk: runtime.program.getChunk(node), runtime: *runtime}},
+	}
+
+	return nil, machine.run()
+}
+
+// Chunks are compiled once, on the first run of node
+func (program *program) getChunk(node *ast_node.ASTNode) *runtime_bytecode.Chunk {
+	if program.chunks == nil {
+		program.chunks = map[*ast_node.ASTNode]*runtime_bytecode.Chunk{}
+	}
+
+	var chunk = program.chunks[node]
+
+	if chunk == nil {
+		chunk = runtime_bytecode.Compile(node)
+		program.chunks[node] = chunk
+	}
+
+	return chunk
+}
+
+func (machine *vm) push(value *runtime_heap.VariableValue) {
+	machine.stack = append(machine.stack, value)
+}
+
+func (machine *vm) pop() *runtime_heap.VariableValue {
+	var value = machine.stack[len(machine.stack)-1]
+	machine.stack = machine.stack[:len(machine.stack)-1]
+
+	return value
+}
+
+func (machine *vm) run() error {
+	for {
+		var frame = machine.frames[len(machine.frames)-1]
+		var instruction = &frame.chunk.Code[frame.pc]
+		var node = instruction.Node
+		var err error
+
+		frame.pc++
+
+		var stepErr = frame.runtime.program.limits.step(node)
+
+		if stepErr != nil {
+			return machine.fail(stepErr)
+		}
+
+		switch instruction.Code {
+		case runtime_bytecode.OP_EVAL:
+			var value, evalErr = frame.runtime.visitNode(node)
+			err = evalErr
+			machine.push(value)
+
+		case runtime_bytecode.OP_CONSTANT:
+			// Values are immutable, so constant is shared by all pushes
+			machine.push(&frame.chunk.Constants[instruction.Argument])
+
+		case runtime_bytecode.OP_POP:
+			machine.pop()
+
+		case runtime_bytecode.OP_GET_VARIABLE:
+			var value, getErr = frame.runtime.getVariable(node, instruction.Slot)
+			err = getErr
+			machine.push(value)
+
+		case runtime_bytecode.OP_ASSIGN:
+			var value, assignErr = frame.runtime.assignVariable(node, instruction.Text, instruction.Slot, machine.pop())
+			err = assignErr
+			machine.push(value)
+
+		case runtime_bytecode.OP_DECLARE:
+			err = frame.runtime.declareVariable(instruction.Slot, node)
+
+		case runtime_bytecode.OP_FUNCTION:
+			var _, declareErr = frame.runtime.declareFunction(instruction.Text, instruction.Slot, node)
+			err = declareErr
+
+		case runtime_bytecode.OP_BINARY:
+			var right = machine.pop()
+			var left = machine.pop()
+			var value, calculateErr = calculateBinaryExpression(node, left, right)
+			err = calculateErr
+
+			if err == nil {
+				err = frame.runtime.program.limits.checkValue(value, node)
+			}
+
+			machine.push(value)
+
+		case runtime_bytecode.OP_UNARY:
+			var value, calculateErr = frame.runtime.calculateUnaryExpression(node, machine.pop())
+			err = calculateErr
+			machine.push(value)
+
+		case runtime_bytecode.OP_READ_PROPERTY:
+			var value, readErr = readProperty(machine.pop(), instruction.Text, node)
+			err = readErr
+			machine.push(value)
+
+		case runtime_bytecode.OP_INDEX:
+			var index = machine.pop()
+			var value, indexErr = getNativeIndex(machine.pop(), index, node)
+			err = indexErr
+			machine.push(value)
+
+		case runtime_bytecode.OP_MATCH_PATTERN:
+			var pattern = machine.pop()
+
+			if runtime_heap.IsEqual(machine.stack[len(machine.stack)-1], pattern) {
+				frame.pc = instruction.Argument
+			}
+
+		case runtime_bytecode.OP_BIND_MATCH:
+			err = frame.runtime.bindMatchValue(node, machine.stack[len(machine.stack)-1])
+
+		case runtime_bytecode.OP_MATCH_FAIL:
+			err = createMatchError(machine.pop(), instruction.Argument == 1, node)
+
+		case runtime_bytecode.OP_POP_UNDER:
+			var value = machine.pop()
+			machine.stack[len(machine.stack)-1] = value
+
+		case runtime_bytecode.OP_CALL:
+			err = machine.call(frame, instruction.Argument, node)
+
+		case runtime_bytecode.OP_RETURN:
+			var value = runtime_heap.CreateUnknown()
+
+			if instruction.Argument > 0 {
+				var bodyValue = machine.pop()
+
+				if bodyValue != nil {
+					value = bodyValue
+				}
+			}
+
+			frame.runtime.frame.completeReturn(value, node)
+
+		case runtime_bytecode.OP_CHECK_VALUE:
+			if machine.stack[len(machine.stack)-1] == nil {
+				err = runtime_error.CreateError(instruction.Text, node)
+			}
+
+		case runtime_bytecode.OP_JUMP:
+			frame.pc = instruction.Argument
+
+		case runtime_bytecode.OP_JUMP_IF_FALSE:
+			if !runtime_heap.IsTruthy(machine.pop()) {
+				frame.pc = instruction.Argument
+			}
+
+		case runtime_bytecode.OP_JUMP_IF_ABRUPT:
+			if frame.runtime.frame.isAbrupt() {
+				frame.pc = instruction.Argument
+			}
+
+		case runtime_bytecode.OP_PUSH_SCOPE:
+			frame.pushScope(instruction.Argument)
+
+		case runtime_bytecode.OP_POP_SCOPE:
+			frame.popScope()
+
+		case runtime_bytecode.OP_COMPLETE_ITERATION:
+			if frame.runtime.frame.completeIteration() {
+				frame.pc = instruction.Argument
+			}
+
+		case runtime_bytecode.OP_CALL_RESULT:
+			var _, resultErr = frame.runtime.getCallResult()
+			err = resultErr
+
+		case runtime_bytecode.OP_END:
+			frame.popScopes()
+
+			if len(machine.frames) == 1 {
+				return nil
+			}
+
+			err = machine.finishCall()
+		}
+
+		if err != nil {
+			return machine.fail(err)
+		}
+	}
+}
+
+func (frame *vmFrame) pushScope(size int) {
+	var outer = frame.runtime.heap
+	frame.scopes = append(frame.scopes, outer)
+
+	if size == 0 && frame.emptyScope != nil && frame.emptyScopeOuter == outer {
+		frame.runtime.heap = frame.emptyScope
+		return
+	}
+
+	frame.runtime.heap = frame.runtime.createScope(size).heap
+
+	if size == 0 {
+		frame.emptyScope = frame.runtime.heap
+		frame.emptyScopeOuter = outer
+	}
+}
+
+// Restores heap of outer scope, variables of ended scope are not counted anymore
+func (frame *vmFrame) popScope() {
+	frame.runtime.program.limits.releaseScope(frame.runtime.heap)
+	frame.runtime.heap = frame.scopes[len(frame.scopes)-1]
+	frame.scopes = frame.scopes[:len(frame.scopes)-1]
+}
+
+// Ends scopes, which are left by return or error
+func (frame *vmFrame) popScopes() {
+	for len(frame.scopes) > 0 {
+		frame.popScope()
+	}
+}
+
+// Regular function gets new frame, other functions are called by runtime
+func (machine *vm) call(frame *vmFrame, argumentsCount int, node *ast_node.ASTNode) error {
+	var argumentsStart = len(machine.stack) - argumentsCount
+	var function = machine.stack[argumentsStart-1]
+	var boundValues, bindErr = frame.runtime.bindCallArguments(node, function, machine.stack[argumentsStart:])
+
+	machine.stack = machine.stack[:argumentsStart-1]
+
+	if bindErr != nil {
+		return bindErr
+	}
+
+	if function.Kind != runtime_heap.TYPE_FUNCTION || ast_node.IsGeneratorFunction(function.Function().Node) || ast_node.IsAsyncFunction(function.Function().Node) {
+		// Arguments can be kept by call, so they don't share memory with stack
+		var argumentsValues = append([]*runtime_heap.VariableValue(nil), boundValues...)
+		var result, callErr = frame.runtime.callFunction(function, argumentsValues, node)
+		machine.push(result)
+
+		return callErr
+	}
+
+	var callFrame = &vmFrame{
+		chunk:      frame.runtime.program.getChunk(function.Function().Node.Body[0]),
+		base:       len(machine.stack),
+		function:   function,
+		callNode:   node,
+		heap:       runtime_heap.CreateHeap(function.Function().Node.ScopeSize),
+		completion: *createFrame(),
+	}
+
+	callFrame.runtime = Runtime{
+		heap:    &callFrame.heap,
+		bridge:  frame.runtime.bridge,
+		frame:   &callFrame.completion,
+		program: frame.runtime.program,
+		call:    frame.runtime.call.enter(function, node),
+	}
+
+	// Arguments are stored into heap, before stack gets other values
+	var startErr = frame.runtime.startFunctionCall(&callFrame.runtime, function, boundValues, node)
+
+	if startErr != nil {
+		return startErr
+	}
+
+	machine.frames = append(machine.frames, callFrame)
+
+	return nil
+}
+
+// Completes call of the last frame, like runBody and callFunction do, and passes result to caller
+func (machine *vm) finishCall() error {
+	var frame = machine.frames[len(machine.frames)-1]
+	var result, resultErr = frame.runtime.getCallResult()
+
+	resultErr = frame.runtime.runDeferredCalls(resultErr)
+	frame.runtime.releaseCall()
+	machine.frames = machine.frames[:len(machine.frames)-1]
+	machine.stack = machine.stack[:frame.base]
+
+	if resultErr != nil {
+		return frame.runtime.call.attachStack(resultErr)
+	}
+
+	var caller = machine.frames[len(machine.frames)-1]
+	var returnTypeErr = caller.runtime.checkReturnType(frame.function, result, frame.callNode)
+
+	if returnTypeErr != nil {
+		return returnTypeErr
+	}
+
+	machine.push(result)
+
+	return nil
+}
+
+// Passes error through wrappers of failed instructions and ends calls of all frames
+func (machine *vm) fail(err error) error {
+	for {
+		var frame = machine.frames[len(machine.frames)-1]
+
+		// Instruction, which failed, is the previous one
+		err = frame.chunk.WrapError(frame.pc-1, err)
+		err = frame.runtime.call.attachStack(err)
+		frame.runtime.frame.throw(err, frame.chunk.Node)
+		frame.popScopes()
+
+		if len(machine.frames) == 1 {
+			return err
+		}
+
+		machine.frames = machine.frames[:len(machine.frames)-1]
+		machine.stack = machine.stack[:frame.base]
+		err = frame.runtime.runDeferredCalls(err)
+		frame.runtime.releaseCall()
+	}
+}
@@ -21,28 +21,31 @@ func (bridge *Bridge) Print(args ...*runtime_heap.VariableValue) {
 }
 
 func printArg(variable *runtime_heap.VariableValue) {
-	if variable.ValueType == runtime_heap.TYPE_STRING {
+	switch variable.Kind {
+	case runtime_heap.TYPE_STRING:
 		fmt.Println(variable.StringValue)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_NUMBER {
+	case runtime_heap.TYPE_NUMBER:
 		fmt.Println(variable.NumberValue)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_BOOLEAN {
+	case runtime_heap.TYPE_INTEGER:
+		fmt.Println(variable.IntegerValue.String())
+	case runtime_heap.TYPE_BOOLEAN:
 		fmt.Println(variable.BooleanValue)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_FUNCTION {
-		var functionName = ast_node.GetFunctionNameParam(variable.FunctionValue)
+	case runtime_heap.TYPE_FUNCTION:
+		var functionName = ast_node.GetFunctionNameParam(variable.Function().Node)
 		fmt.Println("function " + functionName.Value)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_NATIVE_FUNCTION {
+	case runtime_heap.TYPE_NATIVE_FUNCTION:
 		fmt.Println("native code")
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_UNKNOWN {
+	case runtime_heap.TYPE_GENERATOR:
+		fmt.Println("generator")
+	case runtime_heap.TYPE_PROMISE:
+		fmt.Println("promise")
+	case runtime_heap.TYPE_CHANNEL:
+		fmt.Println("channel")
+	case runtime_heap.TYPE_MAP, runtime_heap.TYPE_SET, runtime_heap.TYPE_ENTRY:
+		fmt.Println(runtime_heap.InspectCollection(variable))
+	case runtime_heap.TYPE_ENUM, runtime_heap.TYPE_ENUM_MEMBER:
+		fmt.Println(runtime_heap.InspectEnum(variable))
+	case runtime_heap.TYPE_UNKNOWN:
 		fmt.Println("unknown")
 	}
 }
@@ -2,6 +2,7 @@ package runtime_bridge_mock
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/VadimZvf/golang/ast_node"
@@ -30,29 +31,36 @@ func (bridge *Bridge) GetLastPring() string {
 	return ""
 }
 
+func (bridge *Bridge) GetLog() []string {
+	return bridge.log
+}
+
 func (bridge *Bridge) saveLogArg(variable *runtime_heap.VariableValue) {
-	if variable.ValueType == runtime_heap.TYPE_STRING {
+	switch variable.Kind {
+	case runtime_heap.TYPE_STRING:
 		bridge.log = append(bridge.log, variable.StringValue)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_NUMBER {
+	case runtime_heap.TYPE_NUMBER:
 		bridge.log = append(bridge.log, strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", variable.NumberValue), "0"), "."))
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_BOOLEAN {
-		bridge.log = append(bridge.log, variable.BooleanValue)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_FUNCTION {
-		var functionName = ast_node.GetFunctionNameParam(variable.FunctionValue)
+	case runtime_heap.TYPE_INTEGER:
+		bridge.log = append(bridge.log, variable.IntegerValue.String())
+	case runtime_heap.TYPE_BOOLEAN:
+		bridge.log = append(bridge.log, strconv.FormatBool(variable.BooleanValue))
+	case runtime_heap.TYPE_FUNCTION:
+		var functionName = ast_node.GetFunctionNameParam(variable.Function().Node)
 		bridge.log = append(bridge.log, "function "+functionName.Value)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_NATIVE_FUNCTION {
+	case runtime_heap.TYPE_NATIVE_FUNCTION:
 		bridge.log = append(bridge.log, "native code")
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_UNKNOWN {
+	case runtime_heap.TYPE_GENERATOR:
+		bridge.log = append(bridge.log, "generator")
+	case runtime_heap.TYPE_PROMISE:
+		bridge.log = append(bridge.log, "promise")
+	case runtime_heap.TYPE_CHANNEL:
+		bridge.log = append(bridge.log, "channel")
+	case runtime_heap.TYPE_MAP, runtime_heap.TYPE_SET, runtime_heap.TYPE_ENTRY:
+		bridge.log = append(bridge.log, runtime_heap.InspectCollection(variable))
+	case runtime_heap.TYPE_ENUM, runtime_heap.TYPE_ENUM_MEMBER:
+		bridge.log = append(bridge.log, runtime_heap.InspectEnum(variable))
+	case runtime_heap.TYPE_UNKNOWN:
 		bridge.log = append(bridge.log, "unknown")
 	}
 }
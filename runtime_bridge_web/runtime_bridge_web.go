@@ -2,6 +2,7 @@ package runtime_bridge_web
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"syscall/js"
 
@@ -32,28 +33,31 @@ func (bridge *Bridge) Print(args ...*runtime_heap.VariableValue) {
 }
 
 func printArg(variable *runtime_heap.VariableValue, bridge *Bridge) {
-	if variable.ValueType == runtime_heap.TYPE_STRING {
+	switch variable.Kind {
+	case runtime_heap.TYPE_STRING:
 		bridge.JSPrint(variable.StringValue)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_NUMBER {
+	case runtime_heap.TYPE_NUMBER:
 		bridge.JSPrint(strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", variable.NumberValue), "0"), "."))
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_BOOLEAN {
-		bridge.JSPrint(variable.BooleanValue)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_FUNCTION {
-		var functionName = ast_node.GetFunctionNameParam(variable.FunctionValue)
+	case runtime_heap.TYPE_INTEGER:
+		bridge.JSPrint(variable.IntegerValue.String())
+	case runtime_heap.TYPE_BOOLEAN:
+		bridge.JSPrint(strconv.FormatBool(variable.BooleanValue))
+	case runtime_heap.TYPE_FUNCTION:
+		var functionName = ast_node.GetFunctionNameParam(variable.Function().Node)
 		bridge.JSPrint("function " + functionName.Value)
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_NATIVE_FUNCTION {
+	case runtime_heap.TYPE_NATIVE_FUNCTION:
 		bridge.JSPrint("native code")
-	}
-
-	if variable.ValueType == runtime_heap.TYPE_UNKNOWN {
+	case runtime_heap.TYPE_GENERATOR:
+		bridge.JSPrint("generator")
+	case runtime_heap.TYPE_PROMISE:
+		bridge.JSPrint("promise")
+	case runtime_heap.TYPE_CHANNEL:
+		bridge.JSPrint("channel")
+	case runtime_heap.TYPE_MAP, runtime_heap.TYPE_SET, runtime_heap.TYPE_ENTRY:
+		bridge.JSPrint(runtime_heap.InspectCollection(variable))
+	case runtime_heap.TYPE_ENUM, runtime_heap.TYPE_ENUM_MEMBER:
+		bridge.JSPrint(runtime_heap.InspectEnum(variable))
+	case runtime_heap.TYPE_UNKNOWN:
 		bridge.JSPrint("unknown")
 	}
 }
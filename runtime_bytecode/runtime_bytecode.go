@@ -0,0 +1,157 @@
+package runtime_bytecode
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_error"
+	"github.com/VadimZvf/golang/runtime_heap"
+)
+
+type OpCode int
+
+const (
+	// Runs node by tree walker and pushes its value. Used for nodes, which are not compiled
+	OP_EVAL OpCode = iota
+	// Pushes copy of constant with index from argument
+	OP_CONSTANT
+	OP_POP
+	// Pushes variable from slot, text is name of variable
+	OP_GET_VARIABLE
+	// Stores top value into variable from slot, value stays on stack
+	OP_ASSIGN
+	// Declares variable in slot without value, text is name of variable
+	OP_DECLARE
+	// Declares function of node in slot, text is name of function
+	OP_FUNCTION
+	// Replaces two top values by result of binary expression node
+	OP_BINARY
+	// Replaces top value by result of unary expression node
+	OP_UNARY
+	// Pops arguments, their count is in argument, and function. Pushes result of call
+	OP_CALL
+	// Pops value and completes call by return. Argument is 0, when return has no value
+	OP_RETURN
+	// Fails with error from text, when top value is not defined
+	OP_CHECK_VALUE
+	OP_JUMP
+	// Pops value and jumps, when it is not true
+	OP_JUMP_IF_FALSE
+	// Jumps, when completion of call is abrupt
+	OP_JUMP_IF_ABRUPT
+	// Starts scope of loop iteration, argument is count of its variables
+	OP_PUSH_SCOPE
+	OP_POP_SCOPE
+	// Consumes break and continue of loop iteration. Jumps, when loop should stop
+	OP_COMPLETE_ITERATION
+	// Replaces top value by its property, text is name of property
+	OP_READ_PROPERTY
+	// Replaces value and index by item of value
+	OP_INDEX
+	// Pops pattern value and jumps, when it is equal to matched value under it
+	OP_MATCH_PATTERN
+	// Stores matched value from top into binding variable of match arm
+	OP_BIND_MATCH
+	// Pops matched value and fails, because no arm matched it. Argument is 1, when match has wildcard arm
+	OP_MATCH_FAIL
+	// Removes value under top one
+	OP_POP_UNDER
+	// Fails with error of abrupt completion, which didn't reach its boundary
+	OP_CALL_RESULT
+	OP_END
+)
+
+var opCodeNames = map[OpCode]string{
+	OP_EVAL:               "EVAL",
+	OP_CONSTANT:           "CONSTANT",
+	OP_POP:                "POP",
+	OP_GET_VARIABLE:       "GET_VARIABLE",
+	OP_ASSIGN:             "ASSIGN",
+	OP_DECLARE:            "DECLARE",
+	OP_FUNCTION:           "FUNCTION",
+	OP_BINARY:             "BINARY",
+	OP_UNARY:              "UNARY",
+	OP_CALL:               "CALL",
+	OP_RETURN:             "RETURN",
+	OP_CHECK_VALUE:        "CHECK_VALUE",
+	OP_JUMP:               "JUMP",
+	OP_JUMP_IF_FALSE:      "JUMP_IF_FALSE",
+	OP_JUMP_IF_ABRUPT:     "JUMP_IF_ABRUPT",
+	OP_PUSH_SCOPE:         "PUSH_SCOPE",
+	OP_POP_SCOPE:          "POP_SCOPE",
+	OP_COMPLETE_ITERATION: "COMPLETE_ITERATION",
+	OP_READ_PROPERTY:      "READ_PROPERTY",
+	OP_INDEX:              "INDEX",
+	OP_MATCH_PATTERN:      "MATCH_PATTERN",
+	OP_BIND_MATCH:         "BIND_MATCH",
+	OP_MATCH_FAIL:         "MATCH_FAIL",
+	OP_POP_UNDER:          "POP_UNDER",
+	OP_CALL_RESULT:        "CALL_RESULT",
+	OP_END:                "END",
+}
+
+func (code OpCode) String() string {
+	return opCodeNames[code]
+}
+
+// Node is source of instruction, errors of instruction point to it
+type Instruction struct {
+	Code     OpCode
+	Argument int
+	Text     string
+	Slot     ast_node.VariableSlot
+	Node     *ast_node.ASTNode
+}
+
+// Range of instructions, which errors are wrapped, like tree walker wraps errors of child node
+type ErrorWrapper struct {
+	Start   int
+	End     int
+	Message string
+	Node    *ast_node.ASTNode
+}
+
+// Compiled body of function or program
+type Chunk struct {
+	Code      []Instruction
+	Constants []runtime_heap.VariableValue
+	// Inner wrappers go before outer ones
+	Wrappers []ErrorWrapper
+	Node     *ast_node.ASTNode
+}
+
+// Wraps error of instruction by wrappers of all ranges, which contain it
+func (chunk *Chunk) WrapError(position int, err error) error {
+	for _, wrapper := range chunk.Wrappers {
+		if position >= wrapper.Start && position < wrapper.End {
+			err = runtime_error.MergeRuntimeErrors(runtime_error.CreateError(wrapper.Message, wrapper.Node), err)
+		}
+	}
+
+	return err
+}
+
+// Listing of instructions, one per line, like "3 JUMP_IF_FALSE 9"
+func (chunk *Chunk) String() string {
+	var lines = make([]string, len(chunk.Code))
+
+	for position, instruction := range chunk.Code {
+		var line = strconv.Itoa(position) + " " + instruction.Code.String()
+
+		switch instruction.Code {
+		case OP_EVAL:
+			line += " " + instruction.Node.Code
+		case OP_CONSTANT:
+			line += " " + runtime_heap.InspectCollection(&chunk.Constants[instruction.Argument])
+		case OP_GET_VARIABLE, OP_ASSIGN, OP_DECLARE, OP_FUNCTION, OP_READ_PROPERTY:
+			line += " " + instruction.Text
+		case OP_CALL, OP_RETURN, OP_JUMP, OP_JUMP_IF_FALSE, OP_JUMP_IF_ABRUPT, OP_COMPLETE_ITERATION, OP_MATCH_PATTERN, OP_MATCH_FAIL:
+			line += " " + strconv.Itoa(instruction.Argument)
+		}
+
+		lines[position] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,478 @@
+package runtime_bytecode
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/runtime_heap"
+	"github.com/VadimZvf/golang/token_number"
+)
+
+type compiler struct {
+	chunk *Chunk
+	// Jumps of abrupt completions, which wait for end of loop iteration or end of chunk.
+	// The last list belongs to the innermost loop
+	abruptJumps [][]int
+}
+
+// Compiles root of program or body of function. Nodes, which are not supported
+// by compiler, are evaluated by tree walker, so result is the same for any code
+func Compile(node *ast_node.ASTNode) *Chunk {
+	var c = compiler{chunk: &Chunk{Node: node}}
+
+	c.abruptJumps = append(c.abruptJumps, []int{})
+
+	switch node.Code {
+	case ast_node.AST_NODE_CODE_ROOT:
+		c.compileStatements(node.Body)
+		c.patchAbruptJumps()
+		c.emit(OP_CALL_RESULT, 0, node)
+	default:
+		c.compileStatement(node)
+		c.patchAbruptJumps()
+	}
+
+	c.emit(OP_END, 0, node)
+
+	return c.chunk
+}
+
+func (c *compiler) emit(code OpCode, argument int, node *ast_node.ASTNode) int {
+	c.chunk.Code = append(c.chunk.Code, Instruction{Code: code, Argument: argument, Node: node})
+
+	return len(c.chunk.Code) - 1
+}
+
+func (c *compiler) emitText(code OpCode, text string, node *ast_node.ASTNode) int {
+	var position = c.emit(code, 0, node)
+	c.chunk.Code[position].Text = text
+
+	return position
+}
+
+func (c *compiler) emitVariable(code OpCode, nameParam *ast_node.ASTNodeParam, node *ast_node.ASTNode) {
+	var position = c.emitText(code, nameParam.Value, node)
+	c.chunk.Code[position].Slot = *nameParam.Slot
+}
+
+// Points jump to the next instruction
+func (c *compiler) patchJump(position int) {
+	c.chunk.Code[position].Argument = len(c.chunk.Code)
+}
+
+func (c *compiler) emitAbruptJump(node *ast_node.ASTNode) {
+	var last = len(c.abruptJumps) - 1
+
+	c.abruptJumps[last] = append(c.abruptJumps[last], c.emit(OP_JUMP_IF_ABRUPT, 0, node))
+}
+
+func (c *compiler) patchAbruptJumps() {
+	var last = len(c.abruptJumps) - 1
+
+	for _, position := range c.abruptJumps[last] {
+		c.patchJump(position)
+	}
+
+	c.abruptJumps = c.abruptJumps[:last]
+}
+
+// Errors of child are wrapped by message. With check, child without value fails with the same message
+func (c *compiler) compileChild(child *ast_node.ASTNode, message string, node *ast_node.ASTNode, isChecked bool) {
+	var start = len(c.chunk.Code)
+
+	c.compileExpression(child)
+
+	c.chunk.Wrappers = append(c.chunk.Wrappers, ErrorWrapper{
+		Start:   start,
+		End:     len(c.chunk.Code),
+		Message: message,
+		Node:    node,
+	})
+
+	if isChecked && !c.isValuePushed() {
+		c.emitText(OP_CHECK_VALUE, message, node)
+	}
+}
+
+// Constant, variable and result of binary expression are always defined, instructions fail without push otherwise
+func (c *compiler) isValuePushed() bool {
+	switch c.chunk.Code[len(c.chunk.Code)-1].Code {
+	case OP_CONSTANT, OP_GET_VARIABLE, OP_BINARY:
+		return true
+	}
+
+	return false
+}
+
+// Function declarations are defined before other statements.
+// Statements run one by one, until one of them completes abruptly
+func (c *compiler) compileStatements(nodes []*ast_node.ASTNode) {
+	for _, node := range nodes {
+		if ast_node.IsHoisted(node) {
+			c.compileFunction(node)
+		}
+	}
+
+	for _, node := range nodes {
+		if ast_node.IsHoisted(node) {
+			continue
+		}
+
+		var start = len(c.chunk.Code)
+
+		c.compileStatement(node)
+
+		if c.isAbruptPossible(start) {
+			c.emitAbruptJump(node)
+		}
+	}
+}
+
+// Only return and nodes of tree walker can complete call abruptly, other calls have own completion
+func (c *compiler) isAbruptPossible(start int) bool {
+	for _, instruction := range c.chunk.Code[start:] {
+		if instruction.Code == OP_EVAL || instruction.Code == OP_RETURN {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *compiler) compileStatement(node *ast_node.ASTNode) {
+	switch node.Code {
+	case ast_node.AST_NODE_CODE_BLOCK:
+		c.compileStatements(node.Body)
+		return
+
+	case ast_node.AST_NODE_CODE_RETURN:
+		if len(node.Body) > 0 {
+			c.compileExpression(node.Body[0])
+			c.emit(OP_RETURN, 1, node)
+		} else {
+			c.emit(OP_RETURN, 0, node)
+		}
+		return
+
+	case ast_node.AST_NODE_CODE_VARIABLE_DECLARATION:
+		var nameParam = ast_node.GetVariableNameParam(node)
+
+		if nameParam != nil && nameParam.Slot != nil {
+			c.emitVariable(OP_DECLARE, nameParam, node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_WHILE:
+		if len(node.Arguments) == 1 && len(node.Body) == 1 {
+			c.compileWhile(node)
+			return
+		}
+	}
+
+	c.compileExpression(node)
+	c.emit(OP_POP, 0, node)
+}
+
+// Function without name or slot is evaluated by tree walker, so it fails with its error
+func (c *compiler) compileFunction(node *ast_node.ASTNode) {
+	var nameParam = ast_node.GetFunctionNameParam(node)
+
+	if nameParam != nil && nameParam.Slot != nil {
+		c.emitVariable(OP_FUNCTION, nameParam, node)
+		return
+	}
+
+	c.emit(OP_EVAL, 0, node)
+	c.emit(OP_POP, 0, node)
+}
+
+// Every iteration has own scope. Abrupt completion of body goes to end of iteration
+func (c *compiler) compileWhile(node *ast_node.ASTNode) {
+	var start = len(c.chunk.Code)
+
+	c.compileChild(node.Arguments[0], "Cannot get while loop condition value", node.Arguments[0], true)
+
+	var exitJump = c.emit(OP_JUMP_IF_FALSE, 0, node)
+
+	c.emit(OP_PUSH_SCOPE, node.ScopeSize, node)
+	c.abruptJumps = append(c.abruptJumps, []int{})
+	c.compileStatement(node.Body[0])
+	c.patchAbruptJumps()
+	c.emit(OP_POP_SCOPE, 0, node)
+
+	var stopJump = c.emit(OP_COMPLETE_ITERATION, 0, node)
+
+	c.emit(OP_JUMP, start, node)
+	c.patchJump(exitJump)
+	c.patchJump(stopJump)
+}
+
+// Leaves value of expression on stack. Value can be not defined, like result of function without return
+func (c *compiler) compileExpression(node *ast_node.ASTNode) {
+	switch node.Code {
+	case ast_node.AST_NODE_CODE_NUMBER, ast_node.AST_NODE_CODE_STRING, ast_node.AST_NODE_CODE_BOOLEAN:
+		var value, isValid = getLiteralValue(node)
+
+		if isValid {
+			c.chunk.Constants = append(c.chunk.Constants, value)
+			c.emit(OP_CONSTANT, len(c.chunk.Constants)-1, node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_REFERENCE:
+		var nameParam = ast_node.GetVariableNameParam(node)
+
+		if nameParam != nil && nameParam.Slot != nil {
+			c.emitVariable(OP_GET_VARIABLE, nameParam, node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_PARENTHESIZED_EXPRESSION:
+		if len(node.Body) == 1 && node.Body[0] != nil {
+			c.compileExpression(node.Body[0])
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_ASSIGNMENT:
+		if len(node.Body) == 2 && node.Body[0] != nil && node.Body[1] != nil && node.Body[0].Code == ast_node.AST_NODE_CODE_REFERENCE {
+			var nameParam = ast_node.GetVariableNameParam(node.Body[0])
+
+			if nameParam != nil && nameParam.Slot != nil {
+				c.compileChild(node.Body[1], "Cannot get variable for assertion", node.Body[1], true)
+				c.emitVariable(OP_ASSIGN, nameParam, node)
+				return
+			}
+		}
+
+	case ast_node.AST_NODE_CODE_BINARY_EXPRESSION:
+		// Pipe calls right side, so it is evaluated by tree walker
+		if len(node.Body) == 2 && node.Body[0] != nil && node.Body[1] != nil && !ast_node.IsPipe(node) {
+			c.compileChild(node.Body[0], "Cannot get left node value", node.Body[0], true)
+			c.compileChild(node.Body[1], "Cannot get right node value", node.Body[1], true)
+			c.emit(OP_BINARY, 0, node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_UNARY_EXPRESSION:
+		if len(node.Body) == 1 {
+			c.compileChild(node.Body[0], "Cannot get operand value", node.Body[0], true)
+			c.emit(OP_UNARY, 0, node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_READ_PROP:
+		var propertyName = ast_node.GetParam(node, ast_node.AST_PARAM_PROPERTY_NAME)
+
+		if propertyName != nil && len(node.Body) == 1 && node.Body[0] != nil {
+			c.compileChild(node.Body[0], "Cannot get value for reading property: "+propertyName.Value, node.Body[0], true)
+			c.emitText(OP_READ_PROPERTY, propertyName.Value, node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_INDEX:
+		if len(node.Body) == 1 && len(node.Arguments) == 1 && node.Body[0] != nil && node.Arguments[0] != nil {
+			c.compileChild(node.Body[0], "Cannot get value for reading index", node.Body[0], true)
+			c.compileChild(node.Arguments[0], "Cannot get index value", node.Arguments[0], true)
+			c.emit(OP_INDEX, 0, node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_MATCH:
+		if isCompiledMatch(node) {
+			c.compileMatch(node)
+			return
+		}
+
+	case ast_node.AST_NODE_CODE_CALL_EXPRESSION:
+		if len(node.Body) > 0 && node.Body[0] != nil {
+			// Function without value fails after arguments are evaluated
+			c.compileChild(node.Body[0], "Cannot get function", node.Body[0], false)
+
+			for _, argumentNode := range node.Arguments {
+				var valueNode = argumentNode
+
+				if ast_node.IsNamedArgument(argumentNode) {
+					valueNode = argumentNode.Body[0]
+				}
+
+				c.compileChild(valueNode, "Cannot get function argument", argumentNode, true)
+			}
+
+			c.emit(OP_CALL, len(node.Arguments), node)
+			return
+		}
+	}
+
+	c.emit(OP_EVAL, 0, node)
+}
+
+// Match with one value, and arms with value and not more than one guard
+func isCompiledMatch(node *ast_node.ASTNode) bool {
+	if len(node.Arguments) != 1 || node.Arguments[0] == nil {
+		return false
+	}
+
+	for _, armNode := range node.Body {
+		var valuesCount = 0
+		var guardsCount = 0
+
+		for _, armBodyNode := range armNode.Body {
+			if armBodyNode.Code == ast_node.AST_NODE_CODE_MATCH_GUARD {
+				guardsCount++
+			} else {
+				valuesCount++
+			}
+		}
+
+		if valuesCount != 1 || guardsCount > 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Matched value stays on stack, while arms are checked one by one. Patterns are evaluated
+// in outer scope, binding, guard and value of arm are evaluated in scope of arm
+func (c *compiler) compileMatch(node *ast_node.ASTNode) {
+	c.compileChild(node.Arguments[0], "Cannot get match value", node.Arguments[0], true)
+
+	var endJumps = []int{}
+	var hasWildcard = 0
+
+	for _, armNode := range node.Body {
+		var matchedJumps = []int{}
+		var isAlwaysMatched = false
+		var bindingNode *ast_node.ASTNode
+
+		// Patterns after wildcard or binding are not evaluated
+		for _, patternNode := range armNode.Arguments {
+			if patternNode.Code == ast_node.AST_NODE_CODE_MATCH_WILDCARD {
+				isAlwaysMatched = true
+				break
+			}
+
+			if patternNode.Code == ast_node.AST_NODE_CODE_MATCH_BINDING {
+				isAlwaysMatched = true
+				bindingNode = patternNode
+				break
+			}
+
+			c.compileChild(patternNode, "Cannot get match pattern value", patternNode, false)
+			matchedJumps = append(matchedJumps, c.emit(OP_MATCH_PATTERN, 0, patternNode))
+		}
+
+		for _, patternNode := range armNode.Arguments {
+			if patternNode.Code == ast_node.AST_NODE_CODE_MATCH_WILDCARD {
+				hasWildcard = 1
+			}
+		}
+
+		var nextArmJump = -1
+
+		if !isAlwaysMatched {
+			nextArmJump = c.emit(OP_JUMP, 0, armNode)
+		}
+
+		for _, position := range matchedJumps {
+			c.patchJump(position)
+		}
+
+		if armNode.ScopeSize > 0 {
+			c.emit(OP_PUSH_SCOPE, armNode.ScopeSize, armNode)
+		}
+
+		if bindingNode != nil {
+			c.emit(OP_BIND_MATCH, 0, bindingNode)
+		}
+
+		var guardJump = -1
+		var valueNode *ast_node.ASTNode
+
+		for _, armBodyNode := range armNode.Body {
+			if armBodyNode.Code != ast_node.AST_NODE_CODE_MATCH_GUARD {
+				valueNode = armBodyNode
+				continue
+			}
+
+			c.compileChild(armBodyNode.Body[0], "Cannot get match guard value", armBodyNode, true)
+			guardJump = c.emit(OP_JUMP_IF_FALSE, 0, armBodyNode)
+		}
+
+		c.compileExpression(valueNode)
+
+		if armNode.ScopeSize > 0 {
+			c.emit(OP_POP_SCOPE, 0, armNode)
+		}
+
+		c.emit(OP_POP_UNDER, 0, node)
+		endJumps = append(endJumps, c.emit(OP_JUMP, 0, armNode))
+
+		// Arm, which guard is false, leaves its scope and goes to the next arm
+		if guardJump >= 0 {
+			c.patchJump(guardJump)
+
+			if armNode.ScopeSize > 0 {
+				c.emit(OP_POP_SCOPE, 0, armNode)
+			}
+		}
+
+		if nextArmJump >= 0 {
+			c.patchJump(nextArmJump)
+		}
+	}
+
+	c.emit(OP_MATCH_FAIL, hasWildcard, node)
+
+	for _, position := range endJumps {
+		c.patchJump(position)
+	}
+}
+
+// Value of literal node. Invalid literals are left for tree walker, so they fail with its error
+func getLiteralValue(node *ast_node.ASTNode) (runtime_heap.VariableValue, bool) {
+	switch node.Code {
+	case ast_node.AST_NODE_CODE_NUMBER:
+		var numberValue = ast_node.GetNumberValueParam(node)
+
+		if numberValue == nil {
+			return runtime_heap.VariableValue{}, false
+		}
+
+		if token_number.IsInteger(numberValue.Value) {
+			var integer, integerErr = token_number.ParseInteger(numberValue.Value)
+
+			if integerErr != nil {
+				return runtime_heap.VariableValue{}, false
+			}
+
+			return *runtime_heap.CreateInteger(integer), true
+		}
+
+		var number, numberErr = token_number.ParseNumber(numberValue.Value)
+
+		if numberErr != nil {
+			return runtime_heap.VariableValue{}, false
+		}
+
+		return *runtime_heap.CreateNumber(number), true
+
+	case ast_node.AST_NODE_CODE_STRING:
+		var stringValue = ast_node.GetStringValueParam(node)
+
+		if stringValue == nil {
+			return runtime_heap.VariableValue{}, false
+		}
+
+		return *runtime_heap.CreateString(stringValue.Value), true
+
+	case ast_node.AST_NODE_CODE_BOOLEAN:
+		var booleanValue = ast_node.GetBooleanValueParam(node)
+
+		if booleanValue == nil {
+			return runtime_heap.VariableValue{}, false
+		}
+
+		return *runtime_heap.CreateBoolean(booleanValue.Value == "true"), true
+	}
+
+	return runtime_heap.VariableValue{}, false
+}
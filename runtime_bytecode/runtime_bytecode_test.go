@@ -0,0 +1,113 @@
+package runtime_bytecode
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser"
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/stdout_mock"
+)
+
+func parseCode(t *testing.T, code string) *ast_node.ASTNode {
+	var src = source_mock.GetSourceMock(code)
+	var stdout = stdout_mock.CreateStdout()
+	var codeParser = parser.CreateParser(src, &stdout)
+	var astRoot, astError = codeParser.Parse(false)
+
+	if astError != nil {
+		t.Fatalf("Code should be parsed, but failed with error: \"%s\"", astError.Error())
+	}
+
+	return astRoot
+}
+
+func TestCompileWhileLoop(t *testing.T) {
+	var chunk = Compile(parseCode(t, `
+	var i = 0
+	while (i < 3) {
+		i = i + 1
+	}
+	print(i)
+	`))
+
+	var expected = `0 DECLARE i
+1 CONSTANT 0
+2 ASSIGN i
+3 POP
+4 GET_VARIABLE i
+5 CONSTANT 3
+6 BINARY
+7 JUMP_IF_FALSE 17
+8 PUSH_SCOPE
+9 GET_VARIABLE i
+10 CONSTANT 1
+11 BINARY
+12 ASSIGN i
+13 POP
+14 POP_SCOPE
+15 COMPLETE_ITERATION 17
+16 JUMP 4
+17 GET_VARIABLE print
+18 GET_VARIABLE i
+19 CALL 1
+20 POP
+21 CALL_RESULT
+22 END`
+
+	if chunk.String() != expected {
+		t.Errorf("Chunk should be:\n%s\nbut received:\n%s", expected, chunk.String())
+	}
+}
+
+func TestCompileMatch(t *testing.T) {
+	var chunk = Compile(parseCode(t, `
+	var text = "abc"
+	print(match (text.length) {
+		0 | 1 => "short",
+		n if n > 10 => "long",
+		_ => "medium"
+	})
+	`))
+
+	var expected = `0 DECLARE text
+1 CONSTANT "abc"
+2 ASSIGN text
+3 POP
+4 GET_VARIABLE print
+5 GET_VARIABLE text
+6 READ_PROPERTY length
+7 CHECK_VALUE
+8 CONSTANT 0
+9 MATCH_PATTERN 13
+10 CONSTANT 1
+11 MATCH_PATTERN 13
+12 JUMP 16
+13 CONSTANT "short"
+14 POP_UNDER
+15 JUMP 31
+16 PUSH_SCOPE
+17 BIND_MATCH
+18 GET_VARIABLE n
+19 CONSTANT 10
+20 BINARY
+21 JUMP_IF_FALSE 26
+22 CONSTANT "long"
+23 POP_SCOPE
+24 POP_UNDER
+25 JUMP 31
+26 POP_SCOPE
+27 CONSTANT "medium"
+28 POP_UNDER
+29 JUMP 31
+30 MATCH_FAIL 1
+31 CHECK_VALUE
+32 CALL 1
+33 POP
+34 CALL_RESULT
+35 END`
+
+	if chunk.String() != expected {
+		t.Errorf("Chunk should be:\n%s\nbut received:\n%s", expected, chunk.String())
+	}
+}
@@ -0,0 +1,57 @@
+package runtime_clock
+
+import (
+	"time"
+)
+
+// All times are in milliseconds
+
+// Clock of real world, timers wait for real time
+type RealClock struct {
+	start time.Time
+}
+
+func CreateRealClock() RealClock {
+	return RealClock{
+		start: time.Now(),
+	}
+}
+
+func (clock *RealClock) Now() float64 {
+	return float64(time.Since(clock.start)) / float64(time.Millisecond)
+}
+
+// Waits for duration, or until cancel is closed. Nil cancel never closes
+func (clock *RealClock) Sleep(duration float64, cancel <-chan struct{}) {
+	var timer = time.NewTimer(time.Duration(duration * float64(time.Millisecond)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-cancel:
+	}
+}
+
+// Clock with virtual time. Sleep moves time forward immediately,
+// so timers run deterministically and without real waiting
+type VirtualClock struct {
+	now float64
+}
+
+func CreateVirtualClock() VirtualClock {
+	return VirtualClock{}
+}
+
+func (clock *VirtualClock) Now() float64 {
+	return clock.now
+}
+
+func (clock *VirtualClock) Sleep(duration float64, cancel <-chan struct{}) {
+	clock.Advance(duration)
+}
+
+func (clock *VirtualClock) Advance(duration float64) {
+	if duration > 0 {
+		clock.now = clock.now + duration
+	}
+}
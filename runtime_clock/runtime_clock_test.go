@@ -0,0 +1,43 @@
+package runtime_clock
+
+import (
+	"testing"
+)
+
+func TestVirtualClock(t *testing.T) {
+	var clock = CreateVirtualClock()
+
+	if clock.Now() != 0 {
+		t.Errorf("Virtual clock should start from 0. But received: %f", clock.Now())
+	}
+
+	clock.Sleep(150, nil)
+	clock.Advance(50)
+	clock.Advance(-10)
+
+	if clock.Now() != 200 {
+		t.Errorf("Virtual clock should move time forward. But received: %f", clock.Now())
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	var clock = CreateRealClock()
+
+	clock.Sleep(2, nil)
+
+	if clock.Now() < 2 {
+		t.Errorf("Real clock should wait. But received: %f", clock.Now())
+	}
+}
+
+func TestRealClockCancel(t *testing.T) {
+	var clock = CreateRealClock()
+	var cancel = make(chan struct{})
+
+	close(cancel)
+	clock.Sleep(60*1000, cancel)
+
+	if clock.Now() >= 60*1000 {
+		t.Errorf("Real clock should stop waiting, when cancel is closed. But received: %f", clock.Now())
+	}
+}
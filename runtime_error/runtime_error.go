@@ -2,10 +2,29 @@ package runtime_error
 
 import "github.com/VadimZvf/golang/ast_node"
 
+// Kinds of errors, which stop program, when it exceeds limits of runtime.
+// Errors of program itself have empty kind
+const KIND_STEP_LIMIT = "STEP_LIMIT"
+const KIND_CALL_DEPTH_LIMIT = "CALL_DEPTH_LIMIT"
+const KIND_MEMORY_LIMIT = "MEMORY_LIMIT"
+const KIND_CANCELLED = "CANCELLED"
+
+// Function call, which was not finished, when error happened. Positions are span of call expression
+type StackFrame struct {
+	Name          string
+	StartPosition int
+	EndPosition   int
+}
+
 type RuntimeError struct {
 	Message       string
 	StartPosition int
 	EndPosition   int
+	Kind          string
+	// Calls, which led to error, the most recent first. Nil for errors outside of functions
+	Stack []StackFrame
+	// Errors of deferred calls, which failed after this error. They keep own positions and stacks
+	Deferred []RuntimeError
 }
 
 func (err RuntimeError) Error() string {
@@ -20,6 +39,25 @@ func CreateError(message string, node *ast_node.ASTNode) RuntimeError {
 	}
 }
 
+// Error of exceeded limit, pointing at node, which was evaluated when limit was hit
+func CreateLimitError(kind string, message string, node *ast_node.ASTNode) RuntimeError {
+	var err = CreateError(message, node)
+	err.Kind = kind
+
+	return err
+}
+
+// Kind of runtime error, empty for other errors
+func GetKind(err error) string {
+	runtimeError, isRuntimeError := err.(RuntimeError)
+
+	if !isRuntimeError {
+		return ""
+	}
+
+	return runtimeError.Kind
+}
+
 func MergeRuntimeErrors(first error, second error) error {
 	firstError, firstCastOk := first.(RuntimeError)
 
@@ -43,5 +81,33 @@ func MergeRuntimeErrors(first error, second error) error {
 		firstError.EndPosition = secondError.EndPosition
 	}
 
+	// Exceeded limit stays the reason of wrapped error
+	if secondError.Kind != "" {
+		firstError.Kind = secondError.Kind
+	}
+
+	if secondError.Stack != nil {
+		firstError.Stack = secondError.Stack
+	}
+
+	if secondError.Deferred != nil {
+		firstError.Deferred = secondError.Deferred
+	}
+
 	return firstError
 }
+
+// Chains error of deferred call to error, which happened before it
+func AddDeferredError(err error, deferredErr error) error {
+	runtimeError, isRuntimeError := err.(RuntimeError)
+	deferredRuntimeError, isDeferredRuntimeError := deferredErr.(RuntimeError)
+
+	if !isRuntimeError || !isDeferredRuntimeError {
+		return err
+	}
+
+	// Slice is copied, because wrapped errors can share it
+	runtimeError.Deferred = append(append([]RuntimeError{}, runtimeError.Deferred...), deferredRuntimeError)
+
+	return runtimeError
+}
@@ -1,6 +1,8 @@
 package runtime_error_printer
 
 import (
+	"strconv"
+
 	"github.com/VadimZvf/golang/runtime_error"
 )
 
@@ -16,28 +18,88 @@ func PrintError(code string, std iStdout, err error) {
 		panic(err)
 	}
 
+	// Positions are counted in symbols, so slice code by runes
+	var symbols = []rune(code)
+	var startPosition = clamp(re.StartPosition, 0, len(symbols))
+	var endPosition = clamp(re.EndPosition, startPosition-1, len(symbols)-1)
 	var i = 0
 
-	for ; i < re.StartPosition; i++ {
-		std.Print(string(code[i]))
+	for ; i < startPosition; i++ {
+		std.Print(string(symbols[i]))
 	}
 
 	// Print error parth
-	for ; i <= re.EndPosition; i++ {
-		std.PrintError(string(code[i]))
+	for ; i <= endPosition; i++ {
+		std.PrintError(string(symbols[i]))
 	}
 
 	// Print valid part, at the same line
-	for ; i < len(code) && string(code[i]) != "\n"; i++ {
-		std.Print(string(code[i]))
+	for ; i < len(symbols) && symbols[i] != '\n'; i++ {
+		std.Print(string(symbols[i]))
 	}
 
 	std.Print("\n")
 	std.PrintError(re.Message)
 
-	for ; i < len(code); i++ {
-		std.Print(string(code[i]))
+	for ; i < len(symbols); i++ {
+		std.Print(string(symbols[i]))
 	}
 
 	std.Print("\n")
+	printStack(symbols, std, re.Stack)
+
+	// Deferred calls, which failed after error, are printed as separate errors
+	for _, deferredErr := range re.Deferred {
+		PrintError(code, std, deferredErr)
+	}
+}
+
+// Count of printed calls of long stack, half from top and half from bottom
+const maxPrintedFrames = 20
+
+// Prints calls, which led to error, the most recent first, like "  at fib (line 3)"
+func printStack(symbols []rune, std iStdout, stack []runtime_error.StackFrame) {
+	if len(stack) == 0 {
+		return
+	}
+
+	std.PrintError("Stack trace:\n")
+
+	for index := 0; index < len(stack); index++ {
+		// Deep recursion repeats the same calls, so middle of stack is skipped
+		if len(stack) > maxPrintedFrames && index == maxPrintedFrames/2 {
+			var skipped = len(stack) - maxPrintedFrames
+			std.PrintError("  ... " + strconv.Itoa(skipped) + " more calls\n")
+			index += skipped - 1
+			continue
+		}
+
+		var line = getLine(symbols, stack[index].StartPosition)
+		std.PrintError("  at " + stack[index].Name + " (line " + strconv.Itoa(line) + ")\n")
+	}
+}
+
+// Line number of symbol position, starting from 1
+func getLine(symbols []rune, position int) int {
+	var line = 1
+
+	for i := 0; i < position && i < len(symbols); i++ {
+		if symbols[i] == '\n' {
+			line++
+		}
+	}
+
+	return line
+}
+
+func clamp(value int, lower int, upper int) int {
+	if value < lower {
+		return lower
+	}
+
+	if value > upper {
+		return upper
+	}
+
+	return value
 }
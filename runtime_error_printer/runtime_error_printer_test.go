@@ -0,0 +1,80 @@
+package runtime_error_printer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VadimZvf/golang/runtime_error"
+)
+
+// Keeps printed errors, code is skipped
+type errorsStdout struct {
+	errors []string
+}
+
+func (std *errorsStdout) Print(line string) {
+}
+
+func (std *errorsStdout) PrintError(line string) {
+	std.errors = append(std.errors, line)
+}
+
+func TestPrintStack(t *testing.T) {
+	var std = errorsStdout{}
+	var code = "function f() {\n\treturn g()\n}\nf()"
+
+	PrintError(code, &std, runtime_error.RuntimeError{
+		Message:       "Is not a function",
+		StartPosition: 24,
+		EndPosition:   24,
+		Stack: []runtime_error.StackFrame{
+			{Name: "g", StartPosition: 24, EndPosition: 25},
+			{Name: "f", StartPosition: 30, EndPosition: 31},
+		},
+	})
+
+	var expected = "(Is not a functionStack trace:\n  at g (line 2)\n  at f (line 4)\n"
+
+	if strings.Join(std.errors, "") != expected {
+		t.Errorf("Should print stack, but received: %q", strings.Join(std.errors, ""))
+	}
+}
+
+func TestPrintLongStack(t *testing.T) {
+	var std = errorsStdout{}
+	var stack = []runtime_error.StackFrame{}
+
+	for index := 0; index < 25; index++ {
+		stack = append(stack, runtime_error.StackFrame{Name: "f", StartPosition: 0})
+	}
+
+	PrintError("f()", &std, runtime_error.RuntimeError{Message: "Failed", Stack: stack})
+
+	var printed = strings.Join(std.errors, "")
+
+	if strings.Count(printed, "  at f (line 1)\n") != 20 || !strings.Contains(printed, "  ... 5 more calls\n") {
+		t.Errorf("Should skip middle of long stack, but received: %q", printed)
+	}
+}
+
+func TestPrintDeferredErrors(t *testing.T) {
+	var std = errorsStdout{}
+
+	PrintError("f()\ng()", &std, runtime_error.RuntimeError{
+		Message:       "Failed",
+		StartPosition: 0,
+		EndPosition:   0,
+		Deferred: []runtime_error.RuntimeError{{
+			Message:       "Is not a function\n  Deferred call failed",
+			StartPosition: 4,
+			EndPosition:   4,
+			Stack:         []runtime_error.StackFrame{{Name: "g", StartPosition: 4, EndPosition: 6}},
+		}},
+	})
+
+	var expected = "fFailedgIs not a function\n  Deferred call failedStack trace:\n  at g (line 2)\n"
+
+	if strings.Join(std.errors, "") != expected {
+		t.Errorf("Should print deferred error separately, but received: %q", strings.Join(std.errors, ""))
+	}
+}
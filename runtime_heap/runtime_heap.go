@@ -2,165 +2,520 @@ package runtime_heap
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/VadimZvf/golang/ast_node"
 	"github.com/VadimZvf/golang/runtime_error"
 )
 
-var TYPE_STRING = "STRING"
-var TYPE_NUMBER = "NUMBER"
-var TYPE_BOOLEAN = "BOOLEAN"
-var TYPE_FUNCTION = "FUNCTION"
-var TYPE_NATIVE_FUNCTION = "NATIVE_FUNCTION"
-var TYPE_UNKNOWN = "UNKNOWN"
+// Kind of value. Lowercase name of kind is visible for scripts
+type ValueKind uint8
+
+const (
+	TYPE_UNKNOWN ValueKind = iota
+	TYPE_STRING
+	TYPE_NUMBER
+	TYPE_INTEGER
+	TYPE_BOOLEAN
+	TYPE_FUNCTION
+	TYPE_NATIVE_FUNCTION
+	TYPE_GENERATOR
+	TYPE_PROMISE
+	TYPE_CHANNEL
+	TYPE_MAP
+	TYPE_SET
+	TYPE_ENTRY
+	TYPE_ENUM
+	TYPE_ENUM_MEMBER
+)
+
+var kindNames = [...]string{
+	TYPE_UNKNOWN:         "UNKNOWN",
+	TYPE_STRING:          "STRING",
+	TYPE_NUMBER:          "NUMBER",
+	TYPE_INTEGER:         "INTEGER",
+	TYPE_BOOLEAN:         "BOOLEAN",
+	TYPE_FUNCTION:        "FUNCTION",
+	TYPE_NATIVE_FUNCTION: "NATIVE_FUNCTION",
+	TYPE_GENERATOR:       "GENERATOR",
+	TYPE_PROMISE:         "PROMISE",
+	TYPE_CHANNEL:         "CHANNEL",
+	TYPE_MAP:             "MAP",
+	TYPE_SET:             "SET",
+	TYPE_ENTRY:           "ENTRY",
+	TYPE_ENUM:            "ENUM",
+	TYPE_ENUM_MEMBER:     "ENUM_MEMBER",
+}
+
+func (kind ValueKind) String() string {
+	return kindNames[kind]
+}
 
+// Value of kind and its payload. Numbers, integers, strings and booleans are stored
+// in place. Other kinds keep shared object in Reference, so copy of value refers
+// to the same function, generator, promise, channel, collection, entry or enum.
+// Values are not changed after creation, assignment puts new value to heap slot.
+// So value can be shared by slots, constants and stack of bytecode machine
 type VariableValue struct {
-	ValueType           string
-	StringValue         string
-	NumberValue         float64
-	BooleanValue        string
-	FunctionValue       *ast_node.ASTNode
-	FunctionClosureHeap *Heap
-	NativeFunctionName  string
+	Kind         ValueKind
+	BooleanValue bool
+	NumberValue  float64
+	StringValue  string
+	IntegerValue *big.Int
+	// One of *Function, *NativeFunction, IGenerator, IPromise, IChannel,
+	// *Collection, *CollectionEntry, *Enum or *EnumMember
+	Reference interface{}
+	// Annotated type of variable. It stays with variable, when value is changed
+	DeclaredType string
+}
+
+// Function declared by program, with heap of scope, where it is declared
+type Function struct {
+	Node    *ast_node.ASTNode
+	Closure *Heap
+}
+
+// Build in function, method of native value, or function created by program
+type NativeFunction struct {
+	Name string
+	// Value for methods of native types, like "next" of generator
+	Receiver *VariableValue
+	// Native function, which is created by program, like function returned by memo
+	Closure interface{}
 }
 
+// Result of async operation, settled by event loop of runtime
+type IPromise interface {
+	GetState() string
+}
+
+// Queue of values between tasks
+type IChannel interface {
+	Len() int
+	Cap() int
+	IsClosed() bool
+}
+
+// Suspended generator call, resumed by every "next" call
+type IGenerator interface {
+	Next(value *VariableValue) (*VariableValue, error)
+	IsDone() bool
+	Close()
+}
+
+// Values are immutable, so booleans and unknown are shared
+var trueValue = &VariableValue{Kind: TYPE_BOOLEAN, BooleanValue: true}
+var falseValue = &VariableValue{Kind: TYPE_BOOLEAN, BooleanValue: false}
+var unknownValue = &VariableValue{Kind: TYPE_UNKNOWN}
+
+func CreateReference(kind ValueKind, reference interface{}) *VariableValue {
+	return &VariableValue{
+		Kind:      kind,
+		Reference: reference,
+	}
+}
+
+func CreateFunction(node *ast_node.ASTNode, closure *Heap) *VariableValue {
+	return CreateReference(TYPE_FUNCTION, &Function{Node: node, Closure: closure})
+}
+
+func CreateNativeFunction(name string, receiver *VariableValue, closure interface{}) *VariableValue {
+	return CreateReference(TYPE_NATIVE_FUNCTION, &NativeFunction{Name: name, Receiver: receiver, Closure: closure})
+}
+
+func CreateUnknown() *VariableValue {
+	return unknownValue
+}
+
+// Nil, when value is not function
+func (variable *VariableValue) Function() *Function {
+	var function, _ = variable.Reference.(*Function)
+
+	return function
+}
+
+func (variable *VariableValue) NativeFunction() *NativeFunction {
+	var function, _ = variable.Reference.(*NativeFunction)
+
+	return function
+}
+
+func (variable *VariableValue) Generator() IGenerator {
+	var gen, _ = variable.Reference.(IGenerator)
+
+	return gen
+}
+
+func (variable *VariableValue) Promise() IPromise {
+	var promise, _ = variable.Reference.(IPromise)
+
+	return promise
+}
+
+func (variable *VariableValue) Channel() IChannel {
+	var channel, _ = variable.Reference.(IChannel)
+
+	return channel
+}
+
+func (variable *VariableValue) Collection() *Collection {
+	var collection, _ = variable.Reference.(*Collection)
+
+	return collection
+}
+
+func (variable *VariableValue) Entry() *CollectionEntry {
+	var entry, _ = variable.Reference.(*CollectionEntry)
+
+	return entry
+}
+
+func (variable *VariableValue) Enum() *Enum {
+	var enum, _ = variable.Reference.(*Enum)
+
+	return enum
+}
+
+func (variable *VariableValue) EnumMember() *EnumMember {
+	var member, _ = variable.Reference.(*EnumMember)
+
+	return member
+}
+
+// Variables of one scope in slots, which are assigned by resolver.
+// Safe for concurrent access, parent heaps have own locks
 type Heap struct {
 	parentHeap *Heap
-	values     map[string]*VariableValue
+	// Variable, which is not declared yet, is nil
+	values []*VariableValue
+	lock   *sync.RWMutex
 }
 
-func CreateHeap() Heap {
-	var values = map[string]*VariableValue{}
-
+// Size is count of variables in scope. Heap grows, when variable gets slot out of it
+func CreateHeap(size int) Heap {
 	return Heap{
 		parentHeap: nil,
-		values:     values,
+		values:     make([]*VariableValue, size),
+		lock:       &sync.RWMutex{},
 	}
 }
 
-func (heap *Heap) CreateVariable(name string) error {
-	var prevVariable = heap.values[name]
+func (heap *Heap) CreateVariable(index int) error {
+	heap.lock.Lock()
+	defer heap.lock.Unlock()
+
+	for index >= len(heap.values) {
+		heap.values = append(heap.values, nil)
+	}
 
-	if prevVariable != nil {
+	if heap.values[index] != nil {
 		return runtime_error.RuntimeError{
 			Message: "Variable already declared",
 		}
 	}
 
-	heap.values[name] = &VariableValue{
-		ValueType: TYPE_UNKNOWN,
+	heap.values[index] = &VariableValue{}
+
+	return nil
+}
+
+// Declares variable with value in one step, so slot doesn't get empty value first.
+// Value, which keeps declared type of other variable, gets own copy without it
+func (heap *Heap) DefineVariable(index int, variable *VariableValue) error {
+	heap.lock.Lock()
+	defer heap.lock.Unlock()
+
+	for index >= len(heap.values) {
+		heap.values = append(heap.values, nil)
 	}
 
+	if heap.values[index] != nil {
+		return runtime_error.RuntimeError{
+			Message: "Variable already declared",
+		}
+	}
+
+	if variable.DeclaredType != "" {
+		var copied = *variable
+		copied.DeclaredType = ""
+		variable = &copied
+	}
+
+	heap.values[index] = variable
+
 	return nil
 }
 
-func (heap *Heap) SetVariable(name string, variable *VariableValue) error {
-	var prevVariable = heap.values[name]
+func (heap *Heap) SetVariable(slot ast_node.VariableSlot, variable *VariableValue) error {
+	var scope = heap.getScope(slot.Depth)
 
-	if prevVariable == nil {
+	if scope == nil {
 		return runtime_error.RuntimeError{
 			Message: "Variable not declared",
 		}
 	}
 
-	prevVariable.ValueType = variable.ValueType
-	prevVariable.NumberValue = variable.NumberValue
-	prevVariable.StringValue = variable.StringValue
-	prevVariable.BooleanValue = variable.BooleanValue
-	prevVariable.FunctionValue = variable.FunctionValue
-	prevVariable.NativeFunctionName = variable.NativeFunctionName
-	prevVariable.FunctionClosureHeap = variable.FunctionClosureHeap
+	scope.lock.Lock()
+	defer scope.lock.Unlock()
+
+	if slot.Index >= len(scope.values) || scope.values[slot.Index] == nil {
+		return runtime_error.RuntimeError{
+			Message: "Variable not declared",
+		}
+	}
+
+	var prevVariable = scope.values[slot.Index]
+
+	// Variable keeps its annotated type, so value of other type gets own copy
+	if variable.DeclaredType != prevVariable.DeclaredType {
+		var copied = *variable
+		copied.DeclaredType = prevVariable.DeclaredType
+		variable = &copied
+	}
+
+	// Previous value is replaced, not changed, because it can be still used by evaluated expressions
+	scope.values[slot.Index] = variable
 
 	return nil
 }
 
-func (heap *Heap) GetVariable(name string) *VariableValue {
-	var variable = heap.values[name]
+// Nil, when variable is not declared yet
+func (heap *Heap) GetVariable(slot ast_node.VariableSlot) *VariableValue {
+	var scope = heap.getScope(slot.Depth)
+
+	if scope == nil {
+		return nil
+	}
+
+	scope.lock.RLock()
+	defer scope.lock.RUnlock()
+
+	if slot.Index >= len(scope.values) {
+		return nil
+	}
+
+	return scope.values[slot.Index]
+}
+
+// Values of variables of this scope only, without outer scopes. Not declared variables are nil
+func (heap *Heap) GetValues() []*VariableValue {
+	heap.lock.RLock()
+	defer heap.lock.RUnlock()
+
+	return heap.values
+}
+
+// Heap of outer scope, which is depth levels up
+func (heap *Heap) getScope(depth int) *Heap {
+	var scope = heap
+
+	for ; depth > 0 && scope != nil; depth-- {
+		scope.lock.RLock()
+		var parentHeap = scope.parentHeap
+		scope.lock.RUnlock()
 
-	if variable == nil && heap.parentHeap != nil {
-		return heap.parentHeap.GetVariable(name)
+		scope = parentHeap
 	}
 
-	return variable
+	return scope
 }
 
 func (heap *Heap) SetParentHeap(parent interface{}) {
+	heap.lock.Lock()
+	defer heap.lock.Unlock()
+
 	heap.parentHeap = parent.(*Heap)
 }
 
+// Lowercase type name, visible for scripts
+func GetTypeName(variable *VariableValue) string {
+	return strings.ToLower(variable.Kind.String())
+}
+
 func CastToNumber(variable *VariableValue) (*VariableValue, error) {
-	if variable.ValueType == TYPE_NUMBER {
+	switch variable.Kind {
+	case TYPE_NUMBER:
 		return variable, nil
-	}
+	case TYPE_INTEGER:
+		// Nearest float, big integers lose precision
+		var number, _ = new(big.Float).SetInt(variable.IntegerValue).Float64()
 
-	if variable.ValueType == TYPE_STRING {
+		return CreateNumber(number), nil
+	case TYPE_STRING:
 		var number, numberParsError = strconv.ParseFloat(variable.StringValue, 64)
 
 		if numberParsError != nil {
 			return nil, numberParsError
 		}
 
-		return &VariableValue{
-			ValueType:   TYPE_NUMBER,
-			NumberValue: number,
-		}, nil
+		return CreateNumber(number), nil
+	case TYPE_BOOLEAN:
+		if variable.BooleanValue {
+			return CreateNumber(1), nil
+		}
+
+		return CreateNumber(0), nil
+	case TYPE_UNKNOWN:
+		return CreateNumber(0), nil
 	}
 
-	if variable.ValueType == TYPE_BOOLEAN {
-		if variable.BooleanValue == "true" {
-			return &VariableValue{
-				ValueType:   TYPE_NUMBER,
-				NumberValue: 1,
-			}, nil
-		}
+	return nil, runtime_error.RuntimeError{
+		Message: "Cannot cast variable to number. Type: " + variable.Kind.String(),
+	}
+}
+
+func CastToString(variable *VariableValue) (*VariableValue, error) {
+	switch variable.Kind {
+	case TYPE_STRING:
+		return variable, nil
+	case TYPE_INTEGER:
+		return CreateString(variable.IntegerValue.String()), nil
+	case TYPE_NUMBER:
+		return CreateString(strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", variable.NumberValue), "0"), ".")), nil
+	case TYPE_BOOLEAN:
+		return CreateString(strconv.FormatBool(variable.BooleanValue)), nil
+	case TYPE_UNKNOWN:
+		return CreateString(""), nil
+	}
 
-		return &VariableValue{
-			ValueType:   TYPE_NUMBER,
-			NumberValue: 0,
-		}, nil
+	return nil, runtime_error.RuntimeError{
+		Message: "Cannot cast variable to number. Type: " + variable.Kind.String(),
 	}
+}
+
+// Floats are truncated toward zero. Strings should contain decimal integer
+func CastToInteger(variable *VariableValue) (*VariableValue, error) {
+	switch variable.Kind {
+	case TYPE_INTEGER:
+		return variable, nil
+	case TYPE_NUMBER:
+		if math.IsNaN(variable.NumberValue) || math.IsInf(variable.NumberValue, 0) {
+			return nil, runtime_error.RuntimeError{
+				Message: "Cannot cast not finite number to integer",
+			}
+		}
+
+		var integer, _ = big.NewFloat(variable.NumberValue).Int(nil)
+
+		return CreateInteger(integer), nil
+	case TYPE_STRING:
+		var integer, isValid = new(big.Int).SetString(strings.TrimSpace(variable.StringValue), 10)
+
+		if !isValid {
+			return nil, runtime_error.RuntimeError{
+				Message: "Cannot cast string to integer. Received: " + variable.StringValue,
+			}
+		}
 
-	if variable.ValueType == TYPE_UNKNOWN {
-		return &VariableValue{
-			ValueType:   TYPE_NUMBER,
-			NumberValue: 0,
-		}, nil
+		return CreateInteger(integer), nil
+	case TYPE_BOOLEAN:
+		if variable.BooleanValue {
+			return CreateInteger(big.NewInt(1)), nil
+		}
+
+		return CreateInteger(big.NewInt(0)), nil
+	case TYPE_UNKNOWN:
+		return CreateInteger(big.NewInt(0)), nil
 	}
 
 	return nil, runtime_error.RuntimeError{
-		Message: "Cannot cast variable to number. Type: " + variable.ValueType,
+		Message: "Cannot cast variable to integer. Type: " + variable.Kind.String(),
 	}
 }
 
-func CastToString(variable *VariableValue) (*VariableValue, error) {
-	if variable.ValueType == TYPE_STRING {
+// Values of other kinds are truthy
+func CastToBoolean(variable *VariableValue) (*VariableValue, error) {
+	switch variable.Kind {
+	case TYPE_BOOLEAN:
 		return variable, nil
+	case TYPE_NUMBER:
+		return CreateBoolean(variable.NumberValue != 0), nil
+	case TYPE_INTEGER:
+		return CreateBoolean(variable.IntegerValue.Sign() != 0), nil
+	case TYPE_STRING:
+		return CreateBoolean(variable.StringValue != ""), nil
+	case TYPE_UNKNOWN:
+		return falseValue, nil
+	}
+
+	return trueValue, nil
+}
+
+// Truthiness of value, like condition of loop
+func IsTruthy(variable *VariableValue) bool {
+	var boolean, _ = CastToBoolean(variable)
+
+	return boolean.BooleanValue
+}
+
+func CreateNumber(value float64) *VariableValue {
+	return &VariableValue{
+		Kind:        TYPE_NUMBER,
+		NumberValue: value,
 	}
+}
 
-	if variable.ValueType == TYPE_NUMBER {
-		return &VariableValue{
-			ValueType:   TYPE_STRING,
-			StringValue: strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", variable.NumberValue), "0"), "."),
-		}, nil
+func CreateString(value string) *VariableValue {
+	return &VariableValue{
+		Kind:        TYPE_STRING,
+		StringValue: value,
 	}
+}
 
-	if variable.ValueType == TYPE_BOOLEAN {
-		return &VariableValue{
-			ValueType:   TYPE_STRING,
-			StringValue: variable.BooleanValue,
-		}, nil
+func CreateInteger(value *big.Int) *VariableValue {
+	return &VariableValue{
+		Kind:         TYPE_INTEGER,
+		IntegerValue: value,
 	}
+}
 
-	if variable.ValueType == TYPE_UNKNOWN {
-		return &VariableValue{
-			ValueType:   TYPE_STRING,
-			StringValue: "",
-		}, nil
+func CreateBoolean(value bool) *VariableValue {
+	if value {
+		return trueValue
 	}
 
-	return nil, runtime_error.RuntimeError{
-		Message: "Cannot cast variable to number. Type: " + variable.ValueType,
+	return falseValue
+}
+
+// Build in function is the same for every read. Method gets new copy of receiver
+// on every read, so methods are equal, when they are bound to equal receivers
+func isSameNativeFunction(first *NativeFunction, second *NativeFunction) bool {
+	if first.Name != second.Name || first.Closure != second.Closure {
+		return false
 	}
+
+	if first.Receiver == nil || second.Receiver == nil {
+		return first.Receiver == second.Receiver
+	}
+
+	return IsEqual(first.Receiver, second.Receiver)
+}
+
+// Strict equality, values of different types never equal.
+// Values of reference kinds are equal, when they refer to the same object
+func IsEqual(first *VariableValue, second *VariableValue) bool {
+	if first.Kind != second.Kind {
+		return false
+	}
+
+	switch first.Kind {
+	case TYPE_NUMBER:
+		return first.NumberValue == second.NumberValue
+	case TYPE_INTEGER:
+		return first.IntegerValue.Cmp(second.IntegerValue) == 0
+	case TYPE_STRING:
+		return first.StringValue == second.StringValue
+	case TYPE_BOOLEAN:
+		return first.BooleanValue == second.BooleanValue
+	case TYPE_UNKNOWN:
+		return true
+	case TYPE_NATIVE_FUNCTION:
+		return isSameNativeFunction(first.NativeFunction(), second.NativeFunction())
+	}
+
+	return first.Reference == second.Reference
 }
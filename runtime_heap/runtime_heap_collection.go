@@ -0,0 +1,218 @@
+package runtime_heap
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/VadimZvf/golang/ast_node"
+)
+
+// Key and value of map. Set keeps value as key and value
+type CollectionEntry struct {
+	Key   *VariableValue
+	Value *VariableValue
+}
+
+// Entries of map or set in insertion order. Numbers, integers, strings and booleans
+// are compared by value, functions and other values by identity
+type Collection struct {
+	entries []*CollectionEntry
+	indexes map[collectionKey]*CollectionEntry
+	lock    *sync.RWMutex
+}
+
+type collectionKey struct {
+	kind     ValueKind
+	value    string
+	identity interface{}
+}
+
+// Identity of native function, receiver is compared by its key
+type nativeFunctionKey struct {
+	name     string
+	closure  interface{}
+	receiver interface{}
+}
+
+func CreateCollection() *Collection {
+	return &Collection{
+		indexes: map[collectionKey]*CollectionEntry{},
+		lock:    &sync.RWMutex{},
+	}
+}
+
+func getCollectionKey(key *VariableValue) collectionKey {
+	switch key.Kind {
+	case TYPE_NUMBER:
+		// Zero and negative zero are the same key
+		if key.NumberValue == 0 {
+			return collectionKey{kind: TYPE_NUMBER, value: "0"}
+		}
+
+		return collectionKey{kind: TYPE_NUMBER, value: strconv.FormatFloat(key.NumberValue, 'g', -1, 64)}
+	case TYPE_INTEGER:
+		return collectionKey{kind: TYPE_INTEGER, value: key.IntegerValue.String()}
+	case TYPE_STRING:
+		return collectionKey{kind: TYPE_STRING, value: key.StringValue}
+	case TYPE_BOOLEAN:
+		return collectionKey{kind: TYPE_BOOLEAN, value: strconv.FormatBool(key.BooleanValue)}
+	case TYPE_UNKNOWN:
+		return collectionKey{kind: TYPE_UNKNOWN}
+	case TYPE_NATIVE_FUNCTION:
+		// Compared like by IsEqual
+		var native = key.NativeFunction()
+		var nativeKey = nativeFunctionKey{name: native.Name, closure: native.Closure}
+
+		if native.Receiver != nil {
+			nativeKey.receiver = getCollectionKey(native.Receiver)
+		}
+
+		return collectionKey{kind: TYPE_NATIVE_FUNCTION, identity: nativeKey}
+	}
+
+	return collectionKey{kind: key.Kind, identity: key.Reference}
+}
+
+func (collection *Collection) Get(key *VariableValue) (*VariableValue, bool) {
+	collection.lock.RLock()
+	defer collection.lock.RUnlock()
+
+	var entry = collection.indexes[getCollectionKey(key)]
+
+	if entry == nil {
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+func (collection *Collection) Has(key *VariableValue) bool {
+	var _, isFound = collection.Get(key)
+
+	return isFound
+}
+
+// Replaces value of existing key without changing its position
+func (collection *Collection) Set(key *VariableValue, value *VariableValue) {
+	collection.lock.Lock()
+	defer collection.lock.Unlock()
+
+	var index = getCollectionKey(key)
+	var entry = collection.indexes[index]
+
+	if entry != nil {
+		entry.Value = value
+		return
+	}
+
+	entry = &CollectionEntry{Key: key, Value: value}
+	collection.indexes[index] = entry
+	collection.entries = append(collection.entries, entry)
+}
+
+func (collection *Collection) Delete(key *VariableValue) bool {
+	collection.lock.Lock()
+	defer collection.lock.Unlock()
+
+	var index = getCollectionKey(key)
+	var entry = collection.indexes[index]
+
+	if entry == nil {
+		return false
+	}
+
+	delete(collection.indexes, index)
+
+	for position, existingEntry := range collection.entries {
+		if existingEntry == entry {
+			collection.entries = append(collection.entries[:position], collection.entries[position+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+func (collection *Collection) Size() int {
+	collection.lock.RLock()
+	defer collection.lock.RUnlock()
+
+	return len(collection.entries)
+}
+
+// Copy of entries, so collection can be changed while entries are iterated
+func (collection *Collection) Entries() []*CollectionEntry {
+	collection.lock.RLock()
+	defer collection.lock.RUnlock()
+
+	var entries = make([]*CollectionEntry, len(collection.entries))
+	copy(entries, collection.entries)
+
+	return entries
+}
+
+// Text of map, set or entry for printing, like: Map(1) {"key" => 1}
+func InspectCollection(variable *VariableValue) string {
+	return inspect(variable, map[*Collection]bool{})
+}
+
+func inspect(variable *VariableValue, visited map[*Collection]bool) string {
+	switch variable.Kind {
+	case TYPE_STRING:
+		return strconv.Quote(variable.StringValue)
+	case TYPE_INTEGER:
+		return variable.IntegerValue.String() + "n"
+	case TYPE_NUMBER, TYPE_BOOLEAN:
+		var text, _ = CastToString(variable)
+
+		return text.StringValue
+	case TYPE_FUNCTION:
+		var functionName = ast_node.GetFunctionNameParam(variable.Function().Node)
+
+		if functionName == nil {
+			return "function"
+		}
+
+		return "function " + functionName.Value
+	case TYPE_ENTRY:
+		return inspect(variable.Entry().Key, visited) + " => " + inspect(variable.Entry().Value, visited)
+	case TYPE_ENUM:
+		return inspectEnum(variable.Enum(), visited)
+	case TYPE_ENUM_MEMBER:
+		return variable.EnumMember().Enum.Name + "." + variable.EnumMember().Name
+	case TYPE_MAP, TYPE_SET:
+		var collection = variable.Collection()
+
+		if visited[collection] {
+			return "[Circular]"
+		}
+
+		visited[collection] = true
+		defer delete(visited, collection)
+
+		var items = []string{}
+
+		for _, entry := range collection.Entries() {
+			if variable.Kind == TYPE_SET {
+				items = append(items, inspect(entry.Key, visited))
+			} else {
+				items = append(items, inspect(entry.Key, visited)+" => "+inspect(entry.Value, visited))
+			}
+		}
+
+		var name = "Map"
+
+		if variable.Kind == TYPE_SET {
+			name = "Set"
+		}
+
+		if len(items) == 0 {
+			return name + "(0) {}"
+		}
+
+		return name + "(" + strconv.Itoa(len(items)) + ") {" + strings.Join(items, ", ") + "}"
+	}
+
+	return GetTypeName(variable)
+}
@@ -0,0 +1,73 @@
+package runtime_heap
+
+import "strings"
+
+// Immutable namespace of named values, declared by "enum"
+type Enum struct {
+	Name    string
+	Members []*EnumMember
+}
+
+// Named value of enum. Members are compared by identity, not by value
+type EnumMember struct {
+	Enum  *Enum
+	Name  string
+	Value *VariableValue
+}
+
+func CreateEnum(name string) *Enum {
+	return &Enum{Name: name}
+}
+
+// Adds member and returns it as value
+func (enum *Enum) AddMember(name string, value *VariableValue) *VariableValue {
+	var member = &EnumMember{
+		Enum:  enum,
+		Name:  name,
+		Value: value,
+	}
+
+	enum.Members = append(enum.Members, member)
+
+	return CreateEnumMember(member)
+}
+
+func (enum *Enum) GetMember(name string) *EnumMember {
+	for _, member := range enum.Members {
+		if member.Name == name {
+			return member
+		}
+	}
+
+	return nil
+}
+
+// Reverse lookup, finds first member with equal value
+func (enum *Enum) FindMember(value *VariableValue) *EnumMember {
+	for _, member := range enum.Members {
+		if IsEqual(member.Value, value) {
+			return member
+		}
+	}
+
+	return nil
+}
+
+func CreateEnumMember(member *EnumMember) *VariableValue {
+	return CreateReference(TYPE_ENUM_MEMBER, member)
+}
+
+// Text of enum, like "enum Color {Red = 0, Green = 1}", or of member, like "Color.Red"
+func InspectEnum(variable *VariableValue) string {
+	return inspect(variable, map[*Collection]bool{})
+}
+
+func inspectEnum(enum *Enum, visited map[*Collection]bool) string {
+	var members = []string{}
+
+	for _, member := range enum.Members {
+		members = append(members, member.Name+" = "+inspect(member.Value, visited))
+	}
+
+	return "enum " + enum.Name + " {" + strings.Join(members, ", ") + "}"
+}
@@ -0,0 +1,264 @@
+package runtime_heap
+
+import (
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/VadimZvf/golang/ast_node"
+)
+
+func TestHeapConcurrentAccess(t *testing.T) {
+	var parent = CreateHeap(1)
+	var heap = CreateHeap(0)
+	var shared = ast_node.VariableSlot{Depth: 1, Index: 0}
+	heap.SetParentHeap(&parent)
+	parent.CreateVariable(shared.Index)
+
+	var group sync.WaitGroup
+
+	for worker := 0; worker < 8; worker++ {
+		group.Add(1)
+
+		go func(worker int) {
+			defer group.Done()
+
+			var local = ast_node.VariableSlot{Index: worker}
+			heap.CreateVariable(local.Index)
+
+			for index := 0; index < 100; index++ {
+				heap.SetVariable(local, &VariableValue{Kind: TYPE_NUMBER, NumberValue: float64(index)})
+				heap.SetVariable(shared, &VariableValue{Kind: TYPE_NUMBER, NumberValue: float64(index)})
+				heap.GetVariable(shared)
+			}
+		}(worker)
+	}
+
+	group.Wait()
+
+	for worker := 0; worker < 8; worker++ {
+		var variable = heap.GetVariable(ast_node.VariableSlot{Index: worker})
+
+		if variable == nil || variable.NumberValue != 99 {
+			t.Errorf("Should keep value of every worker, but received: %v", variable)
+		}
+	}
+
+	if parent.GetVariable(ast_node.VariableSlot{Index: shared.Index}).Kind != TYPE_NUMBER {
+		t.Errorf("Should set variable of parent heap")
+	}
+}
+
+func TestHeapSlots(t *testing.T) {
+	var heap = CreateHeap(1)
+	var slot = ast_node.VariableSlot{Index: 3}
+
+	if heap.GetVariable(slot) != nil {
+		t.Errorf("Variable should not be defined before declaration")
+	}
+
+	if heap.SetVariable(slot, CreateBoolean(true)) == nil {
+		t.Errorf("Should fail to set variable, which is not declared")
+	}
+
+	heap.CreateVariable(slot.Index)
+
+	if heap.CreateVariable(slot.Index) == nil {
+		t.Errorf("Should fail to declare variable twice")
+	}
+
+	heap.SetVariable(slot, CreateBoolean(true))
+
+	if !heap.GetVariable(slot).BooleanValue {
+		t.Errorf("Should set variable in slot out of initial size")
+	}
+
+	if heap.GetVariable(ast_node.VariableSlot{Depth: 1, Index: 0}) != nil {
+		t.Errorf("Heap without parent should not have outer variables")
+	}
+}
+
+func TestIntegerCasts(t *testing.T) {
+	var integer, _ = new(big.Int).SetString("123456789012345678901", 10)
+	var value = CreateInteger(integer)
+
+	var text, textErr = CastToString(value)
+
+	if textErr != nil || text.StringValue != "123456789012345678901" {
+		t.Errorf("Should cast integer to string without precision loss, but received: %v", text)
+	}
+
+	var number, numberErr = CastToNumber(CreateInteger(big.NewInt(42)))
+
+	if numberErr != nil || number.Kind != TYPE_NUMBER || number.NumberValue != 42 {
+		t.Errorf("Should cast integer to number, but received: %v", number)
+	}
+
+	var truncated, truncatedErr = CastToInteger(&VariableValue{Kind: TYPE_NUMBER, NumberValue: -2.7})
+
+	if truncatedErr != nil || truncated.IntegerValue.Int64() != -2 {
+		t.Errorf("Should truncate float toward zero, but received: %v", truncated)
+	}
+
+	var _, infinityErr = CastToInteger(&VariableValue{Kind: TYPE_NUMBER, NumberValue: math.Inf(1)})
+
+	if infinityErr == nil {
+		t.Errorf("Should fail to cast infinity to integer")
+	}
+}
+
+func TestCollectionKeys(t *testing.T) {
+	var collection = CreateCollection()
+	var number = &VariableValue{Kind: TYPE_NUMBER, NumberValue: 0}
+	var negativeZero = &VariableValue{Kind: TYPE_NUMBER, NumberValue: math.Copysign(0, -1)}
+	var notNumber = &VariableValue{Kind: TYPE_NUMBER, NumberValue: math.NaN()}
+
+	collection.Set(number, CreateBoolean(true))
+	collection.Set(notNumber, CreateBoolean(true))
+
+	if !collection.Has(negativeZero) {
+		t.Errorf("Zero and negative zero should be the same key")
+	}
+
+	if !collection.Has(&VariableValue{Kind: TYPE_NUMBER, NumberValue: math.NaN()}) {
+		t.Errorf("NaN should be found by NaN key")
+	}
+
+	if collection.Has(&VariableValue{Kind: TYPE_STRING, StringValue: "0"}) {
+		t.Errorf("String should not be equal to number key")
+	}
+
+	collection.Delete(number)
+
+	if collection.Size() != 1 || collection.Entries()[0].Key != notNumber {
+		t.Errorf("Should delete entry by key")
+	}
+}
+
+func TestReferenceKinds(t *testing.T) {
+	var first = CreateReference(TYPE_MAP, CreateCollection())
+	var copied = *first
+
+	copied.Collection().Set(CreateString("key"), CreateNumber(1))
+
+	if first.Collection().Size() != 1 || !IsEqual(first, &copied) {
+		t.Errorf("Copy of map should refer to the same collection")
+	}
+
+	if IsEqual(first, CreateReference(TYPE_MAP, CreateCollection())) {
+		t.Errorf("Different collections should not be equal")
+	}
+
+	if first.Function() != nil || first.Generator() != nil {
+		t.Errorf("Map should not be function or generator")
+	}
+
+	var print = CreateNativeFunction("print", nil, nil)
+
+	if !IsEqual(print, CreateNativeFunction("print", nil, nil)) || IsEqual(print, CreateNativeFunction("now", nil, nil)) {
+		t.Errorf("Build in functions should be equal by name")
+	}
+
+	// Every read of method copies receiver
+	var firstTrim = CreateNativeFunction("trim", CreateString(" a "), nil)
+	var secondTrim = CreateNativeFunction("trim", CreateString(" a "), nil)
+
+	if !IsEqual(firstTrim, secondTrim) || IsEqual(firstTrim, CreateNativeFunction("trim", CreateString("a"), nil)) {
+		t.Errorf("Methods should be equal by receiver value")
+	}
+
+	var collection = CreateCollection()
+	collection.Set(firstTrim, CreateBoolean(true))
+
+	if !collection.Has(secondTrim) {
+		t.Errorf("Methods bound to equal receivers should be the same key")
+	}
+}
+
+func TestSetVariableKeepsDeclaredType(t *testing.T) {
+	var heap = CreateHeap(1)
+	var slot = ast_node.VariableSlot{Index: 0}
+	heap.CreateVariable(slot.Index)
+	heap.GetVariable(slot).DeclaredType = "boolean"
+
+	heap.SetVariable(slot, CreateBoolean(true))
+	heap.GetVariable(slot).DeclaredType = "boolean"
+	heap.SetVariable(slot, CreateBoolean(false))
+
+	var variable = heap.GetVariable(slot)
+
+	if variable.Kind != TYPE_BOOLEAN || variable.BooleanValue || variable.DeclaredType != "boolean" {
+		t.Errorf("Should change value and keep declared type, but received: %v", variable)
+	}
+
+	if !CreateBoolean(true).BooleanValue || CreateBoolean(true).DeclaredType != "" {
+		t.Errorf("Assignment should not change shared boolean value")
+	}
+}
+
+func TestSetVariableReplacesValue(t *testing.T) {
+	var heap = CreateHeap(1)
+	var slot = ast_node.VariableSlot{Index: 0}
+	heap.CreateVariable(slot.Index)
+	heap.SetVariable(slot, CreateNumber(1))
+
+	var previous = heap.GetVariable(slot)
+	heap.SetVariable(slot, CreateNumber(2))
+
+	if previous.NumberValue != 1 || heap.GetVariable(slot).NumberValue != 2 {
+		t.Errorf("Assignment should not change previous value, but received: %v", previous)
+	}
+}
+
+func TestDefineVariable(t *testing.T) {
+	var heap = CreateHeap(0)
+	var slot = ast_node.VariableSlot{Index: 1}
+	var value = CreateNumber(1)
+	value.DeclaredType = "number"
+
+	if heap.DefineVariable(slot.Index, value) != nil {
+		t.Errorf("Should define variable out of heap size")
+	}
+
+	if heap.DefineVariable(slot.Index, CreateNumber(2)) == nil {
+		t.Errorf("Should not define variable twice")
+	}
+
+	var variable = heap.GetVariable(slot)
+
+	if variable.NumberValue != 1 || variable.DeclaredType != "" || value.DeclaredType != "number" {
+		t.Errorf("Should store value without declared type of other variable, but received: %v", variable)
+	}
+}
+
+func TestKindNames(t *testing.T) {
+	if GetTypeName(CreateNativeFunction("print", nil, nil)) != "native_function" || GetTypeName(CreateUnknown()) != "unknown" {
+		t.Errorf("Should name kinds in lowercase")
+	}
+}
+
+func BenchmarkSetVariable(b *testing.B) {
+	var heap = CreateHeap(1)
+	var slot = ast_node.VariableSlot{Index: 0}
+	var value = CreateNumber(1)
+	heap.CreateVariable(slot.Index)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		heap.SetVariable(slot, value)
+	}
+}
+
+func BenchmarkCastToBoolean(b *testing.B) {
+	var values = []*VariableValue{CreateNumber(0), CreateString("text"), CreateUnknown(), CreateBoolean(true)}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for _, value := range values {
+			CastToBoolean(value)
+		}
+	}
+}
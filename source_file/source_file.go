@@ -3,12 +3,11 @@ package source_file
 import (
 	"fmt"
 	"os"
-	"unicode/utf8"
 )
 
+// File is read by chunks of tokenizer buffer, without own buffering
 type Source struct {
-	file   *os.File
-	buffer []byte
+	file *os.File
 }
 
 func check(e error) {
@@ -25,7 +24,6 @@ func GetSource(filePath string) Source {
 
 	return Source{
 		file,
-		make([]byte, 1),
 	}
 }
 
@@ -33,10 +31,6 @@ func (source *Source) Close() {
 	source.file.Close()
 }
 
-func (source Source) NextSymbol() (rune, bool) {
-	n1, err := source.file.Read(source.buffer)
-
-	symbol, _ := utf8.DecodeRune(source.buffer[:n1])
-
-	return symbol, err != nil
+func (source Source) Read(bytes []byte) (int, error) {
+	return source.file.Read(bytes)
 }
@@ -1,12 +1,23 @@
 package source_mock
 
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Source, which gives the same symbol on every read, until it ends
 type SimpleSourceMock struct {
 	IsEnd           bool
 	NextSymbolValue rune
 }
 
-func (source *SimpleSourceMock) NextSymbol() (rune, bool) {
-	return source.NextSymbolValue, source.IsEnd
+func (source *SimpleSourceMock) Read(bytes []byte) (int, error) {
+	if source.IsEnd {
+		return 0, io.EOF
+	}
+
+	return utf8.EncodeRune(bytes, source.NextSymbolValue), nil
 }
 
 func GetSimpleSource() *SimpleSourceMock {
@@ -14,24 +25,13 @@ func GetSimpleSource() *SimpleSourceMock {
 }
 
 type SourceMock struct {
-	IsEnd    bool
-	FullText string
-	position int
+	reader *strings.Reader
 }
 
-func (source *SourceMock) NextSymbol() (rune, bool) {
-	source.position += 1
-
-	if source.position >= len(source.FullText) {
-		return rune(0), true
-	}
-
-	var symbol = source.FullText[source.position]
-	var a = rune(symbol)
-
-	return a, false
+func (source *SourceMock) Read(bytes []byte) (int, error) {
+	return source.reader.Read(bytes)
 }
 
 func GetSourceMock(code string) *SourceMock {
-	return &SourceMock{false, code, -1}
+	return &SourceMock{strings.NewReader(code)}
 }
@@ -1,24 +1,15 @@
 package source_string
 
+import "strings"
+
 type SourceString struct {
-	IsEnd    bool
-	FullText string
-	position int
+	reader *strings.Reader
 }
 
-func (source *SourceString) NextSymbol() (rune, bool) {
-	source.position += 1
-
-	if source.position >= len(source.FullText) {
-		return rune(0), true
-	}
-
-	var symbol = source.FullText[source.position]
-	var a = rune(symbol)
-
-	return a, false
+func (source *SourceString) Read(bytes []byte) (int, error) {
+	return source.reader.Read(bytes)
 }
 
 func GetSource(codeText string) *SourceString {
-	return &SourceString{false, codeText, -1}
+	return &SourceString{strings.NewReader(codeText)}
 }
@@ -11,9 +11,9 @@ func CreateStdout() Stdout {
 }
 
 func (stdout *Stdout) Print(line string) {
-	color.New(color.Reset).Printf(line)
+	color.New(color.Reset).Print(line)
 }
 
 func (stdout *Stdout) PrintError(symbol string) {
-	color.New(color.FgHiRed).Printf(symbol)
+	color.New(color.FgHiRed).Print(symbol)
 }
@@ -35,6 +35,95 @@ go test
 cd ..
 echo ""
 
+echo "Match declaration token"
+echo "======================"
+cd token_match
+go test
+cd ..
+echo ""
+
+echo "If declaration token"
+echo "======================"
+cd token_if
+go test
+cd ..
+echo ""
+
+echo "Typeof token"
+echo "======================"
+cd token_typeof
+go test
+cd ..
+echo ""
+
+echo "While declaration token"
+echo "======================"
+cd token_while
+go test
+cd ..
+echo ""
+
+echo "For declaration token"
+echo "======================"
+cd token_for
+go test
+cd ..
+echo ""
+
+echo "Break declaration token"
+echo "======================"
+cd token_break
+go test
+cd ..
+echo ""
+
+echo "Continue declaration token"
+echo "======================"
+cd token_continue
+go test
+cd ..
+echo ""
+
+echo "Yield declaration token"
+echo "======================"
+cd token_yield
+go test
+cd ..
+echo ""
+
+echo "Async declaration token"
+echo "======================"
+cd token_async
+go test
+cd ..
+echo ""
+
+echo "Await token"
+echo "======================"
+cd token_await
+go test
+cd ..
+echo ""
+
+echo "Spawn declaration token"
+echo "======================"
+cd token_spawn
+go test
+cd ..
+echo ""
+echo "Enum declaration token"
+echo "======================"
+cd token_enum
+go test
+cd ..
+echo ""
+echo "Defer declaration token"
+echo "======================"
+cd token_defer
+go test
+cd ..
+echo ""
+
 echo "Variable declaration token"
 echo "======================"
 cd token_variable_declaration
@@ -77,6 +166,41 @@ go test
 cd ..
 echo ""
 
+echo "Type checker"
+echo "======================"
+cd type_checker
+go test
+cd ..
+echo ""
+
+echo "AST resolver"
+echo "======================"
+cd ast_resolver
+go test
+cd ..
+echo ""
+
+echo "Runtime heap"
+echo "======================"
+cd runtime_heap
+go test
+cd ..
+echo ""
+
+echo "Runtime clock"
+echo "======================"
+cd runtime_clock
+go test
+cd ..
+echo ""
+
+echo "Runtime bytecode"
+echo "======================"
+cd runtime_bytecode
+go test
+cd ..
+echo ""
+
 echo "Runtime"
 echo "======================"
 cd runtime
@@ -1,5 +1,7 @@
 package token
 
+import "unicode"
+
 type TokenParam struct {
 	Name          string
 	Value         string
@@ -20,12 +22,14 @@ type Token struct {
 type IBuffer interface {
 	GetValue() (value string)
 	GetSymbol() (symbol rune)
+	LookNextSymbol() (symbol rune)
 	GetPosition() int
 	GetIsEnd() bool
 	Next()
 	TrimNext()
 	AddSymbol()
 	IsStartsWithWord(value string) bool
+	IsStartsWith(value string) bool
 	Eat(length int)
 	Clear()
 }
@@ -49,6 +53,24 @@ func createSymbolProcessor(code string, symbol rune) TokenProcessor {
 	}
 }
 
+func createOperatorProcessor(code string, operator string) TokenProcessor {
+	return func(buffer IBuffer) (foundToken Token, isFoundToken bool, err error) {
+		if !buffer.IsStartsWith(operator) {
+			return Token{}, false, nil
+		}
+
+		var position = buffer.GetPosition()
+		buffer.Eat(len(operator))
+
+		return Token{
+			Code:          code,
+			StartPosition: position,
+			EndPosition:   position + len(operator) - 1,
+			Value:         operator,
+		}, true, nil
+	}
+}
+
 var ASSIGNMENT = "ASSIGNMENT"
 var AssignmentProcessor = createSymbolProcessor(ASSIGNMENT, '=')
 
@@ -64,6 +86,12 @@ var OpenExpressionProcessor = createSymbolProcessor(OPEN_EXPRESSION, '(')
 var CLOSE_EXPRESSION = "CLOSE_EXPRESSION"
 var CloseExpressionProcessor = createSymbolProcessor(CLOSE_EXPRESSION, ')')
 
+var OPEN_INDEX = "OPEN_INDEX"
+var OpenIndexProcessor = createSymbolProcessor(OPEN_INDEX, '[')
+
+var CLOSE_INDEX = "CLOSE_INDEX"
+var CloseIndexProcessor = createSymbolProcessor(CLOSE_INDEX, ']')
+
 var ADD = "ADD"
 var AddProcessor = createSymbolProcessor(ADD, '+')
 
@@ -76,12 +104,63 @@ var SlashProcessor = createSymbolProcessor(SLASH, '/')
 var ASTERISK = "ASTERISK"
 var AsteriskProcessor = createSymbolProcessor(ASTERISK, '*')
 
+var PERCENT = "PERCENT"
+var PercentProcessor = createSymbolProcessor(PERCENT, '%')
+
 var END_LINE = "END_LINE"
 var EndLineProcessor = createSymbolProcessor(END_LINE, ';')
 
 var COMMA = "COMMA"
 var CommaProcessor = createSymbolProcessor(COMMA, ',')
 
+var COLON = "COLON"
+var ColonProcessor = createSymbolProcessor(COLON, ':')
+
+var EQUAL = "EQUAL"
+var EqualProcessor = createOperatorProcessor(EQUAL, "==")
+
+var NOT_EQUAL = "NOT_EQUAL"
+var NotEqualProcessor = createOperatorProcessor(NOT_EQUAL, "!=")
+
+var GREATER_OR_EQUAL = "GREATER_OR_EQUAL"
+var GreaterOrEqualProcessor = createOperatorProcessor(GREATER_OR_EQUAL, ">=")
+
+var LESS_OR_EQUAL = "LESS_OR_EQUAL"
+var LessOrEqualProcessor = createOperatorProcessor(LESS_OR_EQUAL, "<=")
+
+var GREATER = "GREATER"
+var GreaterProcessor = createSymbolProcessor(GREATER, '>')
+
+var LESS = "LESS"
+var LessProcessor = createSymbolProcessor(LESS, '<')
+
+var ARROW = "ARROW"
+var ArrowProcessor = createOperatorProcessor(ARROW, "=>")
+
+var PIPE = "PIPE"
+var PipeProcessor = createOperatorProcessor(PIPE, "|>")
+
+var VERTICAL_BAR = "VERTICAL_BAR"
+var VerticalBarProcessor = createSymbolProcessor(VERTICAL_BAR, '|')
+
+var AMPERSAND = "AMPERSAND"
+var AmpersandProcessor = createSymbolProcessor(AMPERSAND, '&')
+
+var CARET = "CARET"
+var CaretProcessor = createSymbolProcessor(CARET, '^')
+
+var TILDE = "TILDE"
+var TildeProcessor = createSymbolProcessor(TILDE, '~')
+
+var SHIFT_LEFT = "SHIFT_LEFT"
+var ShiftLeftProcessor = createOperatorProcessor(SHIFT_LEFT, "<<")
+
+var SHIFT_RIGHT = "SHIFT_RIGHT"
+var ShiftRightProcessor = createOperatorProcessor(SHIFT_RIGHT, ">>")
+
+var UNSIGNED_SHIFT_RIGHT = "UNSIGNED_SHIFT_RIGHT"
+var UnsignedShiftRightProcessor = createOperatorProcessor(UNSIGNED_SHIFT_RIGHT, ">>>")
+
 var PROGRAMM = "PROGRAMM"
 var KEY_WORD = "KEY_WORD"
 
@@ -92,7 +171,7 @@ func IsNumber(symbol rune) bool {
 }
 
 func IsLetter(symbol rune) bool {
-	return (symbol >= 'a' && symbol <= 'z') || (symbol >= 'A' && symbol <= 'Z')
+	return unicode.IsLetter(symbol)
 }
 
 func IsKeyWordSymbol(symbol rune) bool {
@@ -122,3 +201,23 @@ func ReadWord(buffer IBuffer) TokenParam {
 		EndPosition:   buffer.GetPosition() - 1,
 	}
 }
+
+// Reads optional type annotation after name, like ": number".
+// Value of found annotation is empty, when type name is missed
+func ReadTypeAnnotation(buffer IBuffer) (annotation TokenParam, isFound bool) {
+	buffer.Clear()
+	buffer.TrimNext()
+
+	if buffer.GetSymbol() != ':' {
+		return TokenParam{}, false
+	}
+
+	buffer.Next()
+	buffer.TrimNext()
+	buffer.Clear()
+
+	annotation = ReadWord(buffer)
+	buffer.Clear()
+
+	return annotation, true
+}
@@ -0,0 +1,25 @@
+package token_async
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var ASYNC_DECLARATION = "ASYNC_DECLARATION"
+var AsyncProcessor token.TokenProcessor = proccess
+var asyncName = "async"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(asyncName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(asyncName))
+
+	return token.Token{
+		Code:          ASYNC_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
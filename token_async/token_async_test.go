@@ -0,0 +1,42 @@
+package token_async
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func TestAsyncShouldNotBeFound(t *testing.T) {
+	var src = source_mock.GetSourceMock(`asyncfoo`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := AsyncProcessor(&buffer)
+
+	if isFound {
+		t.Errorf("Should't find token")
+	}
+
+	if token.Code != "" {
+		t.Errorf("Should't find token")
+	}
+}
+
+func TestAsync(t *testing.T) {
+	var src = source_mock.GetSourceMock(`async`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := AsyncProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if token.Code != ASYNC_DECLARATION {
+		t.Errorf("Should find token")
+	}
+
+	if token.StartPosition != 0 || token.EndPosition != 4 {
+		t.Errorf("Should save position. But receive start: %d end: %d", token.StartPosition, token.EndPosition)
+	}
+}
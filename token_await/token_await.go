@@ -0,0 +1,26 @@
+package token_await
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var AWAIT = "AWAIT"
+var AwaitProcessor token.TokenProcessor = proccess
+var awaitName = "await"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(awaitName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(awaitName))
+
+	return token.Token{
+		Code:          AWAIT,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+		Value:         awaitName,
+	}, true, nil
+}
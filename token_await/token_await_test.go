@@ -0,0 +1,42 @@
+package token_await
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func TestAwaitShouldNotBeFound(t *testing.T) {
+	var src = source_mock.GetSourceMock(`awaitfoo`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := AwaitProcessor(&buffer)
+
+	if isFound {
+		t.Errorf("Should't find token")
+	}
+
+	if token.Code != "" {
+		t.Errorf("Should't find token")
+	}
+}
+
+func TestAwait(t *testing.T) {
+	var src = source_mock.GetSourceMock(`await`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := AwaitProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if token.Code != AWAIT {
+		t.Errorf("Should find token")
+	}
+
+	if token.StartPosition != 0 || token.EndPosition != 4 {
+		t.Errorf("Should save position. But receive start: %d end: %d", token.StartPosition, token.EndPosition)
+	}
+}
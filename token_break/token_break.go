@@ -0,0 +1,25 @@
+package token_break
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var BREAK_DECLARATION = "BREAK_DECLARATION"
+var BreakProcessor token.TokenProcessor = proccess
+var breakName = "break"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(breakName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(breakName))
+
+	return token.Token{
+		Code:          BREAK_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
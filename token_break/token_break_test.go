@@ -0,0 +1,42 @@
+package token_break
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func TestBreakShouldNotBeFound(t *testing.T) {
+	var src = source_mock.GetSourceMock(`breakfoo`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := BreakProcessor(&buffer)
+
+	if isFound {
+		t.Errorf("Should't find token")
+	}
+
+	if token.Code != "" {
+		t.Errorf("Should't find token")
+	}
+}
+
+func TestBreak(t *testing.T) {
+	var src = source_mock.GetSourceMock(`break`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := BreakProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if token.Code != BREAK_DECLARATION {
+		t.Errorf("Should find token")
+	}
+
+	if token.StartPosition != 0 || token.EndPosition != 4 {
+		t.Errorf("Should save position. But receive start: %d end: %d", token.StartPosition, token.EndPosition)
+	}
+}
@@ -0,0 +1,25 @@
+package token_continue
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var CONTINUE_DECLARATION = "CONTINUE_DECLARATION"
+var ContinueProcessor token.TokenProcessor = proccess
+var continueName = "continue"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(continueName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(continueName))
+
+	return token.Token{
+		Code:          CONTINUE_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
@@ -0,0 +1,42 @@
+package token_continue
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func TestContinueShouldNotBeFound(t *testing.T) {
+	var src = source_mock.GetSourceMock(`continuefoo`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := ContinueProcessor(&buffer)
+
+	if isFound {
+		t.Errorf("Should't find token")
+	}
+
+	if token.Code != "" {
+		t.Errorf("Should't find token")
+	}
+}
+
+func TestContinue(t *testing.T) {
+	var src = source_mock.GetSourceMock(`continue`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := ContinueProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if token.Code != CONTINUE_DECLARATION {
+		t.Errorf("Should find token")
+	}
+
+	if token.StartPosition != 0 || token.EndPosition != 7 {
+		t.Errorf("Should save position. But receive start: %d end: %d", token.StartPosition, token.EndPosition)
+	}
+}
@@ -0,0 +1,25 @@
+package token_defer
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var DEFER_DECLARATION = "DEFER_DECLARATION"
+var DeferProcessor token.TokenProcessor = proccess
+var deferName = "defer"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(deferName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(deferName))
+
+	return token.Token{
+		Code:          DEFER_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
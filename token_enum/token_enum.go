@@ -0,0 +1,25 @@
+package token_enum
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var ENUM_DECLARATION = "ENUM_DECLARATION"
+var EnumProcessor token.TokenProcessor = proccess
+var enumName = "enum"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(enumName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(enumName))
+
+	return token.Token{
+		Code:          ENUM_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
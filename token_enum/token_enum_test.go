@@ -0,0 +1,42 @@
+package token_enum
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func TestEnumShouldNotBeFound(t *testing.T) {
+	var src = source_mock.GetSourceMock(`enumeration`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := EnumProcessor(&buffer)
+
+	if isFound {
+		t.Errorf("Should't find token")
+	}
+
+	if token.Code != "" {
+		t.Errorf("Should't find token")
+	}
+}
+
+func TestEnum(t *testing.T) {
+	var src = source_mock.GetSourceMock(`enum`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := EnumProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if token.Code != ENUM_DECLARATION {
+		t.Errorf("Should find token")
+	}
+
+	if token.StartPosition != 0 || token.EndPosition != 3 {
+		t.Errorf("Should save position. But receive start: %d end: %d", token.StartPosition, token.EndPosition)
+	}
+}
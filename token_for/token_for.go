@@ -0,0 +1,25 @@
+package token_for
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var FOR_DECLARATION = "FOR_DECLARATION"
+var ForProcessor token.TokenProcessor = proccess
+var forName = "for"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(forName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(forName))
+
+	return token.Token{
+		Code:          FOR_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
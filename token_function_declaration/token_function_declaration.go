@@ -9,23 +9,40 @@ var FUNCTION_DECLARATION = "FUNCTION_DECLARATION"
 var FunctionDeclorationProcessor = proccess
 var FUNCTION_NAME_PARAM = "NAME"
 var FUNCTION_ARGUMENT_PARAM = "ARGUMENT"
+var FUNCTION_GENERATOR_PARAM = "GENERATOR"
+var FUNCTION_ARGUMENT_TYPE_PARAM = "ARGUMENT_TYPE"
+var FUNCTION_RETURN_TYPE_PARAM = "RETURN_TYPE"
 
-func readWordsWithSeporator(buffer token.IBuffer, seporator rune) []token.TokenParam {
-	var words = []token.TokenParam{}
+// Reads arguments names, every name can have type annotation, like "a: number, b"
+func readArguments(buffer token.IBuffer) ([]token.TokenParam, bool) {
+	var arguments = []token.TokenParam{}
 	buffer.TrimNext()
 
 	for token.IsKeyWordSymbol(buffer.GetSymbol()) {
-		words = append(words, token.ReadWord(buffer))
-		buffer.Clear()
-		buffer.TrimNext()
-		if buffer.GetSymbol() != seporator {
-			return words
+		var argument = token.ReadWord(buffer)
+		argument.Name = FUNCTION_ARGUMENT_PARAM
+		arguments = append(arguments, argument)
+
+		var typeAnnotation, isTyped = token.ReadTypeAnnotation(buffer)
+
+		if isTyped {
+			if len(typeAnnotation.Value) == 0 {
+				return arguments, false
+			}
+
+			typeAnnotation.Name = FUNCTION_ARGUMENT_TYPE_PARAM
+			arguments = append(arguments, typeAnnotation)
+			buffer.TrimNext()
+		}
+
+		if buffer.GetSymbol() != ',' {
+			return arguments, true
 		}
 		buffer.Next()
 		buffer.TrimNext()
 	}
 
-	return words
+	return arguments, true
 }
 
 const functionDeclorationName = "function"
@@ -38,12 +55,23 @@ func proccess(buffer token.IBuffer) (token.Token, bool, error) {
 	var functionDeclorationStartPosition = buffer.GetPosition()
 
 	buffer.Eat(len(functionDeclorationName))
-	// Go to next symbol
-	buffer.Next()
-
-	buffer.Clear()
 	buffer.TrimNext()
 
+	// Generator function, like "function* range() {}"
+	var generatorParams = []token.TokenParam{}
+
+	if buffer.GetSymbol() == '*' {
+		generatorParams = append(generatorParams, token.TokenParam{
+			Name:          FUNCTION_GENERATOR_PARAM,
+			Value:         "*",
+			StartPosition: buffer.GetPosition(),
+			EndPosition:   buffer.GetPosition(),
+		})
+		buffer.Next()
+		buffer.Clear()
+		buffer.TrimNext()
+	}
+
 	var functionName = token.ReadWord(buffer)
 	functionName.Name = FUNCTION_NAME_PARAM
 
@@ -69,18 +97,13 @@ func proccess(buffer token.IBuffer) (token.Token, bool, error) {
 	// Skip "("
 	buffer.Next()
 
-	var arguments = readWordsWithSeporator(buffer, ',')
-
-	for i := 0; i < len(arguments); i++ {
-		param := &arguments[i]
-		param.Name = FUNCTION_ARGUMENT_PARAM
+	var arguments, isArgumentsTypesValid = readArguments(buffer)
 
-		if len(param.Value) == 0 {
-			return token.Token{}, false, parser_error.ParserError{
-				Message:       "Function argument should have name",
-				StartPosition: functionDeclorationStartPosition,
-				EndPosition:   buffer.GetPosition(),
-			}
+	if !isArgumentsTypesValid {
+		return token.Token{}, false, parser_error.ParserError{
+			Message:       "Type annotation should have type name",
+			StartPosition: functionDeclorationStartPosition,
+			EndPosition:   buffer.GetPosition(),
 		}
 	}
 
@@ -95,11 +118,31 @@ func proccess(buffer token.IBuffer) (token.Token, bool, error) {
 	// Skip ")"
 	buffer.Next()
 
+	var endPosition = buffer.GetPosition() - 1
+	var params = append(append(arguments, functionName), generatorParams...)
+
+	// Return type, like "function f(): number {}"
+	var returnType, isTyped = token.ReadTypeAnnotation(buffer)
+
+	if isTyped {
+		if len(returnType.Value) == 0 {
+			return token.Token{}, false, parser_error.ParserError{
+				Message:       "Type annotation should have type name",
+				StartPosition: functionDeclorationStartPosition,
+				EndPosition:   buffer.GetPosition(),
+			}
+		}
+
+		returnType.Name = FUNCTION_RETURN_TYPE_PARAM
+		params = append(params, returnType)
+		endPosition = returnType.EndPosition
+	}
+
 	return token.Token{
 		Code:          FUNCTION_DECLARATION,
 		StartPosition: functionDeclorationStartPosition,
-		EndPosition:   buffer.GetPosition() - 1,
-		Params:        append(arguments, functionName),
+		EndPosition:   endPosition,
+		Params:        params,
 	}, true, nil
 }
 
@@ -112,3 +155,36 @@ func GetFunctionNameParam(functionToken token.Token) token.TokenParam {
 
 	return token.TokenParam{}
 }
+
+var DECORATOR = "DECORATOR"
+var DecoratorProcessor token.TokenProcessor = proccessDecorator
+var DECORATOR_NAME_PARAM = "DECORATOR_NAME"
+
+// Decorator of function declaration, like "@memo"
+func proccessDecorator(buffer token.IBuffer) (token.Token, bool, error) {
+	if buffer.GetSymbol() != '@' {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Next()
+
+	var decoratorName = token.ReadWord(buffer)
+	decoratorName.Name = DECORATOR_NAME_PARAM
+
+	if len(decoratorName.Value) == 0 {
+		return token.Token{}, false, parser_error.ParserError{
+			Message:       "Decorator should have name",
+			StartPosition: startPosition,
+			EndPosition:   startPosition,
+		}
+	}
+
+	return token.Token{
+		Code:          DECORATOR,
+		StartPosition: startPosition,
+		EndPosition:   decoratorName.EndPosition,
+		Params:        []token.TokenParam{decoratorName},
+	}, true, nil
+}
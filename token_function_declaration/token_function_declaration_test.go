@@ -303,3 +303,139 @@ func isSameParams(param token.TokenParam, target token.TokenParam) bool {
 
 	return true
 }
+
+func TestGeneratorFunction(t *testing.T) {
+	var src = source_mock.GetSourceMock(`function* range(n) {}`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	foundToken, isFound, _ := FunctionDeclorationProcessor(&buffer)
+
+	if isFound == false {
+		t.Errorf("Token should be found")
+	}
+
+	if foundToken.StartPosition != 0 || foundToken.EndPosition != 17 {
+		t.Errorf("Should save token position. Received start: %d end: %d", foundToken.StartPosition, foundToken.EndPosition)
+	}
+
+	var generatorParam = token.TokenParam{
+		Name:          FUNCTION_GENERATOR_PARAM,
+		Value:         "*",
+		StartPosition: 8,
+		EndPosition:   8,
+	}
+
+	if !containParam(foundToken.Params, generatorParam) {
+		t.Errorf("Should mark function as generator")
+	}
+
+	var nameParam = token.TokenParam{
+		Name:          FUNCTION_NAME_PARAM,
+		Value:         "range",
+		StartPosition: 10,
+		EndPosition:   14,
+	}
+
+	if !containParam(foundToken.Params, nameParam) {
+		t.Errorf("Should save function name")
+	}
+}
+
+func TestTypedFunction(t *testing.T) {
+	var src = source_mock.GetSourceMock(`function join(a: string, b , c :number): string {}`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	foundToken, isFound, _ := FunctionDeclorationProcessor(&buffer)
+
+	if isFound == false {
+		t.Errorf("Token should be found")
+	}
+
+	if foundToken.StartPosition != 0 || foundToken.EndPosition != 46 {
+		t.Errorf("Should save token position. Received start: %d end: %d", foundToken.StartPosition, foundToken.EndPosition)
+	}
+
+	var expectedParams = []token.TokenParam{
+		{Name: FUNCTION_ARGUMENT_PARAM, Value: "a", StartPosition: 14, EndPosition: 14},
+		{Name: FUNCTION_ARGUMENT_TYPE_PARAM, Value: "string", StartPosition: 17, EndPosition: 22},
+		{Name: FUNCTION_ARGUMENT_PARAM, Value: "b", StartPosition: 25, EndPosition: 25},
+		{Name: FUNCTION_ARGUMENT_PARAM, Value: "c", StartPosition: 29, EndPosition: 29},
+		{Name: FUNCTION_ARGUMENT_TYPE_PARAM, Value: "number", StartPosition: 32, EndPosition: 37},
+		{Name: FUNCTION_NAME_PARAM, Value: "join", StartPosition: 9, EndPosition: 12},
+		{Name: FUNCTION_RETURN_TYPE_PARAM, Value: "string", StartPosition: 41, EndPosition: 46},
+	}
+
+	if len(foundToken.Params) != len(expectedParams) {
+		t.Errorf("Should save arguments with types. Received: %v", foundToken.Params)
+		return
+	}
+
+	for index, param := range expectedParams {
+		if !isSameParams(foundToken.Params[index], param) {
+			t.Errorf("Should save param %v, but received: %v", param, foundToken.Params[index])
+		}
+	}
+}
+
+func TestErrorMissedArgumentType(t *testing.T) {
+	var src = source_mock.GetSourceMock(`function foo(a: , b) {}`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	_, _, err := FunctionDeclorationProcessor(&buffer)
+
+	re, ok := err.(parser_error.ParserError)
+
+	if !ok {
+		t.Errorf("Should return parser error")
+		return
+	}
+
+	if re.Message != "Type annotation should have type name" {
+		t.Errorf("Should return annotation error. Recived: \"%s\"", re.Message)
+	}
+}
+
+func TestDecorator(t *testing.T) {
+	var src = source_mock.GetSourceMock(`@memo function fib(n) {}`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	foundToken, isFound, _ := DecoratorProcessor(&buffer)
+
+	if !isFound || foundToken.Code != DECORATOR {
+		t.Errorf("Token should be found")
+		return
+	}
+
+	if foundToken.StartPosition != 0 || foundToken.EndPosition != 4 {
+		t.Errorf("Should save token position. Received start: %d end: %d", foundToken.StartPosition, foundToken.EndPosition)
+	}
+
+	var nameParam = token.TokenParam{
+		Name:          DECORATOR_NAME_PARAM,
+		Value:         "memo",
+		StartPosition: 1,
+		EndPosition:   4,
+	}
+
+	if !containParam(foundToken.Params, nameParam) {
+		t.Errorf("Should save decorator name")
+	}
+}
+
+func TestErrorDecoratorWithoutName(t *testing.T) {
+	var src = source_mock.GetSourceMock(`@ function fib(n) {}`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	_, _, err := DecoratorProcessor(&buffer)
+
+	re, ok := err.(parser_error.ParserError)
+
+	if !ok {
+		t.Errorf("Should return parser error")
+		return
+	}
+
+	if re.Message != "Decorator should have name" {
+		t.Errorf("Should return decorator error. Recived: \"%s\"", re.Message)
+	}
+}
@@ -0,0 +1,25 @@
+package token_if
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var IF_DECLARATION = "IF_DECLARATION"
+var IfProcessor token.TokenProcessor = proccess
+var ifName = "if"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(ifName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(ifName))
+
+	return token.Token{
+		Code:          IF_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
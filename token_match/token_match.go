@@ -0,0 +1,25 @@
+package token_match
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var MATCH_DECLARATION = "MATCH_DECLARATION"
+var MatchProcessor token.TokenProcessor = proccess
+var matchName = "match"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(matchName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(matchName))
+
+	return token.Token{
+		Code:          MATCH_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
@@ -1,6 +1,10 @@
 package token_number
 
 import (
+	"math/big"
+	"strconv"
+	"strings"
+
 	"github.com/VadimZvf/golang/parser_error"
 	"github.com/VadimZvf/golang/token"
 )
@@ -8,27 +12,50 @@ import (
 var NUMBER = "NUMBER"
 var NumberProcessor = proccess
 
+var integerSuffix = 'n'
+
+type radix struct {
+	name    string
+	isDigit func(symbol rune) bool
+}
+
+var radixes = map[rune]radix{
+	'x': {name: "hexadecimal", isDigit: isHexDigit},
+	'X': {name: "hexadecimal", isDigit: isHexDigit},
+	'b': {name: "binary", isDigit: isBinaryDigit},
+	'B': {name: "binary", isDigit: isBinaryDigit},
+	'o': {name: "octal", isDigit: isOctalDigit},
+	'O': {name: "octal", isDigit: isOctalDigit},
+}
+
 func proccess(buffer token.IBuffer) (token.Token, bool, error) {
-	if !token.IsNumber(buffer.GetSymbol()) {
+	var isLeadingDot = buffer.GetSymbol() == '.' && token.IsNumber(buffer.LookNextSymbol())
+
+	if !token.IsNumber(buffer.GetSymbol()) && !isLeadingDot {
 		return token.Token{}, false, nil
 	}
 
 	var startPosition = buffer.GetPosition()
+	var readErr error
 
-	for token.IsNumber(buffer.GetSymbol()) && !buffer.GetIsEnd() {
-		buffer.AddSymbol()
-		buffer.Next()
+	if numberRadix, isRadix := radixes[buffer.LookNextSymbol()]; buffer.GetSymbol() == '0' && isRadix {
+		readErr = readRadixNumber(buffer, startPosition, numberRadix)
+	} else {
+		readErr = readDecimalNumber(buffer, startPosition)
 	}
 
-	// Check that number has decimal part
-	if buffer.GetSymbol() == '.' {
-		buffer.AddSymbol()
-		buffer.Next()
+	if readErr != nil {
+		return token.Token{}, false, readErr
+	}
 
-		for token.IsNumber(buffer.GetSymbol()) && !buffer.GetIsEnd() {
-			buffer.AddSymbol()
-			buffer.Next()
+	// Integer literal, like "123n" or "0xFFn"
+	if buffer.GetSymbol() == integerSuffix && !buffer.GetIsEnd() {
+		if !isRadixNumber(buffer.GetValue()) && strings.ContainsAny(buffer.GetValue(), ".eE") {
+			return token.Token{}, false, createError("Syntax error, integer cannot have decimal point or exponent", startPosition, buffer.GetPosition())
 		}
+
+		buffer.AddSymbol()
+		buffer.Next()
 	}
 
 	if token.IsKeyWordSymbol(buffer.GetSymbol()) {
@@ -39,6 +66,22 @@ func proccess(buffer token.IBuffer) (token.Token, bool, error) {
 		}
 	}
 
+	var parseErr error
+
+	if IsInteger(buffer.GetValue()) {
+		_, parseErr = ParseInteger(buffer.GetValue())
+	} else {
+		_, parseErr = ParseNumber(buffer.GetValue())
+	}
+
+	if parseErr != nil {
+		return token.Token{}, false, parser_error.ParserError{
+			Message:       "Syntax error, number is out of range",
+			StartPosition: startPosition,
+			EndPosition:   buffer.GetPosition() - 1,
+		}
+	}
+
 	return token.Token{
 		Code:          NUMBER,
 		StartPosition: startPosition,
@@ -46,3 +89,181 @@ func proccess(buffer token.IBuffer) (token.Token, bool, error) {
 		Value:         buffer.GetValue(),
 	}, true, nil
 }
+
+// Reads numbers like "0xFF", "0b1010", "0o17"
+func readRadixNumber(buffer token.IBuffer, startPosition int, numberRadix radix) error {
+	// Save "0" and radix symbol
+	buffer.AddSymbol()
+	buffer.Next()
+	buffer.AddSymbol()
+	buffer.Next()
+
+	if buffer.GetSymbol() == '_' {
+		return createSeparatorError(startPosition, buffer.GetPosition())
+	}
+
+	if !numberRadix.isDigit(buffer.GetSymbol()) || buffer.GetIsEnd() {
+		if token.IsKeyWordSymbol(buffer.GetSymbol()) && !buffer.GetIsEnd() {
+			return createError("Syntax error, invalid digit \""+string(buffer.GetSymbol())+"\" in "+numberRadix.name+" number", startPosition, buffer.GetPosition())
+		}
+
+		return createError("Syntax error, "+numberRadix.name+" number should have digits", startPosition, buffer.GetPosition()-1)
+	}
+
+	var digitsErr = readDigits(buffer, startPosition, numberRadix.isDigit)
+
+	if digitsErr != nil {
+		return digitsErr
+	}
+
+	if token.IsNumber(buffer.GetSymbol()) || (numberRadix.name != "hexadecimal" && isHexDigit(buffer.GetSymbol())) {
+		return createError("Syntax error, invalid digit \""+string(buffer.GetSymbol())+"\" in "+numberRadix.name+" number", startPosition, buffer.GetPosition())
+	}
+
+	return nil
+}
+
+// Reads numbers like "12", "1_000", "3.5", ".5", "1.5e-3"
+func readDecimalNumber(buffer token.IBuffer, startPosition int) error {
+	if token.IsNumber(buffer.GetSymbol()) {
+		var integerErr = readDigits(buffer, startPosition, token.IsNumber)
+
+		if integerErr != nil {
+			return integerErr
+		}
+	}
+
+	// Check that number has decimal part
+	if buffer.GetSymbol() == '.' && !buffer.GetIsEnd() {
+		buffer.AddSymbol()
+		buffer.Next()
+
+		if buffer.GetSymbol() == '_' {
+			return createSeparatorError(startPosition, buffer.GetPosition())
+		}
+
+		if token.IsNumber(buffer.GetSymbol()) && !buffer.GetIsEnd() {
+			var fractionErr = readDigits(buffer, startPosition, token.IsNumber)
+
+			if fractionErr != nil {
+				return fractionErr
+			}
+		}
+
+		if buffer.GetSymbol() == '.' && token.IsNumber(buffer.LookNextSymbol()) && !buffer.GetIsEnd() {
+			return createError("Syntax error, number can have only one decimal point", startPosition, buffer.GetPosition())
+		}
+	}
+
+	if (buffer.GetSymbol() == 'e' || buffer.GetSymbol() == 'E') && !buffer.GetIsEnd() {
+		buffer.AddSymbol()
+		buffer.Next()
+
+		if (buffer.GetSymbol() == '+' || buffer.GetSymbol() == '-') && !buffer.GetIsEnd() {
+			buffer.AddSymbol()
+			buffer.Next()
+		}
+
+		if !token.IsNumber(buffer.GetSymbol()) || buffer.GetIsEnd() {
+			return createError("Syntax error, exponent should have digits", startPosition, buffer.GetPosition())
+		}
+
+		return readDigits(buffer, startPosition, token.IsNumber)
+	}
+
+	return nil
+}
+
+// Reads digits, which can be divided by single "_"
+func readDigits(buffer token.IBuffer, startPosition int, isDigit func(symbol rune) bool) error {
+	for (isDigit(buffer.GetSymbol()) || buffer.GetSymbol() == '_') && !buffer.GetIsEnd() {
+		if buffer.GetSymbol() == '_' {
+			if !isDigit(buffer.LookNextSymbol()) {
+				return createSeparatorError(startPosition, buffer.GetPosition())
+			}
+
+			buffer.Next()
+			continue
+		}
+
+		buffer.AddSymbol()
+		buffer.Next()
+	}
+
+	return nil
+}
+
+func createSeparatorError(startPosition int, separatorPosition int) error {
+	return createError("Syntax error, numeric separator \"_\" should be placed between digits", startPosition, separatorPosition)
+}
+
+func createError(message string, startPosition int, endPosition int) error {
+	return parser_error.ParserError{
+		Message:       message,
+		StartPosition: startPosition,
+		EndPosition:   endPosition,
+	}
+}
+
+// Converts value of number token to float. Runtime should use it to read numbers the same way as tokenizer
+func ParseNumber(value string) (float64, error) {
+	if isRadixNumber(value) {
+		var integer, isValid = new(big.Int).SetString(value, 0)
+
+		if !isValid {
+			return 0, strconv.ErrSyntax
+		}
+
+		var number, _ = new(big.Float).SetInt(integer).Float64()
+
+		return number, nil
+	}
+
+	return strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64)
+}
+
+// Integer tokens have "n" suffix, other number tokens are floats
+func IsInteger(value string) bool {
+	return strings.HasSuffix(value, string(integerSuffix))
+}
+
+// Converts value of integer token to arbitrary precision integer
+func ParseInteger(value string) (*big.Int, error) {
+	var digits = strings.ReplaceAll(strings.TrimSuffix(value, string(integerSuffix)), "_", "")
+	var base = 10
+
+	// Base is taken from prefix, like "0x"
+	if isRadixNumber(digits) {
+		base = 0
+	}
+
+	var integer, isValid = new(big.Int).SetString(digits, base)
+
+	if !isValid {
+		return nil, strconv.ErrSyntax
+	}
+
+	return integer, nil
+}
+
+func isRadixNumber(value string) bool {
+	if len(value) > 1 && value[0] == '0' {
+		var _, isRadix = radixes[rune(value[1])]
+
+		return isRadix
+	}
+
+	return false
+}
+
+func isHexDigit(symbol rune) bool {
+	return token.IsNumber(symbol) || (symbol >= 'a' && symbol <= 'f') || (symbol >= 'A' && symbol <= 'F')
+}
+
+func isBinaryDigit(symbol rune) bool {
+	return symbol == '0' || symbol == '1'
+}
+
+func isOctalDigit(symbol rune) bool {
+	return symbol >= '0' && symbol <= '7'
+}
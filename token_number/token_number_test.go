@@ -97,3 +97,120 @@ func TestErrorParsing(t *testing.T) {
 		t.Errorf("Should return position of error. Recived start: %d, end: %d", re.StartPosition, re.EndPosition)
 	}
 }
+
+func TestNumberFormats(t *testing.T) {
+	var cases = []struct {
+		code  string
+		value string
+		end   int
+	}{
+		{code: `0xFF`, value: "0xFF", end: 3},
+		{code: `0b1010`, value: "0b1010", end: 5},
+		{code: `0o17`, value: "0o17", end: 3},
+		{code: `1.5e-3`, value: "1.5e-3", end: 5},
+		{code: `.5`, value: ".5", end: 1},
+		{code: `1_000_000`, value: "1000000", end: 8},
+		{code: `123n`, value: "123n", end: 3},
+		{code: `0xFFn`, value: "0xFFn", end: 4},
+	}
+
+	for _, testCase := range cases {
+		var src = source_mock.GetSourceMock(testCase.code)
+		var buffer = tokenizer_buffer.CreateBuffer(src)
+
+		var token, isFound, err = NumberProcessor(&buffer)
+
+		if err != nil || !isFound {
+			t.Errorf("Token should be found for \"%s\"", testCase.code)
+			continue
+		}
+
+		if token.Value != testCase.value {
+			t.Errorf("Should save token value for \"%s\". Received: \"%s\"", testCase.code, token.Value)
+		}
+
+		if token.StartPosition != 0 || token.EndPosition != testCase.end {
+			t.Errorf("Should save token position for \"%s\". Recived start: %d, end: %d", testCase.code, token.StartPosition, token.EndPosition)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	var cases = map[string]float64{
+		"0xFF":    255,
+		"0b1010":  10,
+		"0o17":    15,
+		"1.5e-3":  0.0015,
+		".5":      0.5,
+		"1000000": 1000000,
+		"2E3":     2000,
+	}
+
+	for value, expected := range cases {
+		var number, err = ParseNumber(value)
+
+		if err != nil || number != expected {
+			t.Errorf("Should parse \"%s\" as %f. Received: %f", value, expected, number)
+		}
+	}
+}
+
+func TestParseInteger(t *testing.T) {
+	var cases = map[string]string{
+		"123n":                   "123",
+		"0xFFn":                  "255",
+		"0b1010n":                "10",
+		"012n":                   "12",
+		"9007199254740993n":      "9007199254740993",
+		"123456789012345678901n": "123456789012345678901",
+	}
+
+	for value, expected := range cases {
+		var integer, err = ParseInteger(value)
+
+		if err != nil || integer.String() != expected {
+			t.Errorf("Should parse \"%s\" as %s. Received: %v", value, expected, integer)
+		}
+	}
+}
+
+func TestMalformedNumbers(t *testing.T) {
+	var cases = []struct {
+		code    string
+		message string
+		end     int
+	}{
+		{code: `0x;`, message: "Syntax error, hexadecimal number should have digits", end: 1},
+		{code: `0b102`, message: "Syntax error, invalid digit \"2\" in binary number", end: 4},
+		{code: `0o9`, message: "Syntax error, invalid digit \"9\" in octal number", end: 2},
+		{code: `1__0`, message: "Syntax error, numeric separator \"_\" should be placed between digits", end: 1},
+		{code: `10_;`, message: "Syntax error, numeric separator \"_\" should be placed between digits", end: 2},
+		{code: `1e+;`, message: "Syntax error, exponent should have digits", end: 3},
+		{code: `1.2.3`, message: "Syntax error, number can have only one decimal point", end: 3},
+		{code: `1e999`, message: "Syntax error, number is out of range", end: 4},
+		{code: `1.5n`, message: "Syntax error, integer cannot have decimal point or exponent", end: 3},
+		{code: `12nm`, message: "Syntax error, invalid keyword name. Keyword cannot start with number", end: 3},
+	}
+
+	for _, testCase := range cases {
+		var src = source_mock.GetSourceMock(testCase.code)
+		var buffer = tokenizer_buffer.CreateBuffer(src)
+
+		var _, _, err = NumberProcessor(&buffer)
+
+		re, ok := err.(parser_error.ParserError)
+
+		if !ok {
+			t.Errorf("Should return parser error for \"%s\"", testCase.code)
+			continue
+		}
+
+		if re.Message != testCase.message {
+			t.Errorf("Should return syntax error for \"%s\". Recived: \"%s\"", testCase.code, re.Message)
+		}
+
+		if re.StartPosition != 0 || re.EndPosition != testCase.end {
+			t.Errorf("Should return position of error for \"%s\". Recived start: %d, end: %d", testCase.code, re.StartPosition, re.EndPosition)
+		}
+	}
+}
@@ -13,6 +13,11 @@ func proccess(buffer token.IBuffer) (token.Token, bool, error) {
 		return token.Token{}, false, nil
 	}
 
+	// Number with leading dot, like ".5"
+	if token.IsNumber(buffer.LookNextSymbol()) {
+		return token.Token{}, false, nil
+	}
+
 	var startPosition = buffer.GetPosition()
 	buffer.Next()
 
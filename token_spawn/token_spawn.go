@@ -0,0 +1,25 @@
+package token_spawn
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var SPAWN_DECLARATION = "SPAWN_DECLARATION"
+var SpawnProcessor token.TokenProcessor = proccess
+var spawnName = "spawn"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(spawnName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(spawnName))
+
+	return token.Token{
+		Code:          SPAWN_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
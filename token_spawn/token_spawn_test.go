@@ -0,0 +1,42 @@
+package token_spawn
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func TestSpawnShouldNotBeFound(t *testing.T) {
+	var src = source_mock.GetSourceMock(`spawnfoo`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := SpawnProcessor(&buffer)
+
+	if isFound {
+		t.Errorf("Should't find token")
+	}
+
+	if token.Code != "" {
+		t.Errorf("Should't find token")
+	}
+}
+
+func TestSpawn(t *testing.T) {
+	var src = source_mock.GetSourceMock(`spawn`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := SpawnProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if token.Code != SPAWN_DECLARATION {
+		t.Errorf("Should find token")
+	}
+
+	if token.StartPosition != 0 || token.EndPosition != 4 {
+		t.Errorf("Should save position. But receive start: %d end: %d", token.StartPosition, token.EndPosition)
+	}
+}
@@ -0,0 +1,26 @@
+package token_typeof
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var TYPEOF = "TYPEOF"
+var TypeofProcessor token.TokenProcessor = proccess
+var typeofName = "typeof"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(typeofName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(typeofName))
+
+	return token.Token{
+		Code:          TYPEOF,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+		Value:         typeofName,
+	}, true, nil
+}
@@ -0,0 +1,42 @@
+package token_typeof
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+func TestTypeofShouldNotBeFound(t *testing.T) {
+	var src = source_mock.GetSourceMock(`typeoffoo`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := TypeofProcessor(&buffer)
+
+	if isFound {
+		t.Errorf("Should't find token")
+	}
+
+	if token.Code != "" {
+		t.Errorf("Should't find token")
+	}
+}
+
+func TestTypeof(t *testing.T) {
+	var src = source_mock.GetSourceMock(`typeof`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	token, isFound, _ := TypeofProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if token.Code != TYPEOF {
+		t.Errorf("Should find token")
+	}
+
+	if token.StartPosition != 0 || token.EndPosition != 5 {
+		t.Errorf("Should save position. But receive start: %d end: %d", token.StartPosition, token.EndPosition)
+	}
+}
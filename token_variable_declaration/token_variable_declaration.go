@@ -9,6 +9,7 @@ var VARIABLE_DECLARAION = "VARIABLE_DECLARAION"
 var VariableDeclarationProcessor token.TokenProcessor = proccess
 
 var VARIABLE_NAME_PARAM = "NAME"
+var VARIABLE_TYPE_PARAM = "TYPE"
 var variableDeclorationName = "var"
 
 func proccess(buffer token.IBuffer) (token.Token, bool, error) {
@@ -43,11 +44,30 @@ func proccess(buffer token.IBuffer) (token.Token, bool, error) {
 		}
 	}
 
+	var params = []token.TokenParam{variableName}
+
+	// Typed variable, like "var a: number"
+	var typeAnnotation, isTyped = token.ReadTypeAnnotation(buffer)
+
+	if isTyped {
+		if len(typeAnnotation.Value) == 0 {
+			return token.Token{}, false, parser_error.ParserError{
+				Message:       "Type annotation should have type name",
+				StartPosition: startPosition,
+				EndPosition:   buffer.GetPosition(),
+			}
+		}
+
+		typeAnnotation.Name = VARIABLE_TYPE_PARAM
+		params = append(params, typeAnnotation)
+		endPosition = typeAnnotation.EndPosition
+	}
+
 	return token.Token{
 		Code:          VARIABLE_DECLARAION,
 		StartPosition: startPosition,
 		EndPosition:   endPosition,
-		Params:        []token.TokenParam{variableName},
+		Params:        params,
 	}, true, nil
 }
 
@@ -60,3 +80,13 @@ func GetVariableNameParam(variableToken token.Token) token.TokenParam {
 
 	return token.TokenParam{}
 }
+
+func GetVariableTypeParam(variableToken token.Token) (token.TokenParam, bool) {
+	for _, param := range variableToken.Params {
+		if param.Name == VARIABLE_TYPE_PARAM {
+			return param, true
+		}
+	}
+
+	return token.TokenParam{}, false
+}
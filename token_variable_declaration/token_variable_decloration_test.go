@@ -126,3 +126,42 @@ func containParam(params []token.TokenParam, target token.TokenParam) bool {
 	}
 	return true
 }
+
+func TestTypedVariableDecloration(t *testing.T) {
+	var src = source_mock.GetSourceMock(`var count : integer = 1n;`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	foundToken, isFound, _ := VariableDeclarationProcessor(&buffer)
+
+	if !isFound {
+		t.Errorf("Should find token")
+	}
+
+	if foundToken.StartPosition != 0 || foundToken.EndPosition != 18 {
+		t.Errorf("Should save token position. Received start: %d end: %d", foundToken.StartPosition, foundToken.EndPosition)
+	}
+
+	var variableTypeParam = token.TokenParam{
+		Name:          "TYPE",
+		Value:         "integer",
+		StartPosition: 12,
+		EndPosition:   18,
+	}
+
+	var foundTypeParam, isTyped = GetVariableTypeParam(foundToken)
+
+	if !isTyped || foundTypeParam != variableTypeParam {
+		t.Errorf("Should save type. Received: %v", foundToken.Params)
+	}
+}
+
+func TestMissedVariableType(t *testing.T) {
+	var src = source_mock.GetSourceMock(`var count: = 1;`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+
+	_, _, err := VariableDeclarationProcessor(&buffer)
+
+	if err == nil || err.Error() != "Type annotation should have type name" {
+		t.Errorf("Should return annotation error. Received: %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package token_while
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var WHILE_DECLARATION = "WHILE_DECLARATION"
+var WhileProcessor token.TokenProcessor = proccess
+var whileName = "while"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(whileName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(whileName))
+
+	return token.Token{
+		Code:          WHILE_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
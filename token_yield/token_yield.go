@@ -0,0 +1,25 @@
+package token_yield
+
+import (
+	"github.com/VadimZvf/golang/token"
+)
+
+var YIELD_DECLARATION = "YIELD_DECLARATION"
+var YieldProcessor token.TokenProcessor = proccess
+var yieldName = "yield"
+
+func proccess(buffer token.IBuffer) (token.Token, bool, error) {
+	if !buffer.IsStartsWithWord(yieldName) {
+		return token.Token{}, false, nil
+	}
+
+	var startPosition = buffer.GetPosition()
+
+	buffer.Eat(len(yieldName))
+
+	return token.Token{
+		Code:          YIELD_DECLARATION,
+		StartPosition: startPosition,
+		EndPosition:   buffer.GetPosition() - 1,
+	}, true, nil
+}
@@ -3,27 +3,43 @@ package tokenizer
 import (
 	"github.com/VadimZvf/golang/parser_error"
 	"github.com/VadimZvf/golang/token"
+	"github.com/VadimZvf/golang/token_async"
+	"github.com/VadimZvf/golang/token_await"
 	"github.com/VadimZvf/golang/token_boolean"
+	"github.com/VadimZvf/golang/token_break"
+	"github.com/VadimZvf/golang/token_continue"
+	"github.com/VadimZvf/golang/token_defer"
+	"github.com/VadimZvf/golang/token_enum"
+	"github.com/VadimZvf/golang/token_for"
 	"github.com/VadimZvf/golang/token_function_declaration"
+	"github.com/VadimZvf/golang/token_if"
 	"github.com/VadimZvf/golang/token_keyword"
+	"github.com/VadimZvf/golang/token_match"
 	"github.com/VadimZvf/golang/token_number"
 	"github.com/VadimZvf/golang/token_read_property"
 	"github.com/VadimZvf/golang/token_return"
+	"github.com/VadimZvf/golang/token_spawn"
 	"github.com/VadimZvf/golang/token_string"
+	"github.com/VadimZvf/golang/token_typeof"
 	"github.com/VadimZvf/golang/token_variable_declaration"
+	"github.com/VadimZvf/golang/token_while"
+	"github.com/VadimZvf/golang/token_yield"
 )
 
 type iBuffer interface {
 	GetValue() (value string)
 	GetSymbol() (symbol rune)
+	LookNextSymbol() (symbol rune)
 	GetPosition() int
 	GetIsEnd() bool
 	Next()
 	TrimNext()
 	AddSymbol()
 	IsStartsWithWord(value string) bool
+	IsStartsWith(value string) bool
 	Eat(length int)
 	Clear()
+	GetReadError() error
 }
 
 type Tokenizer struct {
@@ -50,6 +66,15 @@ func (tknzr *Tokenizer) GetTokens() ([]token.Token, error) {
 
 		foundToken, isFoundToken, err := getToken(tknzr.buffer)
 
+		// Code, which was read before failed read, is not complete, so its errors are not reported
+		if tknzr.buffer.GetReadError() != nil {
+			return tknzr.tokens, parser_error.ParserError{
+				Message:       "Cannot read source: " + tknzr.buffer.GetReadError().Error(),
+				StartPosition: tknzr.buffer.GetPosition(),
+				EndPosition:   tknzr.buffer.GetPosition(),
+			}
+		}
+
 		if err != nil {
 			return tknzr.tokens, err
 		}
@@ -73,29 +98,63 @@ func (tknzr *Tokenizer) GetTokens() ([]token.Token, error) {
 	}
 }
 
-func getToken(buffer iBuffer) (token.Token, bool, error) {
-	var tokensArray = []token.TokenProcessor{
-		token_read_property.ReadPropertyProcessor,
-		token_number.NumberProcessor,
-		token_return.ReturnProcessor,
-		token_boolean.BooleanProcessor,
-		token_variable_declaration.VariableDeclarationProcessor,
-		token_function_declaration.FunctionDeclorationProcessor,
-		token_keyword.KeyWordProcessor,
-		token_string.StringProcessor,
-		token.AssignmentProcessor,
-		token.OpenBlockProcessor,
-		token.CloseBlockProcessor,
-		token.OpenExpressionProcessor,
-		token.CloseExpressionProcessor,
-		token.AddProcessor,
-		token.SubtractProcessor,
-		token.SlashProcessor,
-		token.AsteriskProcessor,
-		token.EndLineProcessor,
-		token.CommaProcessor,
-	}
+// Processors are tried in order, the first found token is taken
+var tokensArray = []token.TokenProcessor{
+	token_read_property.ReadPropertyProcessor,
+	token_number.NumberProcessor,
+	token_return.ReturnProcessor,
+	token_boolean.BooleanProcessor,
+	token_variable_declaration.VariableDeclarationProcessor,
+	token_function_declaration.FunctionDeclorationProcessor,
+	token_function_declaration.DecoratorProcessor,
+	token_match.MatchProcessor,
+	token_if.IfProcessor,
+	token_typeof.TypeofProcessor,
+	token_while.WhileProcessor,
+	token_for.ForProcessor,
+	token_break.BreakProcessor,
+	token_continue.ContinueProcessor,
+	token_yield.YieldProcessor,
+	token_async.AsyncProcessor,
+	token_await.AwaitProcessor,
+	token_spawn.SpawnProcessor,
+	token_enum.EnumProcessor,
+	token_defer.DeferProcessor,
+	token_keyword.KeyWordProcessor,
+	token_string.StringProcessor,
+	token.EqualProcessor,
+	token.ArrowProcessor,
+	token.NotEqualProcessor,
+	token.PipeProcessor,
+	token.UnsignedShiftRightProcessor,
+	token.ShiftRightProcessor,
+	token.ShiftLeftProcessor,
+	token.GreaterOrEqualProcessor,
+	token.LessOrEqualProcessor,
+	token.AssignmentProcessor,
+	token.GreaterProcessor,
+	token.LessProcessor,
+	token.VerticalBarProcessor,
+	token.AmpersandProcessor,
+	token.CaretProcessor,
+	token.TildeProcessor,
+	token.OpenBlockProcessor,
+	token.CloseBlockProcessor,
+	token.OpenExpressionProcessor,
+	token.CloseExpressionProcessor,
+	token.OpenIndexProcessor,
+	token.CloseIndexProcessor,
+	token.AddProcessor,
+	token.SubtractProcessor,
+	token.SlashProcessor,
+	token.AsteriskProcessor,
+	token.PercentProcessor,
+	token.EndLineProcessor,
+	token.CommaProcessor,
+	token.ColonProcessor,
+}
 
+func getToken(buffer iBuffer) (token.Token, bool, error) {
 	for i := 0; i < len(tokensArray); i++ {
 		tokenProccessor := tokensArray[i]
 		token, isFound, err := tokenProccessor(buffer)
@@ -0,0 +1,52 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/tokenizer_buffer"
+)
+
+const megabyte = 1 << 20
+
+const programChunk = `
+function sum(count: number): number {
+	var total = 0;
+	var i = 1_000;
+	while (i < count) {
+		total = total + i * 2 % 7;
+		i = i + 1;
+	}
+	return total;
+}
+print("Сумма: " + sum(20000));
+`
+
+func BenchmarkTokenizeProgram(b *testing.B) {
+	var code = strings.Repeat(programChunk, megabyte/len(programChunk))
+
+	benchmarkTokens(b, code)
+}
+
+// Token value, which grows symbol by symbol
+func BenchmarkTokenizeLongString(b *testing.B) {
+	var code = "var text = `" + strings.Repeat("строка\n", megabyte/len("строка\n")) + "`;"
+
+	benchmarkTokens(b, code)
+}
+
+func benchmarkTokens(b *testing.B, code string) {
+	b.SetBytes(int64(len(code)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var buffer = tokenizer_buffer.CreateBuffer(source_mock.GetSourceMock(code))
+		var tknzr = GetTokenizer(&buffer)
+		var _, err = tknzr.GetTokens()
+
+		if err != nil {
+			b.Fatalf("Code should be tokenized, but failed with error: \"%s\"", err.Error())
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package tokenizer
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/VadimZvf/golang/parser_error"
@@ -377,7 +380,7 @@ func TestSyntaxError(t *testing.T) {
 	var src = source_mock.GetSourceMock(`
 	const a = 123;
 
-	^
+	#
 
 	function foo() {
 
@@ -398,7 +401,7 @@ func TestSyntaxError(t *testing.T) {
 		t.Errorf("Should return parser error")
 	}
 
-	if re.Message != "Syntax error, unexpected symbol: ^" {
+	if re.Message != "Syntax error, unexpected symbol: #" {
 		t.Errorf("Should return syntax error message, but receinve: \"%s\"", re.Message)
 	}
 
@@ -407,6 +410,138 @@ func TestSyntaxError(t *testing.T) {
 	}
 }
 
+func TestReadError(t *testing.T) {
+	var src = io.MultiReader(strings.NewReader("var a = 1;\nvar b"), failingReader{})
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+	var tokenizer = GetTokenizer(&buffer)
+	var _, err = tokenizer.GetTokens()
+
+	re, ok := err.(parser_error.ParserError)
+
+	if !ok || re.Message != "Cannot read source: disk failed" {
+		t.Errorf("Should return error of source, but received: %#v", err)
+	}
+}
+
+func TestComparisonOperators(t *testing.T) {
+	var src = source_mock.GetSourceMock(`a>=1==b=>c`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+	var tokenizer = GetTokenizer(&buffer)
+	var tokens, _ = tokenizer.GetTokens()
+
+	if !isSameToken(tokens[1], token.Token{
+		Code:          token.GREATER_OR_EQUAL,
+		Value:         ">=",
+		StartPosition: 1,
+		EndPosition:   2,
+	}) {
+		t.Errorf("Wrong token")
+	}
+
+	if !isSameToken(tokens[3], token.Token{
+		Code:          token.EQUAL,
+		Value:         "==",
+		StartPosition: 4,
+		EndPosition:   5,
+	}) {
+		t.Errorf("Wrong token")
+	}
+
+	if !isSameToken(tokens[5], token.Token{
+		Code:          token.ARROW,
+		Value:         "=>",
+		StartPosition: 7,
+		EndPosition:   8,
+	}) {
+		t.Errorf("Wrong token")
+	}
+}
+
+func TestUnicodeVariable(t *testing.T) {
+	var src = source_mock.GetSourceMock("var имя = \"Мир\";\r\nимя")
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+	var tokenizer = GetTokenizer(&buffer)
+	var tokens, err = tokenizer.GetTokens()
+
+	if err != nil {
+		t.Errorf("Should parse without errors. Received: %s", err.Error())
+		return
+	}
+
+	if !checkParam(tokens[0], token.TokenParam{
+		Name:          token_variable_declaration.VARIABLE_NAME_PARAM,
+		Value:         "имя",
+		StartPosition: 4,
+		EndPosition:   6,
+	}) {
+		t.Errorf("Wrong param")
+	}
+
+	if !isSameToken(tokens[2], token.Token{
+		Code:          token_string.STRING,
+		Value:         "Мир",
+		StartPosition: 10,
+		EndPosition:   14,
+	}) {
+		t.Errorf("Wrong token")
+	}
+
+	if !isSameToken(tokens[4], token.Token{
+		Code:          token.KEY_WORD,
+		Value:         "имя",
+		StartPosition: 18,
+		EndPosition:   20,
+	}) {
+		t.Errorf("Wrong token")
+	}
+}
+
+func TestShiftOperators(t *testing.T) {
+	var src = source_mock.GetSourceMock(`a>>>1>>b<<c>=~d`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+	var tokenizer = GetTokenizer(&buffer)
+	var tokens, _ = tokenizer.GetTokens()
+
+	var expected = map[int]token.Token{
+		1: {Code: token.UNSIGNED_SHIFT_RIGHT, Value: ">>>", StartPosition: 1, EndPosition: 3},
+		3: {Code: token.SHIFT_RIGHT, Value: ">>", StartPosition: 5, EndPosition: 6},
+		5: {Code: token.SHIFT_LEFT, Value: "<<", StartPosition: 8, EndPosition: 9},
+		7: {Code: token.GREATER_OR_EQUAL, Value: ">=", StartPosition: 11, EndPosition: 12},
+		8: {Code: token.TILDE, Value: "~", StartPosition: 13, EndPosition: 13},
+	}
+
+	for index, expectedToken := range expected {
+		if !isSameToken(tokens[index], expectedToken) {
+			t.Errorf("Wrong token: %v", tokens[index])
+		}
+	}
+}
+
+func TestPipeOperator(t *testing.T) {
+	var src = source_mock.GetSourceMock(`a|>b|c`)
+	var buffer = tokenizer_buffer.CreateBuffer(src)
+	var tokenizer = GetTokenizer(&buffer)
+	var tokens, _ = tokenizer.GetTokens()
+
+	if !isSameToken(tokens[1], token.Token{
+		Code:          token.PIPE,
+		Value:         "|>",
+		StartPosition: 1,
+		EndPosition:   2,
+	}) {
+		t.Errorf("Wrong token")
+	}
+
+	if !isSameToken(tokens[3], token.Token{
+		Code:          token.VERTICAL_BAR,
+		Value:         "|",
+		StartPosition: 4,
+		EndPosition:   4,
+	}) {
+		t.Errorf("Wrong token")
+	}
+}
+
 /// Utils
 
 func isSameToken(first token.Token, second token.Token) bool {
@@ -450,3 +585,10 @@ func checkParam(tokenItem token.Token, targetParam token.TokenParam) bool {
 
 	return false
 }
+
+// Source, which fails on read
+type failingReader struct{}
+
+func (reader failingReader) Read(bytes []byte) (int, error) {
+	return 0, errors.New("disk failed")
+}
@@ -1,50 +1,81 @@
 package tokenizer_buffer
 
 import (
-	"strings"
+	"bytes"
+	"io"
+	"unicode/utf8"
+	"unsafe"
 
 	"github.com/VadimZvf/golang/token"
 )
 
-type iSource interface {
-	NextSymbol() (symbol rune, isEnd bool)
-}
+// Size of one read from source, so file is read by few syscalls
+const readSize = 32 * 1024
 
-type Buffer struct {
-	value string // Current saved value, with trims, skips
+var byteOrderMark = []byte("\uFEFF")
 
-	loadedValue      string // Current loaded value from source
-	positionInBuffer int    // Index position in loaded value
-	code             string // Full text of code
-	position         int    // Current index position in code
-	isSourceEnd      bool
+// Code is read from source by chunks and kept in one byte slice. Loaded bytes are never
+// changed, new chunks are only appended, so token values are views of loaded code.
+// All positions are counted in symbols (runes), not in bytes
+type Buffer struct {
+	code        []byte // Loaded code
+	isSourceEnd bool
+	// Error of source, other than end of it. Code, loaded before error, is not complete
+	readErr error
+
+	start         int // Byte offset of the first not eaten symbol
+	startPosition int // Position of the first not eaten symbol
+
+	offset     int  // Byte offset of current symbol
+	position   int  // Position of current symbol
+	symbol     rune // Current symbol, zero at end
+	symbolSize int
+
+	// Bytes of saved value in code, while symbols are added one after another
+	valueStart int
+	valueEnd   int
+	// Saved value, when symbols are added with skips
+	copiedValue   []byte
+	isValueCopied bool
 
 	// Inner props
-	source iSource
+	source io.Reader
 }
 
 func (buffer *Buffer) GetValue() string {
-	return buffer.value
+	if buffer.isValueCopied {
+		return string(buffer.copiedValue)
+	}
+
+	return bytesToString(buffer.code[buffer.valueStart:buffer.valueEnd])
 }
 
 func (buffer *Buffer) GetSymbol() rune {
-	if buffer.positionInBuffer >= len(buffer.loadedValue) {
-		return rune(0)
-	}
+	return buffer.symbol
+}
 
-	return rune(buffer.loadedValue[buffer.positionInBuffer])
+// Symbol after current one, without moving position
+func (buffer *Buffer) LookNextSymbol() rune {
+	var symbol, _ = buffer.decodeSymbol(buffer.offset + buffer.symbolSize)
+
+	return symbol
 }
 
 func (buffer *Buffer) GetPosition() int {
-	return buffer.position + buffer.positionInBuffer
+	return buffer.position
 }
 
 func (buffer *Buffer) GetIsEnd() bool {
-	return buffer.positionInBuffer == len(buffer.loadedValue) && buffer.isSourceEnd
+	return buffer.symbolSize == 0
+}
+
+// Error of reading source, nil when source is read till end
+func (buffer *Buffer) GetReadError() error {
+	return buffer.readErr
 }
 
 func (buffer *Buffer) GetReadedCode() string {
-	return buffer.code
+	return bytesToString(buffer.code)
 }
 
 func (buffer *Buffer) Next() {
@@ -52,98 +83,165 @@ func (buffer *Buffer) Next() {
 		return
 	}
 
-	buffer.positionInBuffer = buffer.positionInBuffer + 1
-
-	if buffer.positionInBuffer >= len(buffer.loadedValue) {
-		buffer.loadSymbol()
-	}
+	buffer.moveTo(buffer.offset+buffer.symbolSize, buffer.position+1)
 }
 
 func (buffer *Buffer) Reset() {
-	buffer.positionInBuffer = 0
+	buffer.moveTo(buffer.start, buffer.startPosition)
 }
 
 func (buffer *Buffer) TrimNext() {
-	for (buffer.GetSymbol() == ' ' || buffer.GetSymbol() == '\n' || buffer.GetSymbol() == '\t') && !buffer.GetIsEnd() {
+	for isWhitespace(buffer.GetSymbol()) && !buffer.GetIsEnd() {
 		buffer.Next()
 	}
 
-	buffer.Eat(buffer.positionInBuffer)
+	buffer.start = buffer.offset
+	buffer.startPosition = buffer.position
 }
 
 func (buffer *Buffer) AddSymbol() {
-	buffer.value = buffer.value + string(buffer.GetSymbol())
+	if buffer.GetIsEnd() {
+		return
+	}
+
+	var symbolEnd = buffer.offset + buffer.symbolSize
+
+	if buffer.isValueCopied {
+		buffer.copiedValue = append(buffer.copiedValue, buffer.code[buffer.offset:symbolEnd]...)
+		return
+	}
+
+	if buffer.valueStart == buffer.valueEnd {
+		buffer.valueStart = buffer.offset
+		buffer.valueEnd = symbolEnd
+		return
+	}
+
+	if buffer.valueEnd == buffer.offset {
+		buffer.valueEnd = symbolEnd
+		return
+	}
+
+	// Symbols are skipped inside value, like "_" in "1_000", so value is copied
+	buffer.copiedValue = append(buffer.copiedValue[:0], buffer.code[buffer.valueStart:buffer.valueEnd]...)
+	buffer.copiedValue = append(buffer.copiedValue, buffer.code[buffer.offset:symbolEnd]...)
+	buffer.isValueCopied = true
 }
 
 func (buffer *Buffer) IsStartsWithWord(word string) bool {
-	for len(buffer.loadedValue) < len(word) && !buffer.isSourceEnd {
-		buffer.loadSymbol()
+	if !buffer.IsStartsWith(word) {
+		return false
 	}
 
-	var isStartsWithWord = strings.HasPrefix(buffer.loadedValue, word)
+	var symbolAfterWord, symbolSize = buffer.decodeSymbol(buffer.start + len(word))
 
-	if !isStartsWithWord {
+	return symbolSize == 0 || !token.IsKeyWordSymbol(symbolAfterWord)
+}
+
+// Checks not eaten code, not the current symbol
+func (buffer *Buffer) IsStartsWith(value string) bool {
+	var end = buffer.start + len(value)
+
+	buffer.load(end)
+
+	if end > len(buffer.code) {
 		return false
 	}
 
-	if buffer.isSourceEnd {
-		return true
-	}
+	return string(buffer.code[buffer.start:end]) == value
+}
 
-	buffer.loadSymbol()
+// Removes symbols from start of not eaten code, current symbol is the first not eaten one
+func (buffer *Buffer) Eat(length int) {
+	buffer.moveTo(buffer.start, buffer.startPosition)
 
-	if buffer.isSourceEnd {
-		return true
+	for index := 0; index < length && !buffer.GetIsEnd(); index++ {
+		buffer.Next()
 	}
 
-	var symbolAfterWord = buffer.loadedValue[len(word)]
+	buffer.start = buffer.offset
+	buffer.startPosition = buffer.position
+}
 
-	return !token.IsKeyWordSymbol(rune(symbolAfterWord))
+func (buffer *Buffer) Clear() {
+	buffer.start = buffer.offset
+	buffer.startPosition = buffer.position
+	buffer.valueStart = 0
+	buffer.valueEnd = 0
+	buffer.isValueCopied = false
 }
 
-func (buffer *Buffer) Eat(length int) {
-	buffer.loadedValue = buffer.loadedValue[length:]
-	buffer.position = buffer.position + length
-	buffer.positionInBuffer = 0
+func (buffer *Buffer) moveTo(offset int, position int) {
+	buffer.offset = offset
+	buffer.position = position
+	buffer.symbol, buffer.symbolSize = buffer.decodeSymbol(offset)
 }
 
-func (buffer *Buffer) Clear() {
-	buffer.position = buffer.position + buffer.positionInBuffer
-	buffer.loadedValue = buffer.loadedValue[buffer.positionInBuffer:]
-	buffer.positionInBuffer = 0
-	buffer.value = ""
+// Symbol at byte offset and its size. Size is zero at end of code
+func (buffer *Buffer) decodeSymbol(offset int) (rune, int) {
+	buffer.load(offset + 1)
+
+	if offset >= len(buffer.code) {
+		return rune(0), 0
+	}
 
-	if len(buffer.loadedValue) == 0 {
-		buffer.loadSymbol()
+	// Source is read lazily, only bytes of symbol are loaded
+	for !utf8.FullRune(buffer.code[offset:]) && !buffer.isSourceEnd {
+		buffer.load(len(buffer.code) + 1)
 	}
+
+	return utf8.DecodeRune(buffer.code[offset:])
 }
 
-func (buffer *Buffer) loadSymbol() {
-	symbol, isEnd := buffer.source.NextSymbol()
+// Reads source, until code has size bytes or source ends
+func (buffer *Buffer) load(size int) {
+	for len(buffer.code) < size && !buffer.isSourceEnd {
+		if cap(buffer.code)-len(buffer.code) < readSize {
+			// New slice is allocated, so views of loaded code stay the same
+			var code = make([]byte, len(buffer.code), 2*cap(buffer.code)+readSize)
+			copy(code, buffer.code)
+			buffer.code = code
+		}
 
-	buffer.isSourceEnd = isEnd
+		var count, err = buffer.source.Read(buffer.code[len(buffer.code):cap(buffer.code)])
+		buffer.code = buffer.code[:len(buffer.code)+count]
+		buffer.isSourceEnd = err != nil
 
-	if isEnd {
-		return
+		if err != nil && err != io.EOF {
+			buffer.readErr = err
+		}
 	}
-
-	buffer.code = buffer.code + string(symbol)
-	buffer.loadedValue = buffer.loadedValue + string(symbol)
 }
 
-func CreateBuffer(source iSource) Buffer {
-	symbol, isSourceEnd := source.NextSymbol()
+// View of bytes, which are never changed after loading
+func bytesToString(value []byte) string {
+	if len(value) == 0 {
+		return ""
+	}
 
-	return Buffer{
-		value: "",
+	return *(*string)(unsafe.Pointer(&value))
+}
 
-		loadedValue:      string(symbol),
-		positionInBuffer: 0,
-		code:             string(symbol),
-		position:         0,
-		isSourceEnd:      isSourceEnd,
+func isWhitespace(symbol rune) bool {
+	return symbol == ' ' || symbol == '\n' || symbol == '\t' || symbol == '\r'
+}
 
-		// Inner props
+func CreateBuffer(source io.Reader) Buffer {
+	var buffer = Buffer{
 		source: source,
 	}
+
+	buffer.load(1)
+
+	if len(buffer.code) > 0 && buffer.code[0] == byteOrderMark[0] {
+		buffer.load(len(byteOrderMark))
+	}
+
+	if bytes.HasPrefix(buffer.code, byteOrderMark) {
+		buffer.code = buffer.code[len(byteOrderMark):]
+	}
+
+	buffer.moveTo(0, 0)
+
+	return buffer
 }
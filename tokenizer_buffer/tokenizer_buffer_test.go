@@ -1,8 +1,11 @@
 package tokenizer_buffer
 
 import (
-	"reflect"
+	"errors"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/VadimZvf/golang/source_mock"
 )
@@ -14,8 +17,8 @@ func TestCreateBuffer(t *testing.T) {
 		t.Errorf("Buffer interface should contain isEnd flag")
 	}
 
-	if reflect.TypeOf(buffer.value).Name() != "string" {
-		t.Errorf("Buffer interface should contain value prop")
+	if buffer.GetValue() != "" || buffer.GetSymbol() != rune(0) {
+		t.Errorf("Buffer should start with empty value at the first symbol")
 	}
 }
 
@@ -149,3 +152,138 @@ func TestClear(t *testing.T) {
 		t.Errorf("Buffer should save position after clear")
 	}
 }
+
+func TestTrimNextWithCarriageReturn(t *testing.T) {
+	var source = source_mock.GetSourceMock("\r\n\t wow")
+	buffer := CreateBuffer(source)
+	buffer.TrimNext()
+	symbol := buffer.GetSymbol()
+	if symbol != 'w' {
+		t.Errorf("Buffer should trim \"\\r\\n\". But received: %c", symbol)
+	}
+
+	position := buffer.GetPosition()
+	if position != 4 {
+		t.Errorf("Buffer should save position after trim. But received: %d", position)
+	}
+}
+
+func TestUnicodeSymbols(t *testing.T) {
+	var source = source_mock.GetSourceMock("\uFEFFпривет мир")
+	buffer := CreateBuffer(source)
+
+	for buffer.GetSymbol() != ' ' {
+		buffer.AddSymbol()
+		buffer.Next()
+	}
+
+	if buffer.GetValue() != "привет" {
+		t.Errorf("Buffer should read unicode symbols. But received: %s", buffer.GetValue())
+	}
+
+	position := buffer.GetPosition()
+	if position != 6 {
+		t.Errorf("Buffer should count position in symbols. But received: %d", position)
+	}
+
+	if buffer.LookNextSymbol() != 'м' {
+		t.Errorf("Buffer should look at next unicode symbol. But received: %c", buffer.LookNextSymbol())
+	}
+}
+
+func TestReadByOneByte(t *testing.T) {
+	var source = iotest.OneByteReader(strings.NewReader("\uFEFFмир 42"))
+	buffer := CreateBuffer(source)
+
+	for !buffer.GetIsEnd() {
+		buffer.AddSymbol()
+		buffer.Next()
+	}
+
+	if buffer.GetValue() != "мир 42" {
+		t.Errorf("Buffer should decode symbols split between reads. But received: %s", buffer.GetValue())
+	}
+
+	if buffer.GetPosition() != 6 || buffer.GetReadedCode() != "мир 42" {
+		t.Errorf("Buffer should skip byte order mark. But received: %d %s", buffer.GetPosition(), buffer.GetReadedCode())
+	}
+}
+
+func TestValueWithSkippedSymbols(t *testing.T) {
+	var source = source_mock.GetSourceMock("1_000 ab")
+	buffer := CreateBuffer(source)
+
+	for buffer.GetSymbol() != ' ' {
+		if buffer.GetSymbol() != '_' {
+			buffer.AddSymbol()
+		}
+
+		buffer.Next()
+	}
+
+	if buffer.GetValue() != "1000" {
+		t.Errorf("Buffer should save value without skipped symbols. But received: %s", buffer.GetValue())
+	}
+
+	buffer.TrimNext()
+	buffer.Clear()
+	buffer.AddSymbol()
+	buffer.Next()
+	buffer.AddSymbol()
+
+	if buffer.GetValue() != "ab" {
+		t.Errorf("Buffer should start new value after clear. But received: %s", buffer.GetValue())
+	}
+}
+
+func TestIsStartsWithWord(t *testing.T) {
+	buffer := CreateBuffer(source_mock.GetSourceMock("  while whilex"))
+	buffer.TrimNext()
+
+	if !buffer.IsStartsWithWord("while") {
+		t.Errorf("Buffer should find word")
+	}
+
+	buffer.Eat(5)
+	buffer.TrimNext()
+
+	if buffer.IsStartsWithWord("while") || !buffer.IsStartsWith("while") {
+		t.Errorf("Word should be followed by not keyword symbol")
+	}
+
+	if buffer.GetPosition() != 8 {
+		t.Errorf("Buffer should move position after eat. But received: %d", buffer.GetPosition())
+	}
+}
+
+func TestReadError(t *testing.T) {
+	var readErr = errors.New("disk failed")
+	buffer := CreateBuffer(io.MultiReader(strings.NewReader("ab"), &failingReader{readErr}))
+
+	for !buffer.GetIsEnd() {
+		buffer.Next()
+	}
+
+	if buffer.GetReadError() != readErr || buffer.GetReadedCode() != "ab" {
+		t.Errorf("Buffer should keep error of source after loaded code. But received: %v %s", buffer.GetReadError(), buffer.GetReadedCode())
+	}
+
+	buffer = CreateBuffer(source_mock.GetSourceMock("ab"))
+
+	for !buffer.GetIsEnd() {
+		buffer.Next()
+	}
+
+	if buffer.GetReadError() != nil {
+		t.Errorf("End of source should not be error. But received: %v", buffer.GetReadError())
+	}
+}
+
+// Source, which fails on every read
+type failingReader struct {
+	err error
+}
+
+func (reader *failingReader) Read(bytes []byte) (int, error) {
+	return 0, reader.err
+}
@@ -0,0 +1,841 @@
+package type_checker
+
+import (
+	"github.com/VadimZvf/golang/ast_node"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/token_number"
+)
+
+// Variable with type annotation, or with type inferred from assigned values
+type variable struct {
+	annotation string
+	inferred   string
+	// Declaration of function, arguments of calls are checked by it
+	function     *ast_node.ASTNode
+	isReassigned bool
+}
+
+func (v *variable) getType() string {
+	if v.annotation != "" {
+		return v.annotation
+	}
+
+	return v.inferred
+}
+
+// Variable is identified by node, which declares it, so it is the same between passes
+type variableKey struct {
+	declaration *ast_node.ASTNode
+	name        string
+}
+
+type scope struct {
+	parent    *scope
+	variables map[string]*variable
+}
+
+type checker struct {
+	variables map[variableKey]*variable
+	scope     *scope
+	// Function, which body is checked now
+	function    *ast_node.ASTNode
+	diagnostics []parser_error.ParserError
+	isChanged   bool
+}
+
+// Checks annotated types of program without running it. Unannotated variables
+// get type of assigned values, or dynamic type, when values have different types
+func Check(root *ast_node.ASTNode) []parser_error.ParserError {
+	var c = checker{
+		variables: map[variableKey]*variable{},
+	}
+
+	// Variable can be read before assignment, like in loop or closure,
+	// so passes are repeated till inferred types are stable
+	for {
+		c.diagnostics = []parser_error.ParserError{}
+		c.isChanged = false
+		c.scope = &scope{variables: map[string]*variable{}}
+		c.function = nil
+
+		c.checkStatements(root.Body)
+
+		if !c.isChanged {
+			return c.diagnostics
+		}
+	}
+}
+
+func (c *checker) report(message string, node *ast_node.ASTNode) {
+	var startPosition, endPosition = getNodeRange(node)
+
+	c.diagnostics = append(c.diagnostics, parser_error.CreateError(message, startPosition, endPosition))
+}
+
+func (c *checker) pushScope() {
+	c.scope = &scope{
+		parent:    c.scope,
+		variables: map[string]*variable{},
+	}
+}
+
+func (c *checker) popScope() {
+	c.scope = c.scope.parent
+}
+
+func (c *checker) declare(declaration *ast_node.ASTNode, name string, annotation *ast_node.ASTNodeParam) *variable {
+	var key = variableKey{declaration: declaration, name: name}
+	var v = c.variables[key]
+
+	if v == nil {
+		v = &variable{}
+		c.variables[key] = v
+	}
+
+	if annotation != nil {
+		v.annotation = c.getAnnotationType(annotation)
+	}
+
+	c.scope.variables[name] = v
+
+	return v
+}
+
+// Unknown type names are reported and treated as dynamic type
+func (c *checker) getAnnotationType(annotation *ast_node.ASTNodeParam) string {
+	if !IsKnownType(annotation.Value) {
+		c.diagnostics = append(c.diagnostics, parser_error.CreateError(
+			CreateUnknownTypeMessage(annotation.Value),
+			annotation.StartPosition,
+			annotation.EndPosition,
+		))
+
+		return TYPE_ANY
+	}
+
+	return annotation.Value
+}
+
+func (c *checker) resolve(name string) *variable {
+	for s := c.scope; s != nil; s = s.parent {
+		if v, ok := s.variables[name]; ok {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func (c *checker) assign(v *variable, name string, valueType string, valueNode *ast_node.ASTNode) {
+	if v.annotation != "" {
+		if !IsAssignable(v.annotation, valueType) {
+			c.report(CreateMismatchMessage("variable "+name, v.annotation, valueType), valueNode)
+		}
+
+		return
+	}
+
+	var joined = joinTypes(v.inferred, valueType)
+
+	if joined != v.inferred {
+		v.inferred = joined
+		c.isChanged = true
+	}
+}
+
+// Function declarations are hoisted, so they are declared before other statements.
+// Decorated functions are declared in place, like in runtime
+func (c *checker) checkStatements(nodes []*ast_node.ASTNode) {
+	for _, node := range nodes {
+		if ast_node.IsHoisted(node) {
+			c.declareFunction(node)
+		}
+	}
+
+	for index, node := range nodes {
+		if node.Code != ast_node.AST_NODE_CODE_VARIABLE_DECLARATION {
+			c.getType(node)
+			continue
+		}
+
+		var nameParam = ast_node.GetVariableNameParam(node)
+
+		if nameParam == nil {
+			continue
+		}
+
+		var v = c.declare(node, nameParam.Value, ast_node.GetVariableTypeParam(node))
+		var isInitialized = index+1 < len(nodes) && isInitialization(nodes[index+1], nameParam)
+
+		// Declared without value, so variable is unknown till assignment
+		if !isInitialized && v.annotation == "" {
+			c.assign(v, nameParam.Value, TYPE_UNKNOWN, node)
+		}
+	}
+}
+
+// Declaration with value is parsed as declaration followed by assignment to its name
+func isInitialization(node *ast_node.ASTNode, nameParam *ast_node.ASTNodeParam) bool {
+	if node.Code != ast_node.AST_NODE_CODE_ASSIGNMENT || len(node.Body) == 0 {
+		return false
+	}
+
+	var referenceNameParam = ast_node.GetVariableNameParam(node.Body[0])
+
+	return referenceNameParam != nil && referenceNameParam.StartPosition == nameParam.StartPosition
+}
+
+func (c *checker) declareFunction(node *ast_node.ASTNode) *variable {
+	var nameParam = ast_node.GetFunctionNameParam(node)
+
+	if nameParam == nil {
+		return nil
+	}
+
+	var v = c.declare(node, nameParam.Value, nil)
+
+	// Decorators can replace function with any value, so its declaration doesn't describe calls
+	if len(ast_node.GetFunctionDecorators(node)) > 0 {
+		c.assign(v, nameParam.Value, TYPE_ANY, node)
+		return v
+	}
+
+	if v.function == nil && !v.isReassigned {
+		v.function = node
+	}
+
+	c.assign(v, nameParam.Value, TYPE_FUNCTION, node)
+
+	return v
+}
+
+// Returns type of node value and reports mismatches inside of it
+func (c *checker) getType(node *ast_node.ASTNode) string {
+	switch node.Code {
+	case ast_node.AST_NODE_CODE_NUMBER:
+		var valueParam = ast_node.GetNumberValueParam(node)
+
+		if valueParam != nil && token_number.IsInteger(valueParam.Value) {
+			return TYPE_INTEGER
+		}
+
+		return TYPE_NUMBER
+
+	case ast_node.AST_NODE_CODE_STRING:
+		return TYPE_STRING
+
+	case ast_node.AST_NODE_CODE_BOOLEAN:
+		return TYPE_BOOLEAN
+
+	case ast_node.AST_NODE_CODE_REFERENCE:
+		return c.getReferenceType(node)
+
+	case ast_node.AST_NODE_CODE_PARENTHESIZED_EXPRESSION:
+		var valueType = TYPE_ANY
+
+		for _, bodyNode := range node.Body {
+			valueType = c.getType(bodyNode)
+		}
+
+		return valueType
+
+	case ast_node.AST_NODE_CODE_BINARY_EXPRESSION:
+		return c.getBinaryExpressionType(node)
+
+	case ast_node.AST_NODE_CODE_UNARY_EXPRESSION:
+		return c.getUnaryExpressionType(node)
+
+	case ast_node.AST_NODE_CODE_ASSIGNMENT:
+		return c.getAssignmentType(node)
+
+	case ast_node.AST_NODE_CODE_CALL_EXPRESSION:
+		return c.getCallType(node)
+
+	case ast_node.AST_NODE_CODE_NAMED_ARGUMENT:
+		return c.getType(node.Body[0])
+
+	case ast_node.AST_NODE_CODE_FUNCTION:
+		c.checkFunction(node)
+		return TYPE_FUNCTION
+
+	case ast_node.AST_NODE_CODE_RETURN:
+		c.checkReturn(node)
+		return TYPE_ANY
+
+	case ast_node.AST_NODE_CODE_BLOCK:
+		c.checkStatements(node.Body)
+		return TYPE_ANY
+
+	case ast_node.AST_NODE_CODE_WHILE:
+		c.getChildrenType(node.Arguments)
+
+		// Every iteration has own scope
+		c.pushScope()
+		c.checkStatements(node.Body)
+		c.popScope()
+
+		return TYPE_ANY
+
+	case ast_node.AST_NODE_CODE_FOR_OF:
+		c.getChildrenType(node.Arguments)
+
+		c.pushScope()
+
+		var itemName = ast_node.GetVariableNameParam(node)
+
+		if itemName != nil {
+			c.assign(c.declare(node, itemName.Value, nil), itemName.Value, TYPE_ANY, node)
+		}
+
+		c.checkStatements(node.Body)
+		c.popScope()
+
+		return TYPE_ANY
+
+	case ast_node.AST_NODE_CODE_MATCH:
+		return c.getMatchType(node)
+
+	case ast_node.AST_NODE_CODE_READ_PROP:
+		return c.getReadPropType(node)
+
+	case ast_node.AST_NODE_CODE_ENUM:
+		var enumName = ast_node.GetVariableNameParam(node)
+
+		if enumName != nil {
+			c.assign(c.declare(node, enumName.Value, nil), enumName.Value, TYPE_ENUM, node)
+		}
+
+		return TYPE_ENUM
+
+	case ast_node.AST_NODE_CODE_SPAWN:
+		c.getChildrenType(node.Body)
+		return TYPE_PROMISE
+	}
+
+	c.getChildrenType(node.Body)
+	c.getChildrenType(node.Arguments)
+
+	return TYPE_ANY
+}
+
+// Checks nodes and returns type of last one
+func (c *checker) getChildrenType(nodes []*ast_node.ASTNode) string {
+	var valueType = TYPE_ANY
+
+	for _, node := range nodes {
+		valueType = c.getType(node)
+	}
+
+	return valueType
+}
+
+func (c *checker) getReferenceType(node *ast_node.ASTNode) string {
+	var nameParam = ast_node.GetVariableNameParam(node)
+
+	if nameParam == nil {
+		return TYPE_ANY
+	}
+
+	var v = c.resolve(nameParam.Value)
+
+	if v == nil {
+		// Build in function, or variable, which is not declared at all
+		return TYPE_ANY
+	}
+
+	return v.getType()
+}
+
+func (c *checker) getBinaryExpressionType(node *ast_node.ASTNode) string {
+	if len(node.Body) != 2 {
+		c.getChildrenType(node.Body)
+		return TYPE_ANY
+	}
+
+	if ast_node.IsPipe(node) {
+		return c.getCallType(ast_node.CreatePipeCall(node))
+	}
+
+	var leftType = c.getType(node.Body[0])
+	var rightType = c.getType(node.Body[1])
+	var operator = ast_node.GetBinaryExpressionTypeParam(node)
+
+	if operator == nil {
+		return TYPE_ANY
+	}
+
+	switch operator.Value {
+	case "==", "!=", ">", "<", ">=", "<=":
+		return TYPE_BOOLEAN
+	}
+
+	if leftType == typeNone || rightType == typeNone {
+		return typeNone
+	}
+
+	if leftType == TYPE_INTEGER && rightType == TYPE_INTEGER {
+		if operator.Value == ">>>" {
+			c.report(CreateMismatchMessage("operator "+operator.Value, TYPE_NUMBER, TYPE_INTEGER), node)
+			return TYPE_ANY
+		}
+
+		return TYPE_INTEGER
+	}
+
+	// Integer mixed with float is converted to float
+	if isNumericType(leftType) && isNumericType(rightType) {
+		return TYPE_NUMBER
+	}
+
+	if leftType == TYPE_ANY || rightType == TYPE_ANY {
+		return TYPE_ANY
+	}
+
+	// Other values are concatenated as strings
+	if operator.Value == "+" {
+		return TYPE_STRING
+	}
+
+	var actualType = leftType
+
+	if isNumericType(leftType) {
+		actualType = rightType
+	}
+
+	c.report(CreateMismatchMessage("operator "+operator.Value, TYPE_NUMBER, actualType), node)
+
+	return TYPE_ANY
+}
+
+func (c *checker) getUnaryExpressionType(node *ast_node.ASTNode) string {
+	var operandType = c.getChildrenType(node.Body)
+	var operator = ast_node.GetUnaryExpressionTypeParam(node)
+
+	if operator == nil {
+		return TYPE_ANY
+	}
+
+	switch operator.Value {
+	case "typeof":
+		return TYPE_STRING
+	case "await":
+		// Awaited call of async function gives its returned value
+		if len(node.Body) == 1 && node.Body[0].Code == ast_node.AST_NODE_CODE_CALL_EXPRESSION {
+			var function = c.getCalledFunction(node.Body[0])
+
+			if function != nil && ast_node.IsAsyncFunction(function) {
+				return c.getReturnType(function)
+			}
+		}
+
+		if operandType == TYPE_PROMISE {
+			return TYPE_ANY
+		}
+
+		return operandType
+	case "~":
+		if operandType == typeNone || operandType == TYPE_ANY || isNumericType(operandType) {
+			return operandType
+		}
+
+		c.report(CreateMismatchMessage("operator ~", TYPE_NUMBER, operandType), node)
+	}
+
+	return TYPE_ANY
+}
+
+func (c *checker) getAssignmentType(node *ast_node.ASTNode) string {
+	if len(node.Body) != 2 {
+		c.getChildrenType(node.Body)
+		return TYPE_ANY
+	}
+
+	var valueType = c.getType(node.Body[1])
+	var nameParam = ast_node.GetVariableNameParam(node.Body[0])
+
+	if node.Body[0].Code != ast_node.AST_NODE_CODE_REFERENCE || nameParam == nil {
+		c.getType(node.Body[0])
+		return valueType
+	}
+
+	var v = c.resolve(nameParam.Value)
+
+	if v == nil {
+		return valueType
+	}
+
+	// Function can be replaced, so its declaration doesn't describe calls anymore
+	if v.function != nil && !v.isReassigned {
+		v.isReassigned = true
+		c.isChanged = true
+	}
+
+	c.assign(v, nameParam.Value, valueType, node.Body[1])
+
+	return valueType
+}
+
+// Returns declaration of called function, when it is known for sure
+func (c *checker) getCalledFunction(node *ast_node.ASTNode) *ast_node.ASTNode {
+	if len(node.Body) == 0 || node.Body[0].Code != ast_node.AST_NODE_CODE_REFERENCE {
+		return nil
+	}
+
+	var nameParam = ast_node.GetVariableNameParam(node.Body[0])
+
+	if nameParam == nil {
+		return nil
+	}
+
+	var v = c.resolve(nameParam.Value)
+
+	if v == nil || v.isReassigned {
+		return nil
+	}
+
+	return v.function
+}
+
+func (c *checker) getCallType(node *ast_node.ASTNode) string {
+	c.getChildrenType(node.Body)
+
+	var argumentsTypes = map[*ast_node.ASTNode]string{}
+
+	for _, argumentNode := range node.Arguments {
+		argumentsTypes[argumentNode] = c.getType(argumentNode)
+	}
+
+	var function = c.getCalledFunction(node)
+
+	if function == nil {
+		return getNativeResultType(node)
+	}
+
+	var functionName = ast_node.GetFunctionNameParam(function).Value
+
+	for index, argument := range ast_node.GetFunctionArguments(function) {
+		var argumentNode = ast_node.FindCallArgument(node, index, argument.Name.Value)
+
+		if argument.Type == nil || !IsKnownType(argument.Type.Value) {
+			continue
+		}
+
+		// Missing argument is unknown
+		if argumentNode == nil {
+			if !IsAssignable(argument.Type.Value, TYPE_UNKNOWN) {
+				c.report(
+					CreateMismatchMessage("argument "+argument.Name.Value+" of function "+functionName, argument.Type.Value, TYPE_UNKNOWN),
+					node,
+				)
+			}
+
+			continue
+		}
+
+		if !IsAssignable(argument.Type.Value, argumentsTypes[argumentNode]) {
+			c.report(
+				CreateMismatchMessage("argument "+argument.Name.Value+" of function "+functionName, argument.Type.Value, argumentsTypes[argumentNode]),
+				argumentNode,
+			)
+		}
+	}
+
+	if ast_node.IsGeneratorFunction(function) {
+		return TYPE_GENERATOR
+	}
+
+	if ast_node.IsAsyncFunction(function) {
+		return TYPE_PROMISE
+	}
+
+	return c.getReturnType(function)
+}
+
+func getNativeResultType(node *ast_node.ASTNode) string {
+	if len(node.Body) == 0 || node.Body[0].Code != ast_node.AST_NODE_CODE_REFERENCE {
+		return TYPE_ANY
+	}
+
+	var nameParam = ast_node.GetVariableNameParam(node.Body[0])
+
+	if nameParam == nil {
+		return TYPE_ANY
+	}
+
+	var resultType, isFound = nativeResultTypes[nameParam.Value]
+
+	if !isFound {
+		return TYPE_ANY
+	}
+
+	return resultType
+}
+
+// Annotated type of returned value, or dynamic type without annotation
+func (c *checker) getReturnType(function *ast_node.ASTNode) string {
+	var returnType = ast_node.GetFunctionReturnTypeParam(function)
+
+	if returnType == nil || !IsKnownType(returnType.Value) {
+		return TYPE_ANY
+	}
+
+	return returnType.Value
+}
+
+func (c *checker) checkFunction(node *ast_node.ASTNode) {
+	c.declareFunction(node)
+
+	var returnType = ast_node.GetFunctionReturnTypeParam(node)
+
+	if returnType != nil {
+		c.getAnnotationType(returnType)
+	}
+
+	var parentFunction = c.function
+	c.function = node
+	c.pushScope()
+
+	for _, argument := range ast_node.GetFunctionArguments(node) {
+		var v = c.declare(node, argument.Name.Value, argument.Type)
+
+		// Arguments without annotation can get any value
+		if argument.Type == nil {
+			c.assign(v, argument.Name.Value, TYPE_ANY, node)
+		}
+	}
+
+	c.getChildrenType(node.Body)
+
+	// Function, which finishes without return, returns unknown value
+	if returnType != nil && IsKnownType(returnType.Value) && !IsAssignable(returnType.Value, TYPE_UNKNOWN) && !isReturning(node.Body) {
+		var functionName = ast_node.GetFunctionNameParam(node).Value
+		c.diagnostics = append(c.diagnostics, parser_error.CreateError(
+			CreateMismatchMessage("return value of function "+functionName, returnType.Value, TYPE_UNKNOWN),
+			returnType.StartPosition,
+			returnType.EndPosition,
+		))
+	}
+
+	c.popScope()
+	c.function = parentFunction
+}
+
+// Checks, that statements can't finish without return
+func isReturning(nodes []*ast_node.ASTNode) bool {
+	for _, node := range nodes {
+		switch node.Code {
+		case ast_node.AST_NODE_CODE_RETURN:
+			return true
+
+		case ast_node.AST_NODE_CODE_BLOCK:
+			if isReturning(node.Body) {
+				return true
+			}
+
+		// Loop "while (true)" without break finishes only by return
+		case ast_node.AST_NODE_CODE_WHILE:
+			if len(node.Arguments) == 1 && isTrue(node.Arguments[0]) && !hasBreak(node.Body) {
+				return true
+			}
+
+		case ast_node.AST_NODE_CODE_MATCH:
+			if isMatchReturning(node) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isTrue(node *ast_node.ASTNode) bool {
+	if node.Code == ast_node.AST_NODE_CODE_PARENTHESIZED_EXPRESSION && len(node.Body) == 1 {
+		return isTrue(node.Body[0])
+	}
+
+	var valueParam = ast_node.GetBooleanValueParam(node)
+
+	return node.Code == ast_node.AST_NODE_CODE_BOOLEAN && valueParam != nil && valueParam.Value == "true"
+}
+
+// Break of nested loop or function doesn't stop outer loop
+func hasBreak(nodes []*ast_node.ASTNode) bool {
+	for _, node := range nodes {
+		switch node.Code {
+		case ast_node.AST_NODE_CODE_BREAK:
+			return true
+
+		case ast_node.AST_NODE_CODE_WHILE, ast_node.AST_NODE_CODE_FOR_OF, ast_node.AST_NODE_CODE_FUNCTION:
+			continue
+		}
+
+		if hasBreak(node.Body) || hasBreak(node.Arguments) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match returns, when it has arm for any value and every arm returns
+func isMatchReturning(node *ast_node.ASTNode) bool {
+	var hasFallbackArm = false
+
+	for _, armNode := range node.Body {
+		var isGuarded = false
+		var values = []*ast_node.ASTNode{}
+
+		for _, bodyNode := range armNode.Body {
+			if bodyNode.Code == ast_node.AST_NODE_CODE_MATCH_GUARD {
+				isGuarded = true
+				continue
+			}
+
+			values = append(values, bodyNode)
+		}
+
+		if !isReturning(values) {
+			return false
+		}
+
+		for _, patternNode := range armNode.Arguments {
+			if patternNode.Code == ast_node.AST_NODE_CODE_MATCH_WILDCARD || patternNode.Code == ast_node.AST_NODE_CODE_MATCH_BINDING {
+				hasFallbackArm = hasFallbackArm || !isGuarded
+			}
+		}
+	}
+
+	return hasFallbackArm
+}
+
+func (c *checker) checkReturn(node *ast_node.ASTNode) {
+	var valueType = TYPE_UNKNOWN
+
+	if len(node.Body) > 0 {
+		valueType = c.getChildrenType(node.Body)
+	}
+
+	if c.function == nil {
+		return
+	}
+
+	var returnType = ast_node.GetFunctionReturnTypeParam(c.function)
+
+	if returnType == nil || !IsKnownType(returnType.Value) {
+		return
+	}
+
+	if !IsAssignable(returnType.Value, valueType) {
+		var functionName = ast_node.GetFunctionNameParam(c.function).Value
+		c.report(CreateMismatchMessage("return value of function "+functionName, returnType.Value, valueType), node)
+	}
+}
+
+// Type of match is common type of its arms results
+func (c *checker) getMatchType(node *ast_node.ASTNode) string {
+	c.getChildrenType(node.Arguments)
+
+	var resultType = typeNone
+	var hasFallbackArm = false
+
+	for _, armNode := range node.Body {
+		c.pushScope()
+
+		var isGuarded = false
+
+		for _, bodyNode := range armNode.Body {
+			if bodyNode.Code == ast_node.AST_NODE_CODE_MATCH_GUARD {
+				isGuarded = true
+			}
+		}
+
+		for _, patternNode := range armNode.Arguments {
+			switch patternNode.Code {
+			case ast_node.AST_NODE_CODE_MATCH_WILDCARD:
+				hasFallbackArm = hasFallbackArm || !isGuarded
+			case ast_node.AST_NODE_CODE_MATCH_BINDING:
+				hasFallbackArm = hasFallbackArm || !isGuarded
+
+				var bindingName = ast_node.GetVariableNameParam(patternNode)
+
+				if bindingName != nil {
+					c.assign(c.declare(patternNode, bindingName.Value, nil), bindingName.Value, TYPE_ANY, patternNode)
+				}
+			default:
+				c.getType(patternNode)
+			}
+		}
+
+		var armType = TYPE_ANY
+
+		for _, bodyNode := range armNode.Body {
+			if bodyNode.Code == ast_node.AST_NODE_CODE_MATCH_GUARD {
+				c.getChildrenType(bodyNode.Body)
+				continue
+			}
+
+			armType = c.getType(bodyNode)
+		}
+
+		resultType = joinTypes(resultType, armType)
+
+		c.popScope()
+	}
+
+	// Without matched arm value is unknown
+	if !hasFallbackArm {
+		resultType = joinTypes(resultType, TYPE_UNKNOWN)
+	}
+
+	return resultType
+}
+
+func (c *checker) getReadPropType(node *ast_node.ASTNode) string {
+	var objectType = c.getChildrenType(node.Body)
+	var propertyName = ast_node.GetParam(node, ast_node.AST_PARAM_PROPERTY_NAME)
+
+	if propertyName == nil {
+		return TYPE_ANY
+	}
+
+	switch {
+	case objectType == TYPE_STRING && propertyName.Value == "length":
+		return TYPE_NUMBER
+	case (objectType == TYPE_MAP || objectType == TYPE_SET) && propertyName.Value == "size":
+		return TYPE_NUMBER
+	case objectType == TYPE_ENUM:
+		return TYPE_ENUM_MEMBER
+	case objectType == TYPE_ENUM_MEMBER && propertyName.Value == "name":
+		return TYPE_STRING
+	}
+
+	return TYPE_ANY
+}
+
+// Positions of node with all nested nodes, like both operands of binary expression
+func getNodeRange(node *ast_node.ASTNode) (int, int) {
+	var startPosition = node.StartPosition
+	var endPosition = node.EndPosition
+
+	for _, param := range node.Params {
+		if param.EndPosition > endPosition {
+			endPosition = param.EndPosition
+		}
+	}
+
+	for _, children := range [][]*ast_node.ASTNode{node.Body, node.Arguments} {
+		for _, child := range children {
+			var childStart, childEnd = getNodeRange(child)
+
+			if childStart < startPosition {
+				startPosition = childStart
+			}
+
+			if childEnd > endPosition {
+				endPosition = childEnd
+			}
+		}
+	}
+
+	return startPosition, endPosition
+}
@@ -0,0 +1,409 @@
+package type_checker
+
+import (
+	"testing"
+
+	"github.com/VadimZvf/golang/parser"
+	"github.com/VadimZvf/golang/parser_error"
+	"github.com/VadimZvf/golang/source_mock"
+	"github.com/VadimZvf/golang/stdout_mock"
+)
+
+func TestAnnotatedVariable(t *testing.T) {
+	var diagnostics = checkCode(t, `var a: number = "text";`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, variable a expects number, but received string",
+		StartPosition: 16,
+		EndPosition:   21,
+	}})
+}
+
+func TestAnnotatedVariableAssignment(t *testing.T) {
+	var diagnostics = checkCode(t, `
+var a: integer = 1n;
+a = a + 2n;
+a = 1.5;
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, variable a expects integer, but received number",
+		StartPosition: 38,
+		EndPosition:   40,
+	}})
+}
+
+func TestVariableWithoutValue(t *testing.T) {
+	var diagnostics = checkCode(t, `
+var a: string;
+a = "text";
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{})
+}
+
+func TestFunctionArguments(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function greet(name: string, times: number) {
+	return name;
+}
+
+greet("Ann", 2);
+greet(1, "2");
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{
+		{
+			Message:       "Type error, argument name of function greet expects string, but received number",
+			StartPosition: 87,
+			EndPosition:   87,
+		},
+		{
+			Message:       "Type error, argument times of function greet expects number, but received string",
+			StartPosition: 90,
+			EndPosition:   92,
+		},
+	})
+}
+
+func TestMissingArguments(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function greet(name: string, times: number, tag: any, note) {
+	return name;
+}
+
+greet("Ann", 2);
+greet(times: 2);
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{
+		{
+			Message:       "Type error, argument name of function greet expects string, but received unknown",
+			StartPosition: 97,
+			EndPosition:   111,
+		},
+	})
+}
+
+func TestFunctionReturnType(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function size(): number {
+	return "big";
+}
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, return value of function size expects number, but received string",
+		StartPosition: 28,
+		EndPosition:   39,
+	}})
+}
+
+func TestFunctionWithoutReturn(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function empty(): number {
+}
+function matched(x): number {
+	match (x) { 1 => { return 1 }, _ => { return 2 } }
+}
+function partial(x): number {
+	match (x) { 1 => { return 1 } }
+}
+function loop(): number {
+	while (true) { return 1 }
+}
+function stopped(): number {
+	while (true) { break }
+}
+function dynamic(): any {
+}
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{
+		{
+			Message:       "Type error, return value of function empty expects number, but received unknown",
+			StartPosition: 19,
+			EndPosition:   24,
+		},
+		{
+			Message:       "Type error, return value of function partial expects number, but received unknown",
+			StartPosition: 135,
+			EndPosition:   140,
+		},
+		{
+			Message:       "Type error, return value of function stopped expects number, but received unknown",
+			StartPosition: 254,
+			EndPosition:   259,
+		},
+	})
+}
+
+func TestCallResultType(t *testing.T) {
+	var diagnostics = checkCode(t, `
+var total: string = summ(1, 2);
+
+function summ(a: number, b: number): number {
+	return a + b;
+}
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, variable total expects string, but received number",
+		StartPosition: 21,
+		EndPosition:   30,
+	}})
+}
+
+func TestInferredVariable(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function double(value: number) {
+	return value * 2;
+}
+
+var count = 1;
+var label = "a";
+double(count);
+double(label);
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, argument value of function double expects number, but received string",
+		StartPosition: 110,
+		EndPosition:   114,
+	}})
+}
+
+func TestVariableWithDifferentValuesIsDynamic(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function double(value: number) {
+	return value * 2;
+}
+
+var value = 1;
+var i = 0;
+while (i < 2) {
+	double(value);
+	value = "a";
+	i = i + 1;
+}
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{})
+}
+
+func TestUnannotatedCodeIsDynamic(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function echo(value) {
+	return value;
+}
+
+var a: number = echo("text");
+var b: string = receive(chan(1));
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{})
+}
+
+func TestOperatorTypes(t *testing.T) {
+	var diagnostics = checkCode(t, `
+var a: string = 1 + "a";
+var b: boolean = 1 > 2;
+var c: number = 1n + 1.5;
+var d = "a" - 1;
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, operator - expects number, but received string",
+		StartPosition: 84,
+		EndPosition:   90,
+	}})
+}
+
+func TestUnknownType(t *testing.T) {
+	var diagnostics = checkCode(t, `function f(a: text) {}`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Unknown type: text",
+		StartPosition: 14,
+		EndPosition:   17,
+	}})
+}
+
+func TestAsyncFunctionTypes(t *testing.T) {
+	var diagnostics = checkCode(t, `
+async function load(): string {
+	return "data";
+}
+
+async function main() {
+	var data: string = await load();
+	var pending: promise = load();
+	var wrong: number = await load();
+}
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, variable wrong expects number, but received string",
+		StartPosition: 163,
+		EndPosition:   174,
+	}})
+}
+
+func TestMatchType(t *testing.T) {
+	var diagnostics = checkCode(t, `
+var x = 1;
+var a: string = match (x) { 1 => "one", _ => "other" };
+var b: string = match (x) { 1 => "one" };
+var c: number = match (x) { 1 => "one", _ => "other" };
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, variable c expects number, but received string",
+		StartPosition: 126,
+		EndPosition:   163,
+	}})
+}
+
+func TestReassignedFunctionIsNotChecked(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function f(a: number) {}
+f = print;
+f("text");
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{})
+}
+
+func TestEnumTypes(t *testing.T) {
+	var diagnostics = checkCode(t, `
+enum Color { Red, Green }
+var palette: enum = Color;
+var color: enum_member = Color.Red;
+var name: string = Color.Red.name;
+var wrong: number = Color.Green;
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, variable wrong expects number, but received enum_member",
+		StartPosition: 145,
+		EndPosition:   155,
+	}})
+}
+
+func TestNamedArgumentTypes(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function createUser(name: string, admin: boolean) {}
+createUser(admin: true, name: "Ann");
+createUser("Bob", admin: 1);
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, argument admin of function createUser expects boolean, but received number",
+		StartPosition: 110,
+		EndPosition:   117,
+	}})
+}
+
+func TestBitwiseTypes(t *testing.T) {
+	var diagnostics = checkCode(t, `
+var a: number = 5 & 3 << 1;
+var b: integer = ~5n | 1n;
+var c = ~"a";
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, operator ~ expects number, but received string",
+		StartPosition: 64,
+		EndPosition:   67,
+	}})
+}
+
+func TestPipeTypes(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function double(value: number): number {
+	return value * 2;
+}
+
+var a: number = 1 |> double |> double;
+var b = "a" |> double;
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, argument value of function double expects number, but received string",
+		StartPosition: 111,
+		EndPosition:   113,
+	}})
+}
+
+func TestDecoratedFunctionTypes(t *testing.T) {
+	var diagnostics = checkCode(t, `
+function double(value: number): number {
+	return value * 2;
+}
+
+@memo
+function half(value: number): number {
+	return value / 2;
+}
+
+var a = half("a");
+var b = double("a");
+var c: function = trace(double);
+`)
+
+	expectDiagnostics(t, diagnostics, []parser_error.ParserError{{
+		Message:       "Type error, argument value of function double expects number, but received string",
+		StartPosition: 165,
+		EndPosition:   167,
+	}})
+}
+
+func TestIsAssignable(t *testing.T) {
+	var cases = []struct {
+		expected string
+		actual   string
+		result   bool
+	}{
+		{TYPE_NUMBER, TYPE_NUMBER, true},
+		{TYPE_NUMBER, TYPE_INTEGER, false},
+		{TYPE_ANY, TYPE_STRING, true},
+		{TYPE_STRING, TYPE_ANY, true},
+		{TYPE_FUNCTION, TYPE_NATIVE_FUNCTION, true},
+		{TYPE_NATIVE_FUNCTION, TYPE_FUNCTION, false},
+	}
+
+	for _, testCase := range cases {
+		if IsAssignable(testCase.expected, testCase.actual) != testCase.result {
+			t.Errorf("Wrong result for %s and %s", testCase.expected, testCase.actual)
+		}
+	}
+}
+
+func checkCode(t *testing.T, code string) []parser_error.ParserError {
+	var src = source_mock.GetSourceMock(code)
+	var stdout = stdout_mock.CreateStdout()
+	var parser = parser.CreateParser(src, &stdout)
+	var astRoot, astError = parser.Parse(false)
+
+	if astError != nil {
+		t.Fatalf("Parsing failed: %s", astError.Error())
+	}
+
+	return Check(astRoot)
+}
+
+func expectDiagnostics(t *testing.T, received []parser_error.ParserError, expected []parser_error.ParserError) {
+	if len(received) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, but received %d: %v", len(expected), len(received), received)
+	}
+
+	for index, diagnostic := range received {
+		if diagnostic != expected[index] {
+			t.Errorf(
+				"Expected diagnostic %q at %d-%d, but received %q at %d-%d",
+				expected[index].Message, expected[index].StartPosition, expected[index].EndPosition,
+				diagnostic.Message, diagnostic.StartPosition, diagnostic.EndPosition,
+			)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package type_checker
+
+// Type names, same as results of "typeof"
+const TYPE_NUMBER = "number"
+const TYPE_INTEGER = "integer"
+const TYPE_STRING = "string"
+const TYPE_BOOLEAN = "boolean"
+const TYPE_FUNCTION = "function"
+const TYPE_NATIVE_FUNCTION = "native_function"
+const TYPE_GENERATOR = "generator"
+const TYPE_PROMISE = "promise"
+const TYPE_CHANNEL = "channel"
+const TYPE_MAP = "map"
+const TYPE_SET = "set"
+const TYPE_ENTRY = "entry"
+const TYPE_ENUM = "enum"
+const TYPE_ENUM_MEMBER = "enum_member"
+const TYPE_UNKNOWN = "unknown"
+
+// Dynamic type, value of any type fits it, and it fits any type
+const TYPE_ANY = "any"
+
+// Type of expression, which depends on variable with not inferred yet type
+const typeNone = ""
+
+var knownTypes = map[string]bool{
+	TYPE_NUMBER:          true,
+	TYPE_INTEGER:         true,
+	TYPE_STRING:          true,
+	TYPE_BOOLEAN:         true,
+	TYPE_FUNCTION:        true,
+	TYPE_NATIVE_FUNCTION: true,
+	TYPE_GENERATOR:       true,
+	TYPE_PROMISE:         true,
+	TYPE_CHANNEL:         true,
+	TYPE_MAP:             true,
+	TYPE_SET:             true,
+	TYPE_ENTRY:           true,
+	TYPE_ENUM:            true,
+	TYPE_ENUM_MEMBER:     true,
+	TYPE_UNKNOWN:         true,
+	TYPE_ANY:             true,
+}
+
+// Results of build in functions, which don't depend on arguments. Other build in functions return dynamic type
+var nativeResultTypes = map[string]string{
+	"isNumber":    TYPE_BOOLEAN,
+	"isString":    TYPE_BOOLEAN,
+	"isInteger":   TYPE_BOOLEAN,
+	"isBoolean":   TYPE_BOOLEAN,
+	"isFunction":  TYPE_BOOLEAN,
+	"isNative":    TYPE_BOOLEAN,
+	"isMap":       TYPE_BOOLEAN,
+	"isSet":       TYPE_BOOLEAN,
+	"isUnknown":   TYPE_BOOLEAN,
+	"toInteger":   TYPE_INTEGER,
+	"toNumber":    TYPE_NUMBER,
+	"fnName":      TYPE_STRING,
+	"fnArity":     TYPE_NUMBER,
+	"setTimeout":  TYPE_NUMBER,
+	"setInterval": TYPE_NUMBER,
+	"sleep":       TYPE_PROMISE,
+	"now":         TYPE_NUMBER,
+	"Map":         TYPE_MAP,
+	"Set":         TYPE_SET,
+	"chan":        TYPE_CHANNEL,
+	"memo":        TYPE_NATIVE_FUNCTION,
+	"trace":       TYPE_NATIVE_FUNCTION,
+}
+
+func IsKnownType(name string) bool {
+	return knownTypes[name]
+}
+
+// Checks, that value of actual type can be stored in place of expected type
+func IsAssignable(expected string, actual string) bool {
+	if expected == TYPE_ANY || actual == TYPE_ANY || actual == typeNone {
+		return true
+	}
+
+	// Build in functions are functions too
+	if expected == TYPE_FUNCTION && actual == TYPE_NATIVE_FUNCTION {
+		return true
+	}
+
+	return expected == actual
+}
+
+// Message of type mismatch, subject is like "variable a"
+func CreateMismatchMessage(subject string, expected string, actual string) string {
+	return "Type error, " + subject + " expects " + expected + ", but received " + actual
+}
+
+// Message of annotation with not existing type name, same for checker and runtime
+func CreateUnknownTypeMessage(name string) string {
+	return "Unknown type: " + name
+}
+
+func isNumericType(typeName string) bool {
+	return typeName == TYPE_NUMBER || typeName == TYPE_INTEGER
+}
+
+// Common type of two values. Different types are joined into dynamic one
+func joinTypes(first string, second string) string {
+	if first == typeNone {
+		return second
+	}
+
+	if second == typeNone || first == second {
+		return first
+	}
+
+	return TYPE_ANY
+}